@@ -0,0 +1,168 @@
+// Package auth identifies who a request or call is made by, and what their Role lets them do,
+// for every transport to authenticate the same credentials the same way
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Authenticator identifies who made a request from the credentials it carries,
+// an HTTP request's headers or a gRPC call's metadata
+// It returns ErrNoCredentials if they don't include its kind of credentials,
+// so that another Authenticator can be tried, or ErrInvalidCredentials if they're wrong
+type Authenticator interface {
+	Authenticate(credentials http.Header) (Identity, error)
+}
+
+// Identity is who a request has been authenticated as, and what they may do
+type Identity struct {
+	UserID string
+	Role   Role
+}
+
+// ErrNoCredentials & ErrInvalidCredentials are returned by Authenticators, see Authenticator
+var (
+	ErrNoCredentials      = errors.New("no credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authentication requires requests to be authenticated by one of the Authenticators, tried in order
+type Authentication struct {
+	Authenticators []Authenticator
+}
+
+// Authenticate returns ErrNoCredentials if no Authenticator recognised the credentials
+func (a *Authentication) Authenticate(credentials http.Header) (Identity, error) {
+	for _, authenticator := range a.Authenticators {
+		identity, err := authenticator.Authenticate(credentials)
+		if err != ErrNoCredentials {
+			return identity, err
+		}
+	}
+	return Identity{}, ErrNoCredentials
+}
+
+// APIKeyHeader carries the key APIKeys authenticate
+const APIKeyHeader = "X-API-Key"
+
+// APIKeys authenticates requests carrying a static API key in the X-API-Key header,
+// mapped to the user it belongs to
+type APIKeys map[string]Identity
+
+// Authenticate implements Authenticator
+func (k APIKeys) Authenticate(credentials http.Header) (Identity, error) {
+	key := credentials.Get(APIKeyHeader)
+	if key == "" {
+		return Identity{}, ErrNoCredentials
+	}
+
+	// compare against every key in constant time, so timing doesn't reveal a partial match
+	var found Identity
+	ok := false
+	for k, identity := range k {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			found, ok = identity, true
+		}
+	}
+
+	if !ok {
+		return Identity{}, ErrInvalidCredentials
+	}
+	return found, nil
+}
+
+// HMACJWT authenticates requests carrying an HS256 signed JWT bearer token,
+// the user is the token's "sub" claim, and "exp" & "nbf" are honoured if present
+// The "role" claim names the user's Role, they're a viewer without one
+type HMACJWT struct {
+	Secret []byte
+
+	// now is swapped out in tests
+	now func() time.Time
+}
+
+// NewHMACJWT returns an HMACJWT pointer verifying tokens signed with secret
+func NewHMACJWT(secret []byte) *HMACJWT {
+	return &HMACJWT{Secret: secret, now: time.Now}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt *int64 `json:"exp"`
+	NotBefore *int64 `json:"nbf"`
+	Role      string `json:"role"`
+}
+
+// Authenticate implements Authenticator
+func (j *HMACJWT) Authenticate(credentials http.Header) (Identity, error) {
+	authorization := credentials.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return Identity{}, ErrNoCredentials
+	}
+	token := strings.TrimPrefix(authorization, "Bearer ")
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, ErrInvalidCredentials
+	}
+
+	var header jwtHeader
+	if !decodeJWTPart(parts[0], &header) || header.Alg != "HS256" {
+		// only ever accept the algorithm we sign with, never "none"
+		return Identity{}, ErrInvalidCredentials
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, j.sign(parts[0]+"."+parts[1])) {
+		return Identity{}, ErrInvalidCredentials
+	}
+
+	var claims jwtClaims
+	if !decodeJWTPart(parts[1], &claims) || claims.Subject == "" {
+		return Identity{}, ErrInvalidCredentials
+	}
+
+	now := j.now().Unix()
+	if claims.ExpiresAt != nil && now >= *claims.ExpiresAt {
+		return Identity{}, ErrInvalidCredentials
+	}
+	if claims.NotBefore != nil && now < *claims.NotBefore {
+		return Identity{}, ErrInvalidCredentials
+	}
+
+	role := RoleViewer
+	if claims.Role != "" {
+		var ok bool
+		if role, ok = ParseRole(claims.Role); !ok {
+			return Identity{}, ErrInvalidCredentials
+		}
+	}
+
+	return Identity{UserID: claims.Subject, Role: role}, nil
+}
+
+func (j *HMACJWT) sign(signingInput string) []byte {
+	mac := hmac.New(sha256.New, j.Secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func decodeJWTPart(part string, v interface{}) bool {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var testJWTSecret = []byte("not-a-real-secret")
+
+func signTestJWT(t *testing.T, secret []byte, header, claims interface{}) string {
+	t.Helper()
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signingInput := encode(header) + "." + encode(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func credentialsWith(key, value string) http.Header {
+	credentials := http.Header{}
+	credentials.Set(key, value)
+	return credentials
+}
+
+func TestAPIKeys(t *testing.T) {
+	keys := APIKeys{"alices-key": {UserID: "alice", Role: RoleEditor}}
+
+	t.Run("a known key is its user", func(t *testing.T) {
+		identity, err := keys.Authenticate(credentialsWith(APIKeyHeader, "alices-key"))
+		assertNoError(t, err)
+		assertIdentity(t, identity, Identity{UserID: "alice", Role: RoleEditor})
+	})
+
+	t.Run("an unknown key is invalid", func(t *testing.T) {
+		_, err := keys.Authenticate(credentialsWith(APIKeyHeader, "bobs-key"))
+		assertError(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("no key is no credentials", func(t *testing.T) {
+		_, err := keys.Authenticate(http.Header{})
+		assertError(t, err, ErrNoCredentials)
+	})
+}
+
+func TestHMACJWT(t *testing.T) {
+	jwt := NewHMACJWT(testJWTSecret)
+	jwt.now = func() time.Time { return time.Unix(1500000000, 0) }
+
+	hs256 := map[string]string{"alg": "HS256", "typ": "JWT"}
+	bearer := func(token string) http.Header {
+		return credentialsWith("Authorization", "Bearer "+token)
+	}
+
+	t.Run("invalid tokens", func(t *testing.T) {
+		cases := map[string]string{
+			"signed with another secret": signTestJWT(t, []byte("wrong"), hs256, map[string]interface{}{"sub": "alice"}),
+			"unsigned":                   signTestJWT(t, testJWTSecret, map[string]string{"alg": "none"}, map[string]interface{}{"sub": "alice"}),
+			"expired":                    signTestJWT(t, testJWTSecret, hs256, map[string]interface{}{"sub": "alice", "exp": 1400000000}),
+			"not yet valid":              signTestJWT(t, testJWTSecret, hs256, map[string]interface{}{"sub": "alice", "nbf": 1600000000}),
+			"without a subject":          signTestJWT(t, testJWTSecret, hs256, map[string]interface{}{}),
+			"with an unknown role":       signTestJWT(t, testJWTSecret, hs256, map[string]interface{}{"sub": "alice", "role": "owner"}),
+			"malformed":                  "abc.def",
+		}
+
+		for name, token := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := jwt.Authenticate(bearer(token))
+				assertError(t, err, ErrInvalidCredentials)
+			})
+		}
+	})
+
+	t.Run("the subject is the user, a viewer without a role claim", func(t *testing.T) {
+		identity, err := jwt.Authenticate(bearer(signTestJWT(t, testJWTSecret, hs256, map[string]interface{}{"sub": "alice", "exp": 1600000000})))
+		assertNoError(t, err)
+		assertIdentity(t, identity, Identity{UserID: "alice", Role: RoleViewer})
+	})
+
+	t.Run("the role claim is the user's role", func(t *testing.T) {
+		identity, err := jwt.Authenticate(bearer(signTestJWT(t, testJWTSecret, hs256, map[string]interface{}{"sub": "alice", "role": "editor"})))
+		assertNoError(t, err)
+		assertIdentity(t, identity, Identity{UserID: "alice", Role: RoleEditor})
+	})
+
+	t.Run("no bearer token is no credentials", func(t *testing.T) {
+		_, err := jwt.Authenticate(credentialsWith("Authorization", "Basic YWxpY2U6"))
+		assertError(t, err, ErrNoCredentials)
+	})
+}
+
+func TestAuthentication(t *testing.T) {
+	authentication := &Authentication{Authenticators: []Authenticator{
+		APIKeys{"alices-key": {UserID: "alice"}},
+		NewHMACJWT(testJWTSecret),
+	}}
+
+	t.Run("the first Authenticator recognising the credentials decides", func(t *testing.T) {
+		_, err := authentication.Authenticate(credentialsWith("Authorization", "Bearer abc.def"))
+		assertError(t, err, ErrInvalidCredentials)
+
+		identity, err := authentication.Authenticate(credentialsWith(APIKeyHeader, "alices-key"))
+		assertNoError(t, err)
+		assertIdentity(t, identity, Identity{UserID: "alice"})
+	})
+
+	t.Run("credentials none recognise are no credentials", func(t *testing.T) {
+		_, err := authentication.Authenticate(credentialsWith("Cookie", "session=abc"))
+		assertError(t, err, ErrNoCredentials)
+	})
+}
+
+func assertIdentity(t *testing.T, got, want Identity) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %+v wanted %+v", got, want)
+	}
+}
+
+func assertError(t *testing.T, got, want error) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got error %v wanted %v", got, want)
+	}
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package auth
+
+// The titles of the problems a transport reports when a request can't be authenticated,
+// or its user isn't permitted to make it
+const (
+	ErrorAuthenticationRequired = "authentication required"
+	ErrorInvalidCredentials     = "credentials are invalid"
+	ErrorForbiddenUser          = "cannot act for another user"
+	ErrorForbiddenRole          = "role does not permit this"
+)
+
+// errorCodes gives each error above a stable, machine-readable code, as internal's errors have
+var errorCodes = map[string]string{
+	ErrorAuthenticationRequired: "authentication_required",
+	ErrorInvalidCredentials:     "invalid_credentials",
+	ErrorForbiddenUser:          "forbidden_user",
+	ErrorForbiddenRole:          "forbidden_role",
+}
+
+// ErrorCode returns the code for an error title above, and false if it has none
+func ErrorCode(title string) (string, bool) {
+	code, ok := errorCodes[title]
+	return code, ok
+}
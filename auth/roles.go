@@ -0,0 +1,35 @@
+package auth
+
+// Role is what an authenticated user is permitted to do,
+// each role can do everything the roles before it can
+type Role int
+
+const (
+	// RoleViewer can only read
+	RoleViewer Role = iota
+	// RoleEditor can also add, change & remove transactions, and add & change categories & questions
+	RoleEditor
+	// RoleAdmin can also remove categories & questions, restructuring the tree
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleViewer: "viewer",
+	RoleEditor: "editor",
+	RoleAdmin:  "admin",
+}
+
+func (r Role) String() string {
+	return roleNames[r]
+}
+
+// ParseRole returns the Role named name,
+// and false for any other name
+func ParseRole(name string) (Role, bool) {
+	for role, roleName := range roleNames {
+		if name == roleName {
+			return role, true
+		}
+	}
+	return RoleViewer, false
+}
@@ -0,0 +1,16 @@
+package auth
+
+import "testing"
+
+func TestParseRole(t *testing.T) {
+	for _, role := range []Role{RoleViewer, RoleEditor, RoleAdmin} {
+		got, ok := ParseRole(role.String())
+		if !ok || got != role {
+			t.Errorf("got %v, %t parsing %q", got, ok, role.String())
+		}
+	}
+
+	if _, ok := ParseRole("owner"); ok {
+		t.Error(`expected "owner" not to parse`)
+	}
+}
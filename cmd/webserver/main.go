@@ -1,14 +1,26 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jgillard/practising-go-tdd/auth"
+	grpctransport "github.com/jgillard/practising-go-tdd/grpc"
 	httptransport "github.com/jgillard/practising-go-tdd/http"
 	internal "github.com/jgillard/practising-go-tdd/internal"
+	"github.com/jgillard/practising-go-tdd/monzo"
 )
 
+// monzoSyncInterval is how often transactions are pulled from Monzo
+const monzoSyncInterval = 15 * time.Minute
+
 func main() {
 
 	port := os.Getenv("PORT")
@@ -17,12 +29,220 @@ func main() {
 		log.Fatal("$PORT must be set")
 	}
 
-	categoryStore := internal.NewInMemoryCategoryStore(nil)
-	questionStore := internal.NewInMemoryQuestionStore(nil)
+	var categoryStore internal.CategoryStore
+	var questionStore internal.QuestionStore
+	var transactionStore internal.TransactionStore
+
+	// $SQLITE_PATH selects the SQLite stores, $DATA_DIR the file-backed stores,
+	// otherwise nothing survives a restart
+	sqlitePath := os.Getenv("SQLITE_PATH")
+	dataDir := os.Getenv("DATA_DIR")
+
+	switch {
+	case sqlitePath != "":
+		db, err := internal.NewSQLiteDB(sqlitePath)
+		if err != nil {
+			log.Fatalf("could not open database %s %v", sqlitePath, err)
+		}
+		defer db.Close()
+
+		categoryStore = internal.NewSQLiteCategoryStore(db)
+		questionStore = internal.NewSQLiteQuestionStore(db)
+		transactionStore = internal.NewSQLiteTransactionStore(db)
+	case dataDir != "":
+		var err error
+
+		categoryStore, err = internal.NewFileCategoryStore(filepath.Join(dataDir, "categories.json"))
+		if err != nil {
+			log.Fatalf("could not load categories from %s %v", dataDir, err)
+		}
+
+		questionStore, err = internal.NewFileQuestionStore(filepath.Join(dataDir, "questions.json"))
+		if err != nil {
+			log.Fatalf("could not load questions from %s %v", dataDir, err)
+		}
+
+		transactionStore, err = internal.NewFileTransactionStore(filepath.Join(dataDir, "transactions.json"))
+		if err != nil {
+			log.Fatalf("could not load transactions from %s %v", dataDir, err)
+		}
+	default:
+		categoryStore = internal.NewInMemoryCategoryStore(nil)
+		questionStore = internal.NewInMemoryQuestionStore(nil)
+		transactionStore = internal.NewInMemoryTransactionStore(nil)
+	}
+
+	// Monzo transactions, synced or received by webhook, are categorised
+	// using the JSON mapping file at $MONZO_CATEGORY_MAPPING if set
+	mapping := loadMonzoCategoryMapping()
+
+	server := httptransport.NewServer(categoryStore, questionStore, transactionStore)
+	server.SetMonzoCategoryMapping(mapping)
+
+	// $MAX_CATEGORY_DEPTH is how deeply categories may be nested (0 for no subcategories),
+	// or "unlimited", otherwise only one level of subcategories is allowed
+	if maxDepth := os.Getenv("MAX_CATEGORY_DEPTH"); maxDepth != "" {
+		server.Service().SetMaxCategoryDepth(parseMaxCategoryDepth(maxDepth))
+	}
+
+	// $API_KEYS ("key=userID:role,...") and/or $JWT_SECRET require every request
+	// other than /status to be authenticated, otherwise anyone who can reach $PORT can do anything
+	authentication := newAuthentication()
+	if authentication != nil {
+		server.SetAuthentication(authentication, "/status")
+	}
+
+	// $GRPC_PORT also serves categories & questions over gRPC,
+	// sharing the HTTP server's service & authentication
+	if grpcPort := os.Getenv("GRPC_PORT"); grpcPort != "" {
+		grpcServer := grpctransport.NewServer(server.Service())
+		if authentication != nil {
+			grpcServer.SetAuthentication(authentication)
+		}
+		go serveGRPC(grpcServer, grpcPort)
+	}
+
+	// $MONZO_ACCESS_TOKEN turns on syncing transactions from $MONZO_ACCOUNT_ID,
+	// stored for the user $MONZO_USER_ID (the shared user if unset)
+	// and, with $MONZO_EXPORT set, writing categorisations back to them as metadata
+	// ($MONZO_EXPORT=dry-run only logs what would be written)
+	if accessToken := os.Getenv("MONZO_ACCESS_TOKEN"); accessToken != "" {
+		client := newMonzoClient(accessToken)
+
+		importer := monzo.NewImporter(client, server.Service(), mapping)
+		go syncMonzo(internal.WithUser(context.Background(), os.Getenv("MONZO_USER_ID")), importer, os.Getenv("MONZO_ACCOUNT_ID"))
 
-	server := httptransport.NewServer(categoryStore, questionStore)
+		if export := os.Getenv("MONZO_EXPORT"); export != "" {
+			exporter := monzo.NewExporter(client)
+			exporter.DryRun = export == "dry-run"
+			server.OnTransactionCategorised(func(previous, t internal.Transaction) {
+				go func() {
+					if err := exporter.Export(context.Background(), previous, t); err != nil {
+						log.Printf("monzo export of %s failed %v", t.ID, err)
+					}
+				}()
+			})
+		}
+	}
 
 	if err := http.ListenAndServe(":"+port, server); err != nil {
 		log.Fatalf("could not listen on port %s %v", port, err)
 	}
 }
+
+func serveGRPC(server *grpctransport.Server, port string) {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Fatalf("could not listen on gRPC port %s %v", port, err)
+	}
+
+	if err := server.GRPCServer().Serve(listener); err != nil {
+		log.Fatalf("could not serve gRPC on port %s %v", port, err)
+	}
+}
+
+func newAuthentication() *auth.Authentication {
+	var authenticators []auth.Authenticator
+
+	if apiKeys := os.Getenv("API_KEYS"); apiKeys != "" {
+		keys := auth.APIKeys{}
+		for _, pair := range strings.Split(apiKeys, ",") {
+			key, identity, ok := parseAPIKey(pair)
+			if !ok {
+				log.Fatal(`$API_KEYS must be a comma separated list of "key=userID:role", role being viewer, editor or admin`)
+			}
+			keys[key] = identity
+		}
+		authenticators = append(authenticators, keys)
+	}
+
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		authenticators = append(authenticators, auth.NewHMACJWT([]byte(secret)))
+	}
+
+	if len(authenticators) == 0 {
+		return nil
+	}
+
+	return &auth.Authentication{Authenticators: authenticators}
+}
+
+// parseAPIKey parses "key=userID:role"
+func parseAPIKey(s string) (string, auth.Identity, bool) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", auth.Identity{}, false
+	}
+
+	user := strings.SplitN(parts[1], ":", 2)
+	if len(user) != 2 {
+		return "", auth.Identity{}, false
+	}
+
+	role, ok := auth.ParseRole(user[1])
+	if !ok {
+		return "", auth.Identity{}, false
+	}
+
+	return parts[0], auth.Identity{UserID: user[0], Role: role}, true
+}
+
+func parseMaxCategoryDepth(s string) int {
+	if s == "unlimited" {
+		return internal.UnlimitedCategoryDepth
+	}
+
+	depth, err := strconv.Atoi(s)
+	if err != nil || depth < 0 {
+		log.Fatal(`$MAX_CATEGORY_DEPTH must be a number of levels from 0, or "unlimited"`)
+	}
+	return depth
+}
+
+func loadMonzoCategoryMapping() monzo.CategoryMapping {
+	mappingPath := os.Getenv("MONZO_CATEGORY_MAPPING")
+	if mappingPath == "" {
+		return nil
+	}
+
+	mapping, err := monzo.LoadCategoryMapping(mappingPath)
+	if err != nil {
+		log.Fatalf("could not load Monzo category mapping %s %v", mappingPath, err)
+	}
+
+	return mapping
+}
+
+func newMonzoClient(accessToken string) *monzo.Client {
+	if os.Getenv("MONZO_ACCOUNT_ID") == "" {
+		log.Fatal("$MONZO_ACCOUNT_ID must be set with $MONZO_ACCESS_TOKEN")
+	}
+
+	baseURL := os.Getenv("MONZO_API_URL")
+	if baseURL == "" {
+		baseURL = monzo.DefaultBaseURL
+	}
+
+	return monzo.NewClient(baseURL, accessToken)
+}
+
+// syncMonzo imports everything once, then keeps re-importing recent transactions
+// Already imported transactions are skipped, so overlapping windows are harmless
+func syncMonzo(ctx context.Context, importer *monzo.Importer, accountID string) {
+	since := ""
+
+	for {
+		started := time.Now()
+
+		result, err := importer.Import(ctx, accountID, since, "")
+		if err != nil {
+			log.Printf("monzo sync failed %v", err)
+		} else {
+			log.Printf("monzo sync imported %d skipped %d", result.Imported, result.Skipped)
+			// Monzo can settle transactions a little after they're created
+			since = started.Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+		}
+
+		time.Sleep(monzoSyncInterval)
+	}
+}
@@ -0,0 +1,174 @@
+package grpctransport
+
+import (
+	"github.com/jgillard/practising-go-tdd/grpc/pb"
+	internal "github.com/jgillard/practising-go-tdd/internal"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// the domain types converted to their protobuf messages
+
+func categoryToPB(c internal.Category) *pb.Category {
+	return &pb.Category{
+		Id:       c.ID,
+		Name:     c.Name,
+		ParentId: c.ParentID,
+		Owner:    c.Owner,
+	}
+}
+
+func categoriesToPB(categories []internal.Category) []*pb.Category {
+	var converted []*pb.Category
+	for _, c := range categories {
+		converted = append(converted, categoryToPB(c))
+	}
+	return converted
+}
+
+func categoryTreeToPB(t internal.CategoryTree) *pb.CategoryTree {
+	tree := &pb.CategoryTree{Category: categoryToPB(t.Category)}
+	for _, child := range t.Children {
+		tree.Children = append(tree.Children, categoryTreeToPB(child))
+	}
+	return tree
+}
+
+func questionToPB(q internal.Question) *pb.Question {
+	question := &pb.Question{
+		Id:          q.ID,
+		Title:       q.Title,
+		CategoryId:  q.CategoryID,
+		Type:        q.Type,
+		Owner:       q.Owner,
+		Overrides:   q.Overrides,
+		HiddenIn:    q.HiddenIn,
+		Constraints: constraintsToPB(q.Constraints),
+		DependsOn:   dependencyToPB(q.DependsOn),
+	}
+	for _, o := range q.Options {
+		question.Options = append(question.Options, optionToPB(o))
+	}
+	return question
+}
+
+func constraintsToPB(c *internal.Constraints) *pb.Constraints {
+	if c == nil {
+		return nil
+	}
+
+	constraints := &pb.Constraints{
+		Required: c.Required,
+		Min:      c.Min,
+		Max:      c.Max,
+		Step:     c.Step,
+		Unit:     c.Unit,
+	}
+	if c.MaxLength != nil {
+		maxLength := int32(*c.MaxLength)
+		constraints.MaxLength = &maxLength
+	}
+	if c.Default != nil {
+		// defaults were decoded from JSON or a Value, so are always convertible back
+		constraints.Default, _ = structpb.NewValue(c.Default)
+	}
+	return constraints
+}
+
+func optionToPB(o internal.Option) *pb.Option {
+	return &pb.Option{Id: o.ID, Title: o.Title}
+}
+
+func dependencyToPB(d *internal.Dependency) *pb.Dependency {
+	if d == nil {
+		return nil
+	}
+
+	return &pb.Dependency{
+		QuestionId: d.QuestionID,
+		OptionId:   d.OptionID,
+		Min:        d.Min,
+		Max:        d.Max,
+		Equals:     d.Equals,
+	}
+}
+
+func removedToPB(r internal.Removed) *pb.Removed {
+	return &pb.Removed{
+		Categories:             r.Categories,
+		Questions:              r.Questions,
+		ReassignedTransactions: int32(r.ReassignedTransactions),
+	}
+}
+
+// questionPostRequestFromPB keeps options being absent apart from them being empty,
+// as the HTTP API does
+func questionPostRequestFromPB(req *pb.AddQuestionRequest) internal.QuestionPostRequest {
+	question := internal.QuestionPostRequest{
+		Title:     req.GetTitle(),
+		Type:      req.GetType(),
+		Overrides: req.GetOverrides(),
+	}
+	if req.GetOptions() != nil {
+		options := append([]string{}, req.GetOptions().GetTitles()...)
+		question.Options = &options
+	}
+	question.Constraints = constraintsFromPB(req.GetConstraints())
+	question.DependsOn = dependencyFromPB(req.GetDependsOn())
+	return question
+}
+
+// questionPatchRequestFromPB leaves out whatever the request doesn't change, as the HTTP API does
+func questionPatchRequestFromPB(req *pb.UpdateQuestionRequest) internal.QuestionPatchRequest {
+	return internal.QuestionPatchRequest{
+		Title:       req.Title,
+		Constraints: constraintsFromPB(req.GetConstraints()),
+		DependsOn:   dependencyFromPB(req.GetDependsOn()),
+	}
+}
+
+// constraintsFromPB decodes the default as JSON would be, e.g. numbers as float64
+func constraintsFromPB(c *pb.Constraints) *internal.Constraints {
+	if c == nil {
+		return nil
+	}
+
+	constraints := &internal.Constraints{
+		Required: c.GetRequired(),
+		Min:      c.Min,
+		Max:      c.Max,
+		Step:     c.Step,
+		Unit:     c.GetUnit(),
+	}
+	if c.MaxLength != nil {
+		maxLength := int(c.GetMaxLength())
+		constraints.MaxLength = &maxLength
+	}
+	if c.GetDefault() != nil {
+		constraints.Default = c.GetDefault().AsInterface()
+	}
+	return constraints
+}
+
+func dependencyFromPB(d *pb.Dependency) *internal.Dependency {
+	if d == nil {
+		return nil
+	}
+
+	return &internal.Dependency{
+		QuestionID: d.GetQuestionId(),
+		OptionID:   d.GetOptionId(),
+		Min:        d.Min,
+		Max:        d.Max,
+		Equals:     d.Equals,
+	}
+}
+
+// answersFromPB decodes each value as JSON would be, e.g. numbers as float64
+func answersFromPB(answers []*pb.Answer) []internal.Answer {
+	converted := []internal.Answer{}
+	for _, a := range answers {
+		converted = append(converted, internal.Answer{QuestionID: a.GetQuestionId(), Value: a.GetValue().AsInterface()})
+	}
+	return converted
+}
@@ -0,0 +1,81 @@
+package grpctransport
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorInternal is the message of an Internal status, which hides what went wrong from the caller
+const errorInternal = "internal server error"
+
+// errorDomain is the ErrorInfo domain the reasons (internal.ErrorCode) of errors belong to
+const errorDomain = "practising-go-tdd"
+
+// serviceError converts an error returned by the service to a status,
+// with the same titles & codes as the HTTP API's problems
+// Validation problems are InvalidArgument with a BadRequest detail listing every one,
+// keyed by the same pointers, e.g. "/options/1"
+func serviceError(err error) error {
+	var fieldErrs internal.FieldErrors
+	var inUseErr *internal.InUseError
+	var storeErr *internal.StoreError
+
+	switch {
+	case errors.As(err, &fieldErrs):
+		badRequest := &errdetails.BadRequest{}
+		for _, e := range fieldErrs {
+			code, _ := internal.ErrorCode(e.Title)
+			description := e.Title
+			if e.Detail != "" {
+				description += ": " + e.Detail
+			}
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       e.Pointer,
+				Description: description,
+				Reason:      code,
+			})
+		}
+		return withDetails(status.New(codes.InvalidArgument, fieldErrs.Error()), badRequest)
+	case errors.As(err, &inUseErr):
+		fmt.Println(inUseErr.Title)
+		return withDetails(status.New(codes.FailedPrecondition, inUseErr.Title), errorInfo(inUseErr.Title, map[string]string{
+			"categories":   strconv.Itoa(inUseErr.Categories),
+			"transactions": strconv.Itoa(inUseErr.Transactions),
+			"questions":    strconv.Itoa(inUseErr.Questions),
+		}))
+	case errors.As(err, &storeErr) && errors.Is(err, internal.ErrNotFound):
+		fmt.Println(storeErr.Title)
+		return withDetails(status.New(codes.NotFound, storeErr.Title), errorInfo(storeErr.Title, nil))
+	case errors.As(err, &storeErr) && errors.Is(err, internal.ErrConflict):
+		fmt.Println(storeErr.Title)
+		return withDetails(status.New(codes.AlreadyExists, storeErr.Title), errorInfo(storeErr.Title, nil))
+	default:
+		// the store failing, which the caller can do nothing about
+		log.Printf("store error %v", err)
+		return status.Error(codes.Internal, errorInternal)
+	}
+}
+
+func errorInfo(title string, metadata map[string]string) *errdetails.ErrorInfo {
+	code, _ := internal.ErrorCode(title)
+	return &errdetails.ErrorInfo{Reason: code, Domain: errorDomain, Metadata: metadata}
+}
+
+// withDetails returns st with the details attached,
+// or without them if they can't be, as the status itself is still right
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,80 @@
+package grpctransport
+
+import (
+	"context"
+
+	"github.com/jgillard/practising-go-tdd/grpc/pb"
+)
+
+// ListCategories implements pb.CategoriesServer
+func (s *Server) ListCategories(ctx context.Context, _ *pb.ListCategoriesRequest) (*pb.CategoryList, error) {
+	categoryList, err := s.service.ListCategories(ctx)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &pb.CategoryList{Categories: categoriesToPB(categoryList.Categories)}, nil
+}
+
+// GetCategory implements pb.CategoriesServer
+func (s *Server) GetCategory(ctx context.Context, req *pb.GetCategoryRequest) (*pb.GetCategoryResponse, error) {
+	category, children, err := s.service.GetCategory(ctx, req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &pb.GetCategoryResponse{
+		Category: categoryToPB(category),
+		Children: categoriesToPB(children),
+	}, nil
+}
+
+// GetCategoryTree implements pb.CategoriesServer
+func (s *Server) GetCategoryTree(ctx context.Context, req *pb.GetCategoryRequest) (*pb.CategoryTree, error) {
+	tree, err := s.service.GetCategoryTree(ctx, req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return categoryTreeToPB(tree), nil
+}
+
+// AddCategory implements pb.CategoriesServer
+func (s *Server) AddCategory(ctx context.Context, req *pb.AddCategoryRequest) (*pb.Category, error) {
+	category, err := s.service.AddCategory(ctx, req.GetName(), req.ParentId)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return categoryToPB(category), nil
+}
+
+// RenameCategory implements pb.CategoriesServer
+func (s *Server) RenameCategory(ctx context.Context, req *pb.RenameCategoryRequest) (*pb.Category, error) {
+	category, err := s.service.RenameCategory(ctx, req.GetId(), req.GetName())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return categoryToPB(category), nil
+}
+
+// MoveCategory implements pb.CategoriesServer
+func (s *Server) MoveCategory(ctx context.Context, req *pb.MoveCategoryRequest) (*pb.Category, error) {
+	category, err := s.service.MoveCategory(ctx, req.GetId(), req.ParentId)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return categoryToPB(category), nil
+}
+
+// RemoveCategory implements pb.CategoriesServer
+func (s *Server) RemoveCategory(ctx context.Context, req *pb.RemoveCategoryRequest) (*pb.Removed, error) {
+	removed, err := s.service.RemoveCategory(ctx, req.GetId(), req.GetCascade(), req.GetReassignTo())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return removedToPB(removed), nil
+}
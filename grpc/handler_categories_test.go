@@ -0,0 +1,122 @@
+package grpctransport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jgillard/practising-go-tdd/grpc/pb"
+	internal "github.com/jgillard/practising-go-tdd/internal"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+var ctx = context.Background()
+
+func TestCategories(t *testing.T) {
+
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+			internal.Category{ID: "5678", Name: "hotel", ParentID: "1234"},
+		},
+	}
+
+	t.Run("list & get", func(t *testing.T) {
+		list := categoryList
+		client, stop := newTestClient(t, newTestServer(&list, nil, nil))
+		defer stop()
+
+		categories, err := client.ListCategories(ctx, &pb.ListCategoriesRequest{})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(categories.GetCategories()), 2)
+
+		got, err := client.GetCategory(ctx, &pb.GetCategoryRequest{Id: "1234"})
+		assertNoError(t, err)
+		assertStringsEqual(t, got.GetCategory().GetName(), "accommodation")
+		assertNumbersEqual(t, len(got.GetChildren()), 1)
+		assertStringsEqual(t, got.GetChildren()[0].GetId(), "5678")
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		list := categoryList
+		client, stop := newTestClient(t, newTestServer(&list, nil, nil))
+		defer stop()
+
+		tree, err := client.GetCategoryTree(ctx, &pb.GetCategoryRequest{Id: "1234"})
+		assertNoError(t, err)
+		assertStringsEqual(t, tree.GetCategory().GetName(), "accommodation")
+		assertNumbersEqual(t, len(tree.GetChildren()), 1)
+		assertStringsEqual(t, tree.GetChildren()[0].GetCategory().GetName(), "hotel")
+		assertNumbersEqual(t, len(tree.GetChildren()[0].GetChildren()), 0)
+	})
+
+	t.Run("add, rename & remove", func(t *testing.T) {
+		list := categoryList
+		client, stop := newTestClient(t, newTestServer(&list, nil, nil))
+		defer stop()
+
+		added, err := client.AddCategory(ctx, &pb.AddCategoryRequest{Name: "hostel", ParentId: proto.String("1234")})
+		assertNoError(t, err)
+		assertStringsEqual(t, added.GetParentId(), "1234")
+
+		renamed, err := client.RenameCategory(ctx, &pb.RenameCategoryRequest{Id: added.GetId(), Name: "bunkhouse"})
+		assertNoError(t, err)
+		assertStringsEqual(t, renamed.GetName(), "bunkhouse")
+
+		moved, err := client.MoveCategory(ctx, &pb.MoveCategoryRequest{Id: "5678", ParentId: proto.String("")})
+		assertNoError(t, err)
+		assertStringsEqual(t, moved.GetParentId(), "")
+
+		_, err = client.MoveCategory(ctx, &pb.MoveCategoryRequest{Id: "1234", ParentId: proto.String(added.GetId())})
+		assertFieldViolations(t, err, []fieldViolation{{internal.ErrorParentIDIsDescendant, "/parentID"}})
+
+		removed, err := client.RemoveCategory(ctx, &pb.RemoveCategoryRequest{Id: "1234", Cascade: true})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(removed.GetCategories()), 2)
+
+		categories, err := client.ListCategories(ctx, &pb.ListCategoriesRequest{})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(categories.GetCategories()), 1)
+	})
+
+	t.Run("failures behave as over http", func(t *testing.T) {
+		list := categoryList
+		client, stop := newTestClient(t, newTestServer(&list, nil, nil))
+		defer stop()
+
+		t.Run("every field problem is reported at once", func(t *testing.T) {
+			_, err := client.AddCategory(ctx, &pb.AddCategoryRequest{Name: "!!!"})
+
+			assertFieldViolations(t, err, []fieldViolation{
+				{internal.ErrorFieldMissing, "/parentID"},
+				{internal.ErrorInvalidCategoryName, "/name"},
+			})
+		})
+
+		t.Run("name is a duplicate", func(t *testing.T) {
+			_, err := client.AddCategory(ctx, &pb.AddCategoryRequest{Name: "hotel", ParentId: proto.String("")})
+
+			assertStatus(t, err, codes.AlreadyExists, internal.ErrorDuplicateCategoryName)
+			assertErrorReason(t, err, internal.ErrorDuplicateCategoryName)
+		})
+
+		t.Run("category doesn't exist", func(t *testing.T) {
+			_, err := client.GetCategory(ctx, &pb.GetCategoryRequest{Id: "9999"})
+
+			assertStatus(t, err, codes.NotFound, internal.ErrorCategoryNotFound)
+			assertErrorReason(t, err, internal.ErrorCategoryNotFound)
+		})
+
+		t.Run("category has subcategories", func(t *testing.T) {
+			_, err := client.RemoveCategory(ctx, &pb.RemoveCategoryRequest{Id: "1234"})
+
+			assertStatus(t, err, codes.FailedPrecondition, internal.ErrorCategoryHasChildren)
+			assertErrorReason(t, err, internal.ErrorCategoryHasChildren)
+		})
+
+		categories, err := client.ListCategories(ctx, &pb.ListCategoriesRequest{})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(categories.GetCategories()), 2)
+	})
+}
@@ -0,0 +1,60 @@
+package grpctransport
+
+import (
+	"context"
+
+	"github.com/jgillard/practising-go-tdd/grpc/pb"
+)
+
+// ListOptions implements pb.CategoriesServer
+func (s *Server) ListOptions(ctx context.Context, req *pb.ListOptionsRequest) (*pb.OptionList, error) {
+	options, err := s.service.ListOptions(ctx, req.GetCategoryId(), req.GetQuestionId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	response := &pb.OptionList{}
+	for _, o := range options {
+		response.Options = append(response.Options, optionToPB(o))
+	}
+	return response, nil
+}
+
+// GetOption implements pb.CategoriesServer
+func (s *Server) GetOption(ctx context.Context, req *pb.GetOptionRequest) (*pb.Option, error) {
+	option, err := s.service.GetOption(ctx, req.GetCategoryId(), req.GetQuestionId(), req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return optionToPB(option), nil
+}
+
+// AddOption implements pb.CategoriesServer
+func (s *Server) AddOption(ctx context.Context, req *pb.AddOptionRequest) (*pb.Option, error) {
+	option, err := s.service.AddOption(ctx, req.GetCategoryId(), req.GetQuestionId(), req.GetTitle())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return optionToPB(option), nil
+}
+
+// RenameOption implements pb.CategoriesServer
+func (s *Server) RenameOption(ctx context.Context, req *pb.RenameOptionRequest) (*pb.Option, error) {
+	option, err := s.service.RenameOption(ctx, req.GetCategoryId(), req.GetQuestionId(), req.GetId(), req.GetTitle())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return optionToPB(option), nil
+}
+
+// RemoveOption implements pb.CategoriesServer
+func (s *Server) RemoveOption(ctx context.Context, req *pb.RemoveOptionRequest) (*pb.RemoveOptionResponse, error) {
+	if err := s.service.RemoveOption(ctx, req.GetCategoryId(), req.GetQuestionId(), req.GetId(), req.GetCascade()); err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &pb.RemoveOptionResponse{}, nil
+}
@@ -0,0 +1,98 @@
+package grpctransport
+
+import (
+	"testing"
+
+	"github.com/jgillard/practising-go-tdd/grpc/pb"
+	internal "github.com/jgillard/practising-go-tdd/internal"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestOptions(t *testing.T) {
+
+	newServer := func() *Server {
+		categoryList := internal.CategoryList{
+			Categories: []internal.Category{
+				internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+			},
+		}
+		questionList := internal.QuestionList{
+			Questions: []internal.Question{
+				internal.Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+				internal.Question{ID: "2", Title: "what kind?", CategoryID: "1234", Type: "string", Options: internal.OptionList{
+					{ID: "a", Title: "hotel"},
+				}},
+			},
+		}
+		transactionList := internal.TransactionList{
+			Transactions: []internal.Transaction{
+				internal.Transaction{ID: "abcdef", Amount: -4000, Currency: "GBP", CategoryID: "1234", Answers: []internal.Answer{
+					{QuestionID: "2", Value: "a"},
+				}},
+			},
+		}
+		return newTestServer(&categoryList, &questionList, &transactionList)
+	}
+
+	t.Run("add, list, get, rename & remove", func(t *testing.T) {
+		client, stop := newTestClient(t, newServer())
+		defer stop()
+
+		added, err := client.AddOption(ctx, &pb.AddOptionRequest{CategoryId: "1234", QuestionId: "2", Title: "hostel"})
+		assertNoError(t, err)
+		assertStringsEqual(t, added.GetTitle(), "hostel")
+
+		options, err := client.ListOptions(ctx, &pb.ListOptionsRequest{CategoryId: "1234", QuestionId: "2"})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(options.GetOptions()), 2)
+
+		got, err := client.GetOption(ctx, &pb.GetOptionRequest{CategoryId: "1234", QuestionId: "2", Id: added.GetId()})
+		assertNoError(t, err)
+		assertStringsEqual(t, got.GetTitle(), "hostel")
+
+		renamed, err := client.RenameOption(ctx, &pb.RenameOptionRequest{CategoryId: "1234", QuestionId: "2", Id: added.GetId(), Title: "motel"})
+		assertNoError(t, err)
+		assertStringsEqual(t, renamed.GetTitle(), "motel")
+
+		_, err = client.RemoveOption(ctx, &pb.RemoveOptionRequest{CategoryId: "1234", QuestionId: "2", Id: added.GetId()})
+		assertNoError(t, err)
+
+		_, err = client.GetOption(ctx, &pb.GetOptionRequest{CategoryId: "1234", QuestionId: "2", Id: added.GetId()})
+		assertStatus(t, err, codes.NotFound, internal.ErrorOptionNotFound)
+	})
+
+	t.Run("failures behave as over http", func(t *testing.T) {
+		client, stop := newTestClient(t, newServer())
+		defer stop()
+
+		t.Run("option is empty", func(t *testing.T) {
+			_, err := client.AddOption(ctx, &pb.AddOptionRequest{CategoryId: "1234", QuestionId: "2", Title: ""})
+
+			assertFieldViolations(t, err, []fieldViolation{{internal.ErrorOptionEmpty, "/title"}})
+		})
+
+		t.Run("option is a duplicate", func(t *testing.T) {
+			_, err := client.AddOption(ctx, &pb.AddOptionRequest{CategoryId: "1234", QuestionId: "2", Title: "hotel"})
+
+			assertFieldViolations(t, err, []fieldViolation{{internal.ErrorDuplicateOption, "/title"}})
+		})
+
+		t.Run("number question has no options", func(t *testing.T) {
+			_, err := client.ListOptions(ctx, &pb.ListOptionsRequest{CategoryId: "1234", QuestionId: "1"})
+
+			assertFieldViolations(t, err, []fieldViolation{{internal.ErrorQuestionHasNoOptions, ""}})
+		})
+
+		t.Run("option is chosen by a transaction", func(t *testing.T) {
+			_, err := client.RemoveOption(ctx, &pb.RemoveOptionRequest{CategoryId: "1234", QuestionId: "2", Id: "a"})
+
+			assertStatus(t, err, codes.FailedPrecondition, internal.ErrorOptionInUse)
+			assertErrorReason(t, err, internal.ErrorOptionInUse)
+		})
+
+		options, err := client.ListOptions(ctx, &pb.ListOptionsRequest{CategoryId: "1234", QuestionId: "2"})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(options.GetOptions()), 1)
+	})
+}
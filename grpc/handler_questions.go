@@ -0,0 +1,118 @@
+package grpctransport
+
+import (
+	"context"
+
+	"github.com/jgillard/practising-go-tdd/grpc/pb"
+)
+
+// ListQuestions implements pb.CategoriesServer
+func (s *Server) ListQuestions(ctx context.Context, req *pb.ListQuestionsRequest) (*pb.QuestionList, error) {
+	questionList, err := s.service.ListQuestions(ctx, req.GetCategoryId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	response := &pb.QuestionList{}
+	for _, q := range questionList.Questions {
+		response.Questions = append(response.Questions, questionToPB(q))
+	}
+	return response, nil
+}
+
+// GetQuestion implements pb.CategoriesServer
+func (s *Server) GetQuestion(ctx context.Context, req *pb.GetQuestionRequest) (*pb.Question, error) {
+	question, err := s.service.GetQuestion(ctx, req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return questionToPB(question), nil
+}
+
+// AddQuestion implements pb.CategoriesServer
+func (s *Server) AddQuestion(ctx context.Context, req *pb.AddQuestionRequest) (*pb.Question, error) {
+	question, err := s.service.AddQuestion(ctx, req.GetCategoryId(), questionPostRequestFromPB(req))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return questionToPB(question), nil
+}
+
+// RenameQuestion implements pb.CategoriesServer
+func (s *Server) RenameQuestion(ctx context.Context, req *pb.RenameQuestionRequest) (*pb.Question, error) {
+	question, err := s.service.RenameQuestion(ctx, req.GetCategoryId(), req.GetId(), req.GetTitle())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return questionToPB(question), nil
+}
+
+// UpdateQuestion implements pb.CategoriesServer
+func (s *Server) UpdateQuestion(ctx context.Context, req *pb.UpdateQuestionRequest) (*pb.Question, error) {
+	question, err := s.service.UpdateQuestion(ctx, req.GetCategoryId(), req.GetId(), questionPatchRequestFromPB(req))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return questionToPB(question), nil
+}
+
+// RemoveQuestion implements pb.CategoriesServer
+func (s *Server) RemoveQuestion(ctx context.Context, req *pb.RemoveQuestionRequest) (*pb.RemoveQuestionResponse, error) {
+	if err := s.service.RemoveQuestion(ctx, req.GetCategoryId(), req.GetId(), req.GetCascade()); err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &pb.RemoveQuestionResponse{}, nil
+}
+
+// ListEffectiveQuestions implements pb.CategoriesServer
+func (s *Server) ListEffectiveQuestions(ctx context.Context, req *pb.ListQuestionsRequest) (*pb.QuestionList, error) {
+	questionList, err := s.service.ListEffectiveQuestions(ctx, req.GetCategoryId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	response := &pb.QuestionList{}
+	for _, q := range questionList.Questions {
+		response.Questions = append(response.Questions, questionToPB(q))
+	}
+	return response, nil
+}
+
+// HideQuestion implements pb.CategoriesServer
+func (s *Server) HideQuestion(ctx context.Context, req *pb.HideQuestionRequest) (*pb.Question, error) {
+	question, err := s.service.HideQuestion(ctx, req.GetCategoryId(), req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return questionToPB(question), nil
+}
+
+// UnhideQuestion implements pb.CategoriesServer
+func (s *Server) UnhideQuestion(ctx context.Context, req *pb.HideQuestionRequest) (*pb.Question, error) {
+	question, err := s.service.UnhideQuestion(ctx, req.GetCategoryId(), req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return questionToPB(question), nil
+}
+
+// NextQuestions implements pb.CategoriesServer
+func (s *Server) NextQuestions(ctx context.Context, req *pb.NextQuestionsRequest) (*pb.QuestionList, error) {
+	questionList, err := s.service.NextQuestions(ctx, req.GetCategoryId(), answersFromPB(req.GetAnswers()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	response := &pb.QuestionList{}
+	for _, q := range questionList.Questions {
+		response.Questions = append(response.Questions, questionToPB(q))
+	}
+	return response, nil
+}
@@ -0,0 +1,323 @@
+package grpctransport
+
+import (
+	"testing"
+
+	"github.com/jgillard/practising-go-tdd/grpc/pb"
+	internal "github.com/jgillard/practising-go-tdd/internal"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestQuestions(t *testing.T) {
+
+	newServer := func() *Server {
+		categoryList := internal.CategoryList{
+			Categories: []internal.Category{
+				internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+			},
+		}
+		questionList := internal.QuestionList{
+			Questions: []internal.Question{
+				internal.Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+			},
+		}
+		transactionList := internal.TransactionList{
+			Transactions: []internal.Transaction{
+				internal.Transaction{ID: "abcdef", Amount: -350, Currency: "GBP", CategoryID: "1234", Answers: []internal.Answer{
+					{QuestionID: "1", Value: "2"},
+				}},
+			},
+		}
+		return newTestServer(&categoryList, &questionList, &transactionList)
+	}
+
+	t.Run("add, list, get, rename & remove", func(t *testing.T) {
+		client, stop := newTestClient(t, newServer())
+		defer stop()
+
+		added, err := client.AddQuestion(ctx, &pb.AddQuestionRequest{
+			CategoryId: "1234",
+			Title:      "which room",
+			Type:       "string",
+			Options:    &pb.Options{Titles: []string{"single", "double"}},
+		})
+		assertNoError(t, err)
+		assertStringsEqual(t, added.GetCategoryId(), "1234")
+		assertNumbersEqual(t, len(added.GetOptions()), 2)
+
+		questions, err := client.ListQuestions(ctx, &pb.ListQuestionsRequest{CategoryId: "1234"})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(questions.GetQuestions()), 2)
+
+		got, err := client.GetQuestion(ctx, &pb.GetQuestionRequest{Id: added.GetId()})
+		assertNoError(t, err)
+		assertStringsEqual(t, got.GetTitle(), "which room")
+
+		renamed, err := client.RenameQuestion(ctx, &pb.RenameQuestionRequest{CategoryId: "1234", Id: added.GetId(), Title: "which bed"})
+		assertNoError(t, err)
+		assertStringsEqual(t, renamed.GetTitle(), "which bed")
+
+		_, err = client.RemoveQuestion(ctx, &pb.RemoveQuestionRequest{CategoryId: "1234", Id: added.GetId()})
+		assertNoError(t, err)
+
+		_, err = client.GetQuestion(ctx, &pb.GetQuestionRequest{Id: added.GetId()})
+		assertStatus(t, err, codes.NotFound, internal.ErrorQuestionNotFound)
+	})
+
+	t.Run("failures behave as over http", func(t *testing.T) {
+		client, stop := newTestClient(t, newServer())
+		defer stop()
+
+		t.Run("every field problem is reported at once", func(t *testing.T) {
+			_, err := client.AddQuestion(ctx, &pb.AddQuestionRequest{
+				CategoryId: "1234",
+				Title:      "!!!",
+				Type:       "foo",
+				Options:    &pb.Options{Titles: []string{"a", "", "a"}},
+			})
+
+			assertFieldViolations(t, err, []fieldViolation{
+				{internal.ErrorInvalidTitle, "/title"},
+				{internal.ErrorInvalidType, "/type"},
+				{internal.ErrorOptionEmpty, "/options/1"},
+				{internal.ErrorDuplicateOption, "/options/2"},
+			})
+		})
+
+		t.Run("title is a duplicate", func(t *testing.T) {
+			_, err := client.AddQuestion(ctx, &pb.AddQuestionRequest{CategoryId: "1234", Title: "how many nights?", Type: "number"})
+
+			assertStatus(t, err, codes.AlreadyExists, internal.ErrorDuplicateTitle)
+		})
+
+		t.Run("category doesn't exist", func(t *testing.T) {
+			_, err := client.RenameQuestion(ctx, &pb.RenameQuestionRequest{CategoryId: "5678", Id: "1", Title: "how many"})
+
+			assertStatus(t, err, codes.NotFound, internal.ErrorCategoryNotFound)
+		})
+
+		t.Run("question is answered by a transaction", func(t *testing.T) {
+			_, err := client.RemoveQuestion(ctx, &pb.RemoveQuestionRequest{CategoryId: "1234", Id: "1"})
+
+			assertStatus(t, err, codes.FailedPrecondition, internal.ErrorQuestionInUse)
+			assertErrorReason(t, err, internal.ErrorQuestionInUse)
+		})
+
+		questions, err := client.ListQuestions(ctx, &pb.ListQuestionsRequest{CategoryId: "1234"})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(questions.GetQuestions()), 1)
+	})
+}
+
+func TestQuestionConstraints(t *testing.T) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+		},
+	}
+	client, stop := newTestClient(t, newTestServer(&categoryList, &internal.QuestionList{}, &internal.TransactionList{}))
+	defer stop()
+
+	minNights, maxNights := 1.0, 14.0
+
+	added, err := client.AddQuestion(ctx, &pb.AddQuestionRequest{
+		CategoryId:  "1234",
+		Title:       "how many nights",
+		Type:        "number",
+		Constraints: &pb.Constraints{Required: true, Min: &minNights, Max: &maxNights, Default: structpb.NewNumberValue(1)},
+	})
+	assertNoError(t, err)
+	assertDeepEqual(t, added.GetConstraints().GetMax(), maxNights)
+	assertDeepEqual(t, added.GetConstraints().GetDefault().AsInterface(), 1.0)
+
+	t.Run("updating replaces them", func(t *testing.T) {
+		title := "how many nights?"
+		updated, err := client.UpdateQuestion(ctx, &pb.UpdateQuestionRequest{
+			CategoryId:  "1234",
+			Id:          added.GetId(),
+			Title:       &title,
+			Constraints: &pb.Constraints{Unit: "nights"},
+		})
+		assertNoError(t, err)
+		assertStringsEqual(t, updated.GetTitle(), title)
+		assertStringsEqual(t, updated.GetConstraints().GetUnit(), "nights")
+		assertDeepEqual(t, updated.GetConstraints().Max, (*float64)(nil))
+	})
+
+	t.Run("an empty message removes them", func(t *testing.T) {
+		updated, err := client.UpdateQuestion(ctx, &pb.UpdateQuestionRequest{
+			CategoryId:  "1234",
+			Id:          added.GetId(),
+			Constraints: &pb.Constraints{},
+		})
+		assertNoError(t, err)
+		assertDeepEqual(t, updated.GetConstraints(), (*pb.Constraints)(nil))
+	})
+
+	t.Run("failures behave as over http", func(t *testing.T) {
+		maxLength := int32(10)
+		_, err := client.UpdateQuestion(ctx, &pb.UpdateQuestionRequest{
+			CategoryId:  "1234",
+			Id:          added.GetId(),
+			Constraints: &pb.Constraints{Min: &maxNights, Max: &minNights, MaxLength: &maxLength},
+		})
+
+		assertFieldViolations(t, err, []fieldViolation{
+			{internal.ErrorConstraintNotAllowed, "/constraints/maxLength"},
+			{internal.ErrorMinAboveMax, "/constraints/max"},
+		})
+
+		_, err = client.UpdateQuestion(ctx, &pb.UpdateQuestionRequest{CategoryId: "1234", Id: added.GetId()})
+
+		assertFieldViolations(t, err, []fieldViolation{{internal.ErrorFieldMissing, ""}})
+	})
+}
+
+func TestQuestionDependencies(t *testing.T) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+		},
+	}
+	questionList := internal.QuestionList{
+		Questions: []internal.Question{
+			internal.Question{ID: "1", Title: "which room?", CategoryID: "1234", Type: "string", Options: internal.OptionList{
+				{ID: "1", Title: "single"},
+				{ID: "2", Title: "double"},
+			}},
+			internal.Question{ID: "2", Title: "breakfast included?", CategoryID: "1234", Type: "boolean"},
+		},
+	}
+	client, stop := newTestClient(t, newTestServer(&categoryList, &questionList, &internal.TransactionList{}))
+	defer stop()
+
+	followUp, err := client.AddQuestion(ctx, &pb.AddQuestionRequest{
+		CategoryId: "1234",
+		Title:      "who with",
+		Type:       "text",
+		DependsOn:  &pb.Dependency{QuestionId: "1", OptionId: "2"},
+	})
+	assertNoError(t, err)
+	assertStringsEqual(t, followUp.GetDependsOn().GetOptionId(), "2")
+
+	nextIDs := func(t *testing.T, answers []*pb.Answer) []string {
+		t.Helper()
+		next, err := client.NextQuestions(ctx, &pb.NextQuestionsRequest{CategoryId: "1234", Answers: answers})
+		assertNoError(t, err)
+		ids := []string{}
+		for _, q := range next.GetQuestions() {
+			ids = append(ids, q.GetId())
+		}
+		return ids
+	}
+
+	t.Run("a follow-up is next once its dependency is met", func(t *testing.T) {
+		assertDeepEqual(t, nextIDs(t, nil), []string{"1", "2"})
+		assertDeepEqual(t, nextIDs(t, []*pb.Answer{{QuestionId: "1", Value: structpb.NewStringValue("1")}}), []string{"2"})
+		assertDeepEqual(t, nextIDs(t, []*pb.Answer{{QuestionId: "1", Value: structpb.NewStringValue("2")}}), []string{"2", followUp.GetId()})
+	})
+
+	t.Run("updating replaces the dependency, an empty message removing it", func(t *testing.T) {
+		equals := false
+		updated, err := client.UpdateQuestion(ctx, &pb.UpdateQuestionRequest{
+			CategoryId: "1234",
+			Id:         followUp.GetId(),
+			DependsOn:  &pb.Dependency{QuestionId: "2", Equals: &equals},
+		})
+		assertNoError(t, err)
+		assertStringsEqual(t, updated.GetDependsOn().GetQuestionId(), "2")
+		assertDeepEqual(t, nextIDs(t, []*pb.Answer{{QuestionId: "2", Value: structpb.NewBoolValue(false)}}), []string{"1", followUp.GetId()})
+
+		updated, err = client.UpdateQuestion(ctx, &pb.UpdateQuestionRequest{
+			CategoryId: "1234",
+			Id:         followUp.GetId(),
+			DependsOn:  &pb.Dependency{},
+		})
+		assertNoError(t, err)
+		assertDeepEqual(t, updated.GetDependsOn(), (*pb.Dependency)(nil))
+	})
+
+	t.Run("failures behave as over http", func(t *testing.T) {
+		_, err := client.UpdateQuestion(ctx, &pb.UpdateQuestionRequest{
+			CategoryId: "1234",
+			Id:         followUp.GetId(),
+			DependsOn:  &pb.Dependency{QuestionId: "1", OptionId: "3"},
+		})
+		assertFieldViolations(t, err, []fieldViolation{{internal.ErrorDependencyOptionNotFound, "/dependsOn/optionID"}})
+
+		_, err = client.NextQuestions(ctx, &pb.NextQuestionsRequest{
+			CategoryId: "1234",
+			Answers:    []*pb.Answer{{QuestionId: "2", Value: structpb.NewStringValue("yes")}},
+		})
+		assertFieldViolations(t, err, []fieldViolation{{internal.ErrorInvalidAnswer, "/answers/0/value"}})
+	})
+
+	t.Run("a question with follow-ups is in use", func(t *testing.T) {
+		equals := true
+		_, err := client.UpdateQuestion(ctx, &pb.UpdateQuestionRequest{
+			CategoryId: "1234",
+			Id:         followUp.GetId(),
+			DependsOn:  &pb.Dependency{QuestionId: "2", Equals: &equals},
+		})
+		assertNoError(t, err)
+
+		_, err = client.RemoveQuestion(ctx, &pb.RemoveQuestionRequest{CategoryId: "1234", Id: "2"})
+		assertStatus(t, err, codes.FailedPrecondition, internal.ErrorQuestionInUse)
+
+		_, err = client.RemoveQuestion(ctx, &pb.RemoveQuestionRequest{CategoryId: "1234", Id: "2", Cascade: true})
+		assertNoError(t, err)
+
+		got, err := client.GetQuestion(ctx, &pb.GetQuestionRequest{Id: followUp.GetId()})
+		assertNoError(t, err)
+		assertDeepEqual(t, got.GetDependsOn(), (*pb.Dependency)(nil))
+	})
+}
+
+func TestQuestionInheritance(t *testing.T) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "food and drink", ParentID: ""},
+			internal.Category{ID: "5678", Name: "restaurants", ParentID: "1234"},
+		},
+	}
+	questionList := internal.QuestionList{
+		Questions: []internal.Question{
+			internal.Question{ID: "1", Title: "which meal?", CategoryID: "1234", Type: "number"},
+			internal.Question{ID: "2", Title: "how many people?", CategoryID: "1234", Type: "number"},
+		},
+	}
+	client, stop := newTestClient(t, newTestServer(&categoryList, &questionList, nil))
+	defer stop()
+
+	effectiveIDs := func(t *testing.T) []string {
+		t.Helper()
+		questions, err := client.ListEffectiveQuestions(ctx, &pb.ListQuestionsRequest{CategoryId: "5678"})
+		assertNoError(t, err)
+
+		var ids []string
+		for _, q := range questions.GetQuestions() {
+			ids = append(ids, q.GetId())
+		}
+		return ids
+	}
+
+	assertDeepEqual(t, effectiveIDs(t), []string{"1", "2"})
+
+	override, err := client.AddQuestion(ctx, &pb.AddQuestionRequest{CategoryId: "5678", Title: "which meal?", Type: "boolean", Overrides: "1"})
+	assertNoError(t, err)
+	assertStringsEqual(t, override.GetOverrides(), "1")
+
+	hidden, err := client.HideQuestion(ctx, &pb.HideQuestionRequest{CategoryId: "5678", Id: "2"})
+	assertNoError(t, err)
+	assertDeepEqual(t, hidden.GetHiddenIn(), []string{"5678"})
+	assertDeepEqual(t, effectiveIDs(t), []string{override.GetId()})
+
+	_, err = client.UnhideQuestion(ctx, &pb.HideQuestionRequest{CategoryId: "5678", Id: "2"})
+	assertNoError(t, err)
+	assertDeepEqual(t, effectiveIDs(t), []string{override.GetId(), "2"})
+
+	_, err = client.UnhideQuestion(ctx, &pb.HideQuestionRequest{CategoryId: "5678", Id: "2"})
+	assertStatus(t, err, codes.NotFound, internal.ErrorQuestionNotHidden)
+}
@@ -0,0 +1,138 @@
+package grpctransport
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/jgillard/practising-go-tdd/grpc/pb"
+	internal "github.com/jgillard/practising-go-tdd/internal"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves the server over an in-process listener,
+// returning a client connected to it, and a func to stop them both
+func newTestClient(t *testing.T, server *Server) (pb.CategoriesClient, func()) {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+
+	g := server.GRPCServer()
+	go g.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pb.NewCategoriesClient(conn), func() {
+		conn.Close()
+		g.Stop()
+	}
+}
+
+func newTestServer(categoryList *internal.CategoryList, questionList *internal.QuestionList, transactionList *internal.TransactionList) *Server {
+	service := internal.NewService(
+		internal.NewInMemoryCategoryStore(categoryList),
+		internal.NewInMemoryQuestionStore(questionList),
+		internal.NewInMemoryTransactionStore(transactionList),
+	)
+	return NewServer(service)
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("got error '%v' wanted none", err)
+	}
+}
+
+func assertStringsEqual(t *testing.T, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got '%s' wanted '%s'", got, want)
+	}
+}
+
+func assertNumbersEqual(t *testing.T, got, want int) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %d wanted %d", got, want)
+	}
+}
+
+func assertDeepEqual(t *testing.T, got, want interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got '%v' wanted '%v'", got, want)
+	}
+}
+
+// assertStatus checks err is a status with the code & message
+func assertStatus(t *testing.T, err error, code codes.Code, message string) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("got error '%v' wanted a status", err)
+	}
+	if st.Code() != code {
+		t.Errorf("got code %s wanted %s", st.Code(), code)
+	}
+	assertStringsEqual(t, st.Message(), message)
+}
+
+// assertErrorReason checks err carries an ErrorInfo with the code for title
+func assertErrorReason(t *testing.T, err error, title string) {
+	t.Helper()
+	want, _ := internal.ErrorCode(title)
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			assertStringsEqual(t, info.GetReason(), want)
+			return
+		}
+	}
+	t.Errorf("got no error info wanted reason '%s'", want)
+}
+
+// fieldViolation is a BadRequest field violation without the code, which is looked up from the title
+type fieldViolation struct {
+	Title   string
+	Pointer string
+}
+
+// assertFieldViolations checks err is InvalidArgument, listing exactly the violations in want, in order
+func assertFieldViolations(t *testing.T, err error, want []fieldViolation) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("got code %s wanted %s", st.Code(), codes.InvalidArgument)
+	}
+
+	var got []fieldViolation
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, v := range badRequest.GetFieldViolations() {
+			code, _ := internal.ErrorCode(v.GetDescription())
+			if v.GetReason() != code || code == "" {
+				t.Errorf("violation '%s' has reason '%s' wanted '%s'", v.GetDescription(), v.GetReason(), code)
+			}
+			got = append(got, fieldViolation{v.GetDescription(), v.GetField()})
+		}
+	}
+
+	assertDeepEqual(t, got, want)
+}
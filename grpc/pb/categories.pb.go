@@ -0,0 +1,2358 @@
+// The gRPC interface to categories & their questions,
+// behaving exactly as the HTTP API does, see internal.Service
+//
+// Regenerate categories.pb.go & categories_grpc.pb.go after changing this file with
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative categories.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: categories.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Category struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// "" for a top level category
+	ParentId      string `protobuf:"bytes,3,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Owner         string `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Category) Reset() {
+	*x = Category{}
+	mi := &file_categories_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Category) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Category) ProtoMessage() {}
+
+func (x *Category) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Category.ProtoReflect.Descriptor instead.
+func (*Category) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Category) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Category) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Category) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Category) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+type CategoryList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryList) Reset() {
+	*x = CategoryList{}
+	mi := &file_categories_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryList) ProtoMessage() {}
+
+func (x *CategoryList) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryList.ProtoReflect.Descriptor instead.
+func (*CategoryList) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CategoryList) GetCategories() []*Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type ListCategoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesRequest) Reset() {
+	*x = ListCategoriesRequest{}
+	mi := &file_categories_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesRequest) ProtoMessage() {}
+
+func (x *ListCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{2}
+}
+
+type GetCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoryRequest) Reset() {
+	*x = GetCategoryRequest{}
+	mi := &file_categories_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoryRequest) ProtoMessage() {}
+
+func (x *GetCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoryRequest.ProtoReflect.Descriptor instead.
+func (*GetCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCategoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetCategoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Children      []*Category            `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoryResponse) Reset() {
+	*x = GetCategoryResponse{}
+	mi := &file_categories_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoryResponse) ProtoMessage() {}
+
+func (x *GetCategoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoryResponse.ProtoReflect.Descriptor instead.
+func (*GetCategoryResponse) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetCategoryResponse) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+func (x *GetCategoryResponse) GetChildren() []*Category {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+// CategoryTree is a category with its subcategories, and theirs, nested beneath it
+type CategoryTree struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Children      []*CategoryTree        `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryTree) Reset() {
+	*x = CategoryTree{}
+	mi := &file_categories_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryTree) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryTree) ProtoMessage() {}
+
+func (x *CategoryTree) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryTree.ProtoReflect.Descriptor instead.
+func (*CategoryTree) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CategoryTree) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+func (x *CategoryTree) GetChildren() []*CategoryTree {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type AddCategoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// required, "" adds a top level category
+	ParentId      *string `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCategoryRequest) Reset() {
+	*x = AddCategoryRequest{}
+	mi := &file_categories_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCategoryRequest) ProtoMessage() {}
+
+func (x *AddCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCategoryRequest.ProtoReflect.Descriptor instead.
+func (*AddCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AddCategoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AddCategoryRequest) GetParentId() string {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return ""
+}
+
+type RenameCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameCategoryRequest) Reset() {
+	*x = RenameCategoryRequest{}
+	mi := &file_categories_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameCategoryRequest) ProtoMessage() {}
+
+func (x *RenameCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameCategoryRequest.ProtoReflect.Descriptor instead.
+func (*RenameCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RenameCategoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenameCategoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type MoveCategoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// required, "" moves the category to the top level
+	ParentId      *string `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveCategoryRequest) Reset() {
+	*x = MoveCategoryRequest{}
+	mi := &file_categories_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveCategoryRequest) ProtoMessage() {}
+
+func (x *MoveCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveCategoryRequest.ProtoReflect.Descriptor instead.
+func (*MoveCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MoveCategoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MoveCategoryRequest) GetParentId() string {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return ""
+}
+
+type RemoveCategoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// remove subcategories & uncategorise transactions rather than refusing
+	Cascade bool `protobuf:"varint,2,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	// move transactions to this category rather than refusing
+	ReassignTo    string `protobuf:"bytes,3,opt,name=reassign_to,json=reassignTo,proto3" json:"reassign_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveCategoryRequest) Reset() {
+	*x = RemoveCategoryRequest{}
+	mi := &file_categories_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveCategoryRequest) ProtoMessage() {}
+
+func (x *RemoveCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveCategoryRequest.ProtoReflect.Descriptor instead.
+func (*RemoveCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RemoveCategoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RemoveCategoryRequest) GetCascade() bool {
+	if x != nil {
+		return x.Cascade
+	}
+	return false
+}
+
+func (x *RemoveCategoryRequest) GetReassignTo() string {
+	if x != nil {
+		return x.ReassignTo
+	}
+	return ""
+}
+
+type Removed struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Categories             []string               `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	Questions              []string               `protobuf:"bytes,2,rep,name=questions,proto3" json:"questions,omitempty"`
+	ReassignedTransactions int32                  `protobuf:"varint,3,opt,name=reassigned_transactions,json=reassignedTransactions,proto3" json:"reassigned_transactions,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Removed) Reset() {
+	*x = Removed{}
+	mi := &file_categories_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Removed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Removed) ProtoMessage() {}
+
+func (x *Removed) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Removed.ProtoReflect.Descriptor instead.
+func (*Removed) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Removed) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *Removed) GetQuestions() []string {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+func (x *Removed) GetReassignedTransactions() int32 {
+	if x != nil {
+		return x.ReassignedTransactions
+	}
+	return 0
+}
+
+type Option struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Option) Reset() {
+	*x = Option{}
+	mi := &file_categories_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Option) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Option) ProtoMessage() {}
+
+func (x *Option) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Option.ProtoReflect.Descriptor instead.
+func (*Option) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Option) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Option) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type Question struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title      string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	CategoryId string                 `protobuf:"bytes,3,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Type       string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Options    []*Option              `protobuf:"bytes,5,rep,name=options,proto3" json:"options,omitempty"`
+	Owner      string                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	// the ID of the inherited question this one is asked in place of, if any
+	Overrides string `protobuf:"bytes,7,opt,name=overrides,proto3" json:"overrides,omitempty"`
+	// the subcategories that don't inherit this question
+	HiddenIn    []string     `protobuf:"bytes,8,rep,name=hidden_in,json=hiddenIn,proto3" json:"hidden_in,omitempty"`
+	Constraints *Constraints `protobuf:"bytes,9,opt,name=constraints,proto3" json:"constraints,omitempty"`
+	// only asked once the question it depends on has an answer meeting it
+	DependsOn     *Dependency `protobuf:"bytes,10,opt,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Question) Reset() {
+	*x = Question{}
+	mi := &file_categories_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Question) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Question) ProtoMessage() {}
+
+func (x *Question) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Question.ProtoReflect.Descriptor instead.
+func (*Question) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Question) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Question) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Question) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *Question) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Question) GetOptions() []*Option {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Question) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Question) GetOverrides() string {
+	if x != nil {
+		return x.Overrides
+	}
+	return ""
+}
+
+func (x *Question) GetHiddenIn() []string {
+	if x != nil {
+		return x.HiddenIn
+	}
+	return nil
+}
+
+func (x *Question) GetConstraints() *Constraints {
+	if x != nil {
+		return x.Constraints
+	}
+	return nil
+}
+
+func (x *Question) GetDependsOn() *Dependency {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+// Constraints narrow down the answers a question accepts, see internal.Constraints
+type Constraints struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// must be answered whenever a transaction is categorised, unless there's a default
+	Required bool `protobuf:"varint,1,opt,name=required,proto3" json:"required,omitempty"`
+	// min, max, step & unit are for "number" questions
+	Min  *float64 `protobuf:"fixed64,2,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max  *float64 `protobuf:"fixed64,3,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	Step *float64 `protobuf:"fixed64,4,opt,name=step,proto3,oneof" json:"step,omitempty"`
+	Unit string   `protobuf:"bytes,5,opt,name=unit,proto3" json:"unit,omitempty"`
+	// for "text" questions, in characters
+	MaxLength *int32 `protobuf:"varint,6,opt,name=max_length,json=maxLength,proto3,oneof" json:"max_length,omitempty"`
+	// the answer given to the question when it's left unanswered, as it would be over HTTP
+	Default       *structpb.Value `protobuf:"bytes,7,opt,name=default,proto3" json:"default,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Constraints) Reset() {
+	*x = Constraints{}
+	mi := &file_categories_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Constraints) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Constraints) ProtoMessage() {}
+
+func (x *Constraints) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Constraints.ProtoReflect.Descriptor instead.
+func (*Constraints) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Constraints) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *Constraints) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *Constraints) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *Constraints) GetStep() float64 {
+	if x != nil && x.Step != nil {
+		return *x.Step
+	}
+	return 0
+}
+
+func (x *Constraints) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Constraints) GetMaxLength() int32 {
+	if x != nil && x.MaxLength != nil {
+		return *x.MaxLength
+	}
+	return 0
+}
+
+func (x *Constraints) GetDefault() *structpb.Value {
+	if x != nil {
+		return x.Default
+	}
+	return nil
+}
+
+// Dependency makes a question a follow-up, see internal.Dependency
+type Dependency struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	// for "string" & "multiselect" questions
+	OptionId string `protobuf:"bytes,2,opt,name=option_id,json=optionId,proto3" json:"option_id,omitempty"`
+	// for "number" questions, either may be left out
+	Min *float64 `protobuf:"fixed64,3,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max *float64 `protobuf:"fixed64,4,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	// for "boolean" questions
+	Equals        *bool `protobuf:"varint,5,opt,name=equals,proto3,oneof" json:"equals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Dependency) Reset() {
+	*x = Dependency{}
+	mi := &file_categories_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dependency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dependency) ProtoMessage() {}
+
+func (x *Dependency) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dependency.ProtoReflect.Descriptor instead.
+func (*Dependency) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Dependency) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *Dependency) GetOptionId() string {
+	if x != nil {
+		return x.OptionId
+	}
+	return ""
+}
+
+func (x *Dependency) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *Dependency) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *Dependency) GetEquals() bool {
+	if x != nil && x.Equals != nil {
+		return *x.Equals
+	}
+	return false
+}
+
+type QuestionList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Questions     []*Question            `protobuf:"bytes,1,rep,name=questions,proto3" json:"questions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuestionList) Reset() {
+	*x = QuestionList{}
+	mi := &file_categories_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuestionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuestionList) ProtoMessage() {}
+
+func (x *QuestionList) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuestionList.ProtoReflect.Descriptor instead.
+func (*QuestionList) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *QuestionList) GetQuestions() []*Question {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+type ListQuestionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQuestionsRequest) Reset() {
+	*x = ListQuestionsRequest{}
+	mi := &file_categories_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuestionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuestionsRequest) ProtoMessage() {}
+
+func (x *ListQuestionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuestionsRequest.ProtoReflect.Descriptor instead.
+func (*ListQuestionsRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListQuestionsRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+type GetQuestionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuestionRequest) Reset() {
+	*x = GetQuestionRequest{}
+	mi := &file_categories_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuestionRequest) ProtoMessage() {}
+
+func (x *GetQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuestionRequest.ProtoReflect.Descriptor instead.
+func (*GetQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetQuestionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Options is a message so that an empty list can be told apart from none being given
+type Options struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Titles        []string               `protobuf:"bytes,1,rep,name=titles,proto3" json:"titles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
+	mi := &file_categories_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Options) ProtoMessage() {}
+
+func (x *Options) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Options) GetTitles() []string {
+	if x != nil {
+		return x.Titles
+	}
+	return nil
+}
+
+type AddQuestionRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Title      string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Type       string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Options    *Options               `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	// the ID of an inherited question to ask the new one in place of
+	Overrides     string       `protobuf:"bytes,5,opt,name=overrides,proto3" json:"overrides,omitempty"`
+	Constraints   *Constraints `protobuf:"bytes,6,opt,name=constraints,proto3" json:"constraints,omitempty"`
+	DependsOn     *Dependency  `protobuf:"bytes,7,opt,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddQuestionRequest) Reset() {
+	*x = AddQuestionRequest{}
+	mi := &file_categories_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddQuestionRequest) ProtoMessage() {}
+
+func (x *AddQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddQuestionRequest.ProtoReflect.Descriptor instead.
+func (*AddQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AddQuestionRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *AddQuestionRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *AddQuestionRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *AddQuestionRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *AddQuestionRequest) GetOverrides() string {
+	if x != nil {
+		return x.Overrides
+	}
+	return ""
+}
+
+func (x *AddQuestionRequest) GetConstraints() *Constraints {
+	if x != nil {
+		return x.Constraints
+	}
+	return nil
+}
+
+func (x *AddQuestionRequest) GetDependsOn() *Dependency {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+type RenameQuestionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameQuestionRequest) Reset() {
+	*x = RenameQuestionRequest{}
+	mi := &file_categories_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameQuestionRequest) ProtoMessage() {}
+
+func (x *RenameQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameQuestionRequest.ProtoReflect.Descriptor instead.
+func (*RenameQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RenameQuestionRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *RenameQuestionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenameQuestionRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+// UpdateQuestionRequest changes whichever of a question's title, constraints & dependency are given,
+// the constraints & dependency replacing any the question had, empty messages removing them
+type UpdateQuestionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Title         *string                `protobuf:"bytes,3,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Constraints   *Constraints           `protobuf:"bytes,4,opt,name=constraints,proto3" json:"constraints,omitempty"`
+	DependsOn     *Dependency            `protobuf:"bytes,5,opt,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateQuestionRequest) Reset() {
+	*x = UpdateQuestionRequest{}
+	mi := &file_categories_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateQuestionRequest) ProtoMessage() {}
+
+func (x *UpdateQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateQuestionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateQuestionRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *UpdateQuestionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateQuestionRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateQuestionRequest) GetConstraints() *Constraints {
+	if x != nil {
+		return x.Constraints
+	}
+	return nil
+}
+
+func (x *UpdateQuestionRequest) GetDependsOn() *Dependency {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+type RemoveQuestionRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Id         string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// remove answers to the question rather than refusing
+	Cascade       bool `protobuf:"varint,3,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveQuestionRequest) Reset() {
+	*x = RemoveQuestionRequest{}
+	mi := &file_categories_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveQuestionRequest) ProtoMessage() {}
+
+func (x *RemoveQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveQuestionRequest.ProtoReflect.Descriptor instead.
+func (*RemoveQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RemoveQuestionRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *RemoveQuestionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RemoveQuestionRequest) GetCascade() bool {
+	if x != nil {
+		return x.Cascade
+	}
+	return false
+}
+
+type RemoveQuestionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveQuestionResponse) Reset() {
+	*x = RemoveQuestionResponse{}
+	mi := &file_categories_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveQuestionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveQuestionResponse) ProtoMessage() {}
+
+func (x *RemoveQuestionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveQuestionResponse.ProtoReflect.Descriptor instead.
+func (*RemoveQuestionResponse) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{23}
+}
+
+// HideQuestionRequest hides (or unhides) the inherited question id in the category
+type HideQuestionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HideQuestionRequest) Reset() {
+	*x = HideQuestionRequest{}
+	mi := &file_categories_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HideQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HideQuestionRequest) ProtoMessage() {}
+
+func (x *HideQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HideQuestionRequest.ProtoReflect.Descriptor instead.
+func (*HideQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *HideQuestionRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *HideQuestionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Answer answers a question, the value being as it would be over HTTP
+type Answer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Value         *structpb.Value        `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Answer) Reset() {
+	*x = Answer{}
+	mi := &file_categories_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Answer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Answer) ProtoMessage() {}
+
+func (x *Answer) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Answer.ProtoReflect.Descriptor instead.
+func (*Answer) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Answer) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *Answer) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// NextQuestionsRequest asks which of the category's questions are still to be answered given the answers so far
+type NextQuestionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Answers       []*Answer              `protobuf:"bytes,2,rep,name=answers,proto3" json:"answers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NextQuestionsRequest) Reset() {
+	*x = NextQuestionsRequest{}
+	mi := &file_categories_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NextQuestionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextQuestionsRequest) ProtoMessage() {}
+
+func (x *NextQuestionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextQuestionsRequest.ProtoReflect.Descriptor instead.
+func (*NextQuestionsRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *NextQuestionsRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *NextQuestionsRequest) GetAnswers() []*Answer {
+	if x != nil {
+		return x.Answers
+	}
+	return nil
+}
+
+type OptionList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       []*Option              `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OptionList) Reset() {
+	*x = OptionList{}
+	mi := &file_categories_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OptionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OptionList) ProtoMessage() {}
+
+func (x *OptionList) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OptionList.ProtoReflect.Descriptor instead.
+func (*OptionList) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *OptionList) GetOptions() []*Option {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type ListOptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	QuestionId    string                 `protobuf:"bytes,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOptionsRequest) Reset() {
+	*x = ListOptionsRequest{}
+	mi := &file_categories_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOptionsRequest) ProtoMessage() {}
+
+func (x *ListOptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListOptionsRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListOptionsRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *ListOptionsRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+type GetOptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	QuestionId    string                 `protobuf:"bytes,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOptionRequest) Reset() {
+	*x = GetOptionRequest{}
+	mi := &file_categories_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOptionRequest) ProtoMessage() {}
+
+func (x *GetOptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOptionRequest.ProtoReflect.Descriptor instead.
+func (*GetOptionRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetOptionRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *GetOptionRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *GetOptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type AddOptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	QuestionId    string                 `protobuf:"bytes,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddOptionRequest) Reset() {
+	*x = AddOptionRequest{}
+	mi := &file_categories_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddOptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddOptionRequest) ProtoMessage() {}
+
+func (x *AddOptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddOptionRequest.ProtoReflect.Descriptor instead.
+func (*AddOptionRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *AddOptionRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *AddOptionRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *AddOptionRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type RenameOptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	QuestionId    string                 `protobuf:"bytes,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameOptionRequest) Reset() {
+	*x = RenameOptionRequest{}
+	mi := &file_categories_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameOptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameOptionRequest) ProtoMessage() {}
+
+func (x *RenameOptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameOptionRequest.ProtoReflect.Descriptor instead.
+func (*RenameOptionRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RenameOptionRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *RenameOptionRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *RenameOptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RenameOptionRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type RemoveOptionRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	QuestionId string                 `protobuf:"bytes,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Id         string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	// remove answers choosing the option rather than refusing
+	Cascade       bool `protobuf:"varint,4,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveOptionRequest) Reset() {
+	*x = RemoveOptionRequest{}
+	mi := &file_categories_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveOptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveOptionRequest) ProtoMessage() {}
+
+func (x *RemoveOptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveOptionRequest.ProtoReflect.Descriptor instead.
+func (*RemoveOptionRequest) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RemoveOptionRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *RemoveOptionRequest) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
+	}
+	return ""
+}
+
+func (x *RemoveOptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RemoveOptionRequest) GetCascade() bool {
+	if x != nil {
+		return x.Cascade
+	}
+	return false
+}
+
+type RemoveOptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveOptionResponse) Reset() {
+	*x = RemoveOptionResponse{}
+	mi := &file_categories_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveOptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveOptionResponse) ProtoMessage() {}
+
+func (x *RemoveOptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_categories_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveOptionResponse.ProtoReflect.Descriptor instead.
+func (*RemoveOptionResponse) Descriptor() ([]byte, []int) {
+	return file_categories_proto_rawDescGZIP(), []int{33}
+}
+
+var File_categories_proto protoreflect.FileDescriptor
+
+const file_categories_proto_rawDesc = "" +
+	"\n" +
+	"\x10categories.proto\x12\n" +
+	"categories\x1a\x1cgoogle/protobuf/struct.proto\"a\n" +
+	"\bCategory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\tparent_id\x18\x03 \x01(\tR\bparentId\x12\x14\n" +
+	"\x05owner\x18\x04 \x01(\tR\x05owner\"D\n" +
+	"\fCategoryList\x124\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x14.categories.CategoryR\n" +
+	"categories\"\x17\n" +
+	"\x15ListCategoriesRequest\"$\n" +
+	"\x12GetCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"y\n" +
+	"\x13GetCategoryResponse\x120\n" +
+	"\bcategory\x18\x01 \x01(\v2\x14.categories.CategoryR\bcategory\x120\n" +
+	"\bchildren\x18\x02 \x03(\v2\x14.categories.CategoryR\bchildren\"v\n" +
+	"\fCategoryTree\x120\n" +
+	"\bcategory\x18\x01 \x01(\v2\x14.categories.CategoryR\bcategory\x124\n" +
+	"\bchildren\x18\x02 \x03(\v2\x18.categories.CategoryTreeR\bchildren\"X\n" +
+	"\x12AddCategoryRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\tparent_id\x18\x02 \x01(\tH\x00R\bparentId\x88\x01\x01B\f\n" +
+	"\n" +
+	"_parent_id\";\n" +
+	"\x15RenameCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"U\n" +
+	"\x13MoveCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
+	"\tparent_id\x18\x02 \x01(\tH\x00R\bparentId\x88\x01\x01B\f\n" +
+	"\n" +
+	"_parent_id\"b\n" +
+	"\x15RemoveCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\acascade\x18\x02 \x01(\bR\acascade\x12\x1f\n" +
+	"\vreassign_to\x18\x03 \x01(\tR\n" +
+	"reassignTo\"\x80\x01\n" +
+	"\aRemoved\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\tR\n" +
+	"categories\x12\x1c\n" +
+	"\tquestions\x18\x02 \x03(\tR\tquestions\x127\n" +
+	"\x17reassigned_transactions\x18\x03 \x01(\x05R\x16reassignedTransactions\".\n" +
+	"\x06Option\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\"\xd6\x02\n" +
+	"\bQuestion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1f\n" +
+	"\vcategory_id\x18\x03 \x01(\tR\n" +
+	"categoryId\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12,\n" +
+	"\aoptions\x18\x05 \x03(\v2\x12.categories.OptionR\aoptions\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\tR\x05owner\x12\x1c\n" +
+	"\toverrides\x18\a \x01(\tR\toverrides\x12\x1b\n" +
+	"\thidden_in\x18\b \x03(\tR\bhiddenIn\x129\n" +
+	"\vconstraints\x18\t \x01(\v2\x17.categories.ConstraintsR\vconstraints\x125\n" +
+	"\n" +
+	"depends_on\x18\n" +
+	" \x01(\v2\x16.categories.DependencyR\tdependsOn\"\x82\x02\n" +
+	"\vConstraints\x12\x1a\n" +
+	"\brequired\x18\x01 \x01(\bR\brequired\x12\x15\n" +
+	"\x03min\x18\x02 \x01(\x01H\x00R\x03min\x88\x01\x01\x12\x15\n" +
+	"\x03max\x18\x03 \x01(\x01H\x01R\x03max\x88\x01\x01\x12\x17\n" +
+	"\x04step\x18\x04 \x01(\x01H\x02R\x04step\x88\x01\x01\x12\x12\n" +
+	"\x04unit\x18\x05 \x01(\tR\x04unit\x12\"\n" +
+	"\n" +
+	"max_length\x18\x06 \x01(\x05H\x03R\tmaxLength\x88\x01\x01\x120\n" +
+	"\adefault\x18\a \x01(\v2\x16.google.protobuf.ValueR\adefaultB\x06\n" +
+	"\x04_minB\x06\n" +
+	"\x04_maxB\a\n" +
+	"\x05_stepB\r\n" +
+	"\v_max_length\"\xb0\x01\n" +
+	"\n" +
+	"Dependency\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x1b\n" +
+	"\toption_id\x18\x02 \x01(\tR\boptionId\x12\x15\n" +
+	"\x03min\x18\x03 \x01(\x01H\x00R\x03min\x88\x01\x01\x12\x15\n" +
+	"\x03max\x18\x04 \x01(\x01H\x01R\x03max\x88\x01\x01\x12\x1b\n" +
+	"\x06equals\x18\x05 \x01(\bH\x02R\x06equals\x88\x01\x01B\x06\n" +
+	"\x04_minB\x06\n" +
+	"\x04_maxB\t\n" +
+	"\a_equals\"B\n" +
+	"\fQuestionList\x122\n" +
+	"\tquestions\x18\x01 \x03(\v2\x14.categories.QuestionR\tquestions\"7\n" +
+	"\x14ListQuestionsRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\"$\n" +
+	"\x12GetQuestionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"!\n" +
+	"\aOptions\x12\x16\n" +
+	"\x06titles\x18\x01 \x03(\tR\x06titles\"\x9e\x02\n" +
+	"\x12AddQuestionRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12-\n" +
+	"\aoptions\x18\x04 \x01(\v2\x13.categories.OptionsR\aoptions\x12\x1c\n" +
+	"\toverrides\x18\x05 \x01(\tR\toverrides\x129\n" +
+	"\vconstraints\x18\x06 \x01(\v2\x17.categories.ConstraintsR\vconstraints\x125\n" +
+	"\n" +
+	"depends_on\x18\a \x01(\v2\x16.categories.DependencyR\tdependsOn\"^\n" +
+	"\x15RenameQuestionRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\"\xdf\x01\n" +
+	"\x15UpdateQuestionRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x19\n" +
+	"\x05title\x18\x03 \x01(\tH\x00R\x05title\x88\x01\x01\x129\n" +
+	"\vconstraints\x18\x04 \x01(\v2\x17.categories.ConstraintsR\vconstraints\x125\n" +
+	"\n" +
+	"depends_on\x18\x05 \x01(\v2\x16.categories.DependencyR\tdependsOnB\b\n" +
+	"\x06_title\"b\n" +
+	"\x15RemoveQuestionRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x18\n" +
+	"\acascade\x18\x03 \x01(\bR\acascade\"\x18\n" +
+	"\x16RemoveQuestionResponse\"F\n" +
+	"\x13HideQuestionRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"W\n" +
+	"\x06Answer\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value\"e\n" +
+	"\x14NextQuestionsRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12,\n" +
+	"\aanswers\x18\x02 \x03(\v2\x12.categories.AnswerR\aanswers\":\n" +
+	"\n" +
+	"OptionList\x12,\n" +
+	"\aoptions\x18\x01 \x03(\v2\x12.categories.OptionR\aoptions\"V\n" +
+	"\x12ListOptionsRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\tR\n" +
+	"questionId\"d\n" +
+	"\x10GetOptionRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\tR\n" +
+	"questionId\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\"j\n" +
+	"\x10AddOptionRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\tR\n" +
+	"questionId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\"}\n" +
+	"\x13RenameOptionRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\tR\n" +
+	"questionId\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\"\x81\x01\n" +
+	"\x13RemoveOptionRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\tR\n" +
+	"questionId\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x18\n" +
+	"\acascade\x18\x04 \x01(\bR\acascade\"\x16\n" +
+	"\x14RemoveOptionResponse2\xef\f\n" +
+	"\n" +
+	"Categories\x12M\n" +
+	"\x0eListCategories\x12!.categories.ListCategoriesRequest\x1a\x18.categories.CategoryList\x12N\n" +
+	"\vGetCategory\x12\x1e.categories.GetCategoryRequest\x1a\x1f.categories.GetCategoryResponse\x12K\n" +
+	"\x0fGetCategoryTree\x12\x1e.categories.GetCategoryRequest\x1a\x18.categories.CategoryTree\x12C\n" +
+	"\vAddCategory\x12\x1e.categories.AddCategoryRequest\x1a\x14.categories.Category\x12I\n" +
+	"\x0eRenameCategory\x12!.categories.RenameCategoryRequest\x1a\x14.categories.Category\x12E\n" +
+	"\fMoveCategory\x12\x1f.categories.MoveCategoryRequest\x1a\x14.categories.Category\x12H\n" +
+	"\x0eRemoveCategory\x12!.categories.RemoveCategoryRequest\x1a\x13.categories.Removed\x12K\n" +
+	"\rListQuestions\x12 .categories.ListQuestionsRequest\x1a\x18.categories.QuestionList\x12C\n" +
+	"\vGetQuestion\x12\x1e.categories.GetQuestionRequest\x1a\x14.categories.Question\x12C\n" +
+	"\vAddQuestion\x12\x1e.categories.AddQuestionRequest\x1a\x14.categories.Question\x12I\n" +
+	"\x0eRenameQuestion\x12!.categories.RenameQuestionRequest\x1a\x14.categories.Question\x12I\n" +
+	"\x0eUpdateQuestion\x12!.categories.UpdateQuestionRequest\x1a\x14.categories.Question\x12W\n" +
+	"\x0eRemoveQuestion\x12!.categories.RemoveQuestionRequest\x1a\".categories.RemoveQuestionResponse\x12T\n" +
+	"\x16ListEffectiveQuestions\x12 .categories.ListQuestionsRequest\x1a\x18.categories.QuestionList\x12E\n" +
+	"\fHideQuestion\x12\x1f.categories.HideQuestionRequest\x1a\x14.categories.Question\x12G\n" +
+	"\x0eUnhideQuestion\x12\x1f.categories.HideQuestionRequest\x1a\x14.categories.Question\x12K\n" +
+	"\rNextQuestions\x12 .categories.NextQuestionsRequest\x1a\x18.categories.QuestionList\x12E\n" +
+	"\vListOptions\x12\x1e.categories.ListOptionsRequest\x1a\x16.categories.OptionList\x12=\n" +
+	"\tGetOption\x12\x1c.categories.GetOptionRequest\x1a\x12.categories.Option\x12=\n" +
+	"\tAddOption\x12\x1c.categories.AddOptionRequest\x1a\x12.categories.Option\x12C\n" +
+	"\fRenameOption\x12\x1f.categories.RenameOptionRequest\x1a\x12.categories.Option\x12Q\n" +
+	"\fRemoveOption\x12\x1f.categories.RemoveOptionRequest\x1a .categories.RemoveOptionResponseB/Z-github.com/jgillard/practising-go-tdd/grpc/pbb\x06proto3"
+
+var (
+	file_categories_proto_rawDescOnce sync.Once
+	file_categories_proto_rawDescData []byte
+)
+
+func file_categories_proto_rawDescGZIP() []byte {
+	file_categories_proto_rawDescOnce.Do(func() {
+		file_categories_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_categories_proto_rawDesc), len(file_categories_proto_rawDesc)))
+	})
+	return file_categories_proto_rawDescData
+}
+
+var file_categories_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_categories_proto_goTypes = []any{
+	(*Category)(nil),               // 0: categories.Category
+	(*CategoryList)(nil),           // 1: categories.CategoryList
+	(*ListCategoriesRequest)(nil),  // 2: categories.ListCategoriesRequest
+	(*GetCategoryRequest)(nil),     // 3: categories.GetCategoryRequest
+	(*GetCategoryResponse)(nil),    // 4: categories.GetCategoryResponse
+	(*CategoryTree)(nil),           // 5: categories.CategoryTree
+	(*AddCategoryRequest)(nil),     // 6: categories.AddCategoryRequest
+	(*RenameCategoryRequest)(nil),  // 7: categories.RenameCategoryRequest
+	(*MoveCategoryRequest)(nil),    // 8: categories.MoveCategoryRequest
+	(*RemoveCategoryRequest)(nil),  // 9: categories.RemoveCategoryRequest
+	(*Removed)(nil),                // 10: categories.Removed
+	(*Option)(nil),                 // 11: categories.Option
+	(*Question)(nil),               // 12: categories.Question
+	(*Constraints)(nil),            // 13: categories.Constraints
+	(*Dependency)(nil),             // 14: categories.Dependency
+	(*QuestionList)(nil),           // 15: categories.QuestionList
+	(*ListQuestionsRequest)(nil),   // 16: categories.ListQuestionsRequest
+	(*GetQuestionRequest)(nil),     // 17: categories.GetQuestionRequest
+	(*Options)(nil),                // 18: categories.Options
+	(*AddQuestionRequest)(nil),     // 19: categories.AddQuestionRequest
+	(*RenameQuestionRequest)(nil),  // 20: categories.RenameQuestionRequest
+	(*UpdateQuestionRequest)(nil),  // 21: categories.UpdateQuestionRequest
+	(*RemoveQuestionRequest)(nil),  // 22: categories.RemoveQuestionRequest
+	(*RemoveQuestionResponse)(nil), // 23: categories.RemoveQuestionResponse
+	(*HideQuestionRequest)(nil),    // 24: categories.HideQuestionRequest
+	(*Answer)(nil),                 // 25: categories.Answer
+	(*NextQuestionsRequest)(nil),   // 26: categories.NextQuestionsRequest
+	(*OptionList)(nil),             // 27: categories.OptionList
+	(*ListOptionsRequest)(nil),     // 28: categories.ListOptionsRequest
+	(*GetOptionRequest)(nil),       // 29: categories.GetOptionRequest
+	(*AddOptionRequest)(nil),       // 30: categories.AddOptionRequest
+	(*RenameOptionRequest)(nil),    // 31: categories.RenameOptionRequest
+	(*RemoveOptionRequest)(nil),    // 32: categories.RemoveOptionRequest
+	(*RemoveOptionResponse)(nil),   // 33: categories.RemoveOptionResponse
+	(*structpb.Value)(nil),         // 34: google.protobuf.Value
+}
+var file_categories_proto_depIdxs = []int32{
+	0,  // 0: categories.CategoryList.categories:type_name -> categories.Category
+	0,  // 1: categories.GetCategoryResponse.category:type_name -> categories.Category
+	0,  // 2: categories.GetCategoryResponse.children:type_name -> categories.Category
+	0,  // 3: categories.CategoryTree.category:type_name -> categories.Category
+	5,  // 4: categories.CategoryTree.children:type_name -> categories.CategoryTree
+	11, // 5: categories.Question.options:type_name -> categories.Option
+	13, // 6: categories.Question.constraints:type_name -> categories.Constraints
+	14, // 7: categories.Question.depends_on:type_name -> categories.Dependency
+	34, // 8: categories.Constraints.default:type_name -> google.protobuf.Value
+	12, // 9: categories.QuestionList.questions:type_name -> categories.Question
+	18, // 10: categories.AddQuestionRequest.options:type_name -> categories.Options
+	13, // 11: categories.AddQuestionRequest.constraints:type_name -> categories.Constraints
+	14, // 12: categories.AddQuestionRequest.depends_on:type_name -> categories.Dependency
+	13, // 13: categories.UpdateQuestionRequest.constraints:type_name -> categories.Constraints
+	14, // 14: categories.UpdateQuestionRequest.depends_on:type_name -> categories.Dependency
+	34, // 15: categories.Answer.value:type_name -> google.protobuf.Value
+	25, // 16: categories.NextQuestionsRequest.answers:type_name -> categories.Answer
+	11, // 17: categories.OptionList.options:type_name -> categories.Option
+	2,  // 18: categories.Categories.ListCategories:input_type -> categories.ListCategoriesRequest
+	3,  // 19: categories.Categories.GetCategory:input_type -> categories.GetCategoryRequest
+	3,  // 20: categories.Categories.GetCategoryTree:input_type -> categories.GetCategoryRequest
+	6,  // 21: categories.Categories.AddCategory:input_type -> categories.AddCategoryRequest
+	7,  // 22: categories.Categories.RenameCategory:input_type -> categories.RenameCategoryRequest
+	8,  // 23: categories.Categories.MoveCategory:input_type -> categories.MoveCategoryRequest
+	9,  // 24: categories.Categories.RemoveCategory:input_type -> categories.RemoveCategoryRequest
+	16, // 25: categories.Categories.ListQuestions:input_type -> categories.ListQuestionsRequest
+	17, // 26: categories.Categories.GetQuestion:input_type -> categories.GetQuestionRequest
+	19, // 27: categories.Categories.AddQuestion:input_type -> categories.AddQuestionRequest
+	20, // 28: categories.Categories.RenameQuestion:input_type -> categories.RenameQuestionRequest
+	21, // 29: categories.Categories.UpdateQuestion:input_type -> categories.UpdateQuestionRequest
+	22, // 30: categories.Categories.RemoveQuestion:input_type -> categories.RemoveQuestionRequest
+	16, // 31: categories.Categories.ListEffectiveQuestions:input_type -> categories.ListQuestionsRequest
+	24, // 32: categories.Categories.HideQuestion:input_type -> categories.HideQuestionRequest
+	24, // 33: categories.Categories.UnhideQuestion:input_type -> categories.HideQuestionRequest
+	26, // 34: categories.Categories.NextQuestions:input_type -> categories.NextQuestionsRequest
+	28, // 35: categories.Categories.ListOptions:input_type -> categories.ListOptionsRequest
+	29, // 36: categories.Categories.GetOption:input_type -> categories.GetOptionRequest
+	30, // 37: categories.Categories.AddOption:input_type -> categories.AddOptionRequest
+	31, // 38: categories.Categories.RenameOption:input_type -> categories.RenameOptionRequest
+	32, // 39: categories.Categories.RemoveOption:input_type -> categories.RemoveOptionRequest
+	1,  // 40: categories.Categories.ListCategories:output_type -> categories.CategoryList
+	4,  // 41: categories.Categories.GetCategory:output_type -> categories.GetCategoryResponse
+	5,  // 42: categories.Categories.GetCategoryTree:output_type -> categories.CategoryTree
+	0,  // 43: categories.Categories.AddCategory:output_type -> categories.Category
+	0,  // 44: categories.Categories.RenameCategory:output_type -> categories.Category
+	0,  // 45: categories.Categories.MoveCategory:output_type -> categories.Category
+	10, // 46: categories.Categories.RemoveCategory:output_type -> categories.Removed
+	15, // 47: categories.Categories.ListQuestions:output_type -> categories.QuestionList
+	12, // 48: categories.Categories.GetQuestion:output_type -> categories.Question
+	12, // 49: categories.Categories.AddQuestion:output_type -> categories.Question
+	12, // 50: categories.Categories.RenameQuestion:output_type -> categories.Question
+	12, // 51: categories.Categories.UpdateQuestion:output_type -> categories.Question
+	23, // 52: categories.Categories.RemoveQuestion:output_type -> categories.RemoveQuestionResponse
+	15, // 53: categories.Categories.ListEffectiveQuestions:output_type -> categories.QuestionList
+	12, // 54: categories.Categories.HideQuestion:output_type -> categories.Question
+	12, // 55: categories.Categories.UnhideQuestion:output_type -> categories.Question
+	15, // 56: categories.Categories.NextQuestions:output_type -> categories.QuestionList
+	27, // 57: categories.Categories.ListOptions:output_type -> categories.OptionList
+	11, // 58: categories.Categories.GetOption:output_type -> categories.Option
+	11, // 59: categories.Categories.AddOption:output_type -> categories.Option
+	11, // 60: categories.Categories.RenameOption:output_type -> categories.Option
+	33, // 61: categories.Categories.RemoveOption:output_type -> categories.RemoveOptionResponse
+	40, // [40:62] is the sub-list for method output_type
+	18, // [18:40] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_categories_proto_init() }
+func file_categories_proto_init() {
+	if File_categories_proto != nil {
+		return
+	}
+	file_categories_proto_msgTypes[6].OneofWrappers = []any{}
+	file_categories_proto_msgTypes[8].OneofWrappers = []any{}
+	file_categories_proto_msgTypes[13].OneofWrappers = []any{}
+	file_categories_proto_msgTypes[14].OneofWrappers = []any{}
+	file_categories_proto_msgTypes[21].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_categories_proto_rawDesc), len(file_categories_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   34,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_categories_proto_goTypes,
+		DependencyIndexes: file_categories_proto_depIdxs,
+		MessageInfos:      file_categories_proto_msgTypes,
+	}.Build()
+	File_categories_proto = out.File
+	file_categories_proto_goTypes = nil
+	file_categories_proto_depIdxs = nil
+}
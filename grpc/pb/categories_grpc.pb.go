@@ -0,0 +1,926 @@
+// The gRPC interface to categories & their questions,
+// behaving exactly as the HTTP API does, see internal.Service
+//
+// Regenerate categories.pb.go & categories_grpc.pb.go after changing this file with
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative categories.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: categories.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Categories_ListCategories_FullMethodName         = "/categories.Categories/ListCategories"
+	Categories_GetCategory_FullMethodName            = "/categories.Categories/GetCategory"
+	Categories_GetCategoryTree_FullMethodName        = "/categories.Categories/GetCategoryTree"
+	Categories_AddCategory_FullMethodName            = "/categories.Categories/AddCategory"
+	Categories_RenameCategory_FullMethodName         = "/categories.Categories/RenameCategory"
+	Categories_MoveCategory_FullMethodName           = "/categories.Categories/MoveCategory"
+	Categories_RemoveCategory_FullMethodName         = "/categories.Categories/RemoveCategory"
+	Categories_ListQuestions_FullMethodName          = "/categories.Categories/ListQuestions"
+	Categories_GetQuestion_FullMethodName            = "/categories.Categories/GetQuestion"
+	Categories_AddQuestion_FullMethodName            = "/categories.Categories/AddQuestion"
+	Categories_RenameQuestion_FullMethodName         = "/categories.Categories/RenameQuestion"
+	Categories_UpdateQuestion_FullMethodName         = "/categories.Categories/UpdateQuestion"
+	Categories_RemoveQuestion_FullMethodName         = "/categories.Categories/RemoveQuestion"
+	Categories_ListEffectiveQuestions_FullMethodName = "/categories.Categories/ListEffectiveQuestions"
+	Categories_HideQuestion_FullMethodName           = "/categories.Categories/HideQuestion"
+	Categories_UnhideQuestion_FullMethodName         = "/categories.Categories/UnhideQuestion"
+	Categories_NextQuestions_FullMethodName          = "/categories.Categories/NextQuestions"
+	Categories_ListOptions_FullMethodName            = "/categories.Categories/ListOptions"
+	Categories_GetOption_FullMethodName              = "/categories.Categories/GetOption"
+	Categories_AddOption_FullMethodName              = "/categories.Categories/AddOption"
+	Categories_RenameOption_FullMethodName           = "/categories.Categories/RenameOption"
+	Categories_RemoveOption_FullMethodName           = "/categories.Categories/RemoveOption"
+)
+
+// CategoriesClient is the client API for Categories service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CategoriesClient interface {
+	ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*CategoryList, error)
+	GetCategory(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (*GetCategoryResponse, error)
+	GetCategoryTree(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (*CategoryTree, error)
+	AddCategory(ctx context.Context, in *AddCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	RenameCategory(ctx context.Context, in *RenameCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	MoveCategory(ctx context.Context, in *MoveCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	RemoveCategory(ctx context.Context, in *RemoveCategoryRequest, opts ...grpc.CallOption) (*Removed, error)
+	ListQuestions(ctx context.Context, in *ListQuestionsRequest, opts ...grpc.CallOption) (*QuestionList, error)
+	GetQuestion(ctx context.Context, in *GetQuestionRequest, opts ...grpc.CallOption) (*Question, error)
+	AddQuestion(ctx context.Context, in *AddQuestionRequest, opts ...grpc.CallOption) (*Question, error)
+	RenameQuestion(ctx context.Context, in *RenameQuestionRequest, opts ...grpc.CallOption) (*Question, error)
+	UpdateQuestion(ctx context.Context, in *UpdateQuestionRequest, opts ...grpc.CallOption) (*Question, error)
+	RemoveQuestion(ctx context.Context, in *RemoveQuestionRequest, opts ...grpc.CallOption) (*RemoveQuestionResponse, error)
+	ListEffectiveQuestions(ctx context.Context, in *ListQuestionsRequest, opts ...grpc.CallOption) (*QuestionList, error)
+	HideQuestion(ctx context.Context, in *HideQuestionRequest, opts ...grpc.CallOption) (*Question, error)
+	UnhideQuestion(ctx context.Context, in *HideQuestionRequest, opts ...grpc.CallOption) (*Question, error)
+	NextQuestions(ctx context.Context, in *NextQuestionsRequest, opts ...grpc.CallOption) (*QuestionList, error)
+	ListOptions(ctx context.Context, in *ListOptionsRequest, opts ...grpc.CallOption) (*OptionList, error)
+	GetOption(ctx context.Context, in *GetOptionRequest, opts ...grpc.CallOption) (*Option, error)
+	AddOption(ctx context.Context, in *AddOptionRequest, opts ...grpc.CallOption) (*Option, error)
+	RenameOption(ctx context.Context, in *RenameOptionRequest, opts ...grpc.CallOption) (*Option, error)
+	RemoveOption(ctx context.Context, in *RemoveOptionRequest, opts ...grpc.CallOption) (*RemoveOptionResponse, error)
+}
+
+type categoriesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCategoriesClient(cc grpc.ClientConnInterface) CategoriesClient {
+	return &categoriesClient{cc}
+}
+
+func (c *categoriesClient) ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*CategoryList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryList)
+	err := c.cc.Invoke(ctx, Categories_ListCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) GetCategory(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (*GetCategoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCategoryResponse)
+	err := c.cc.Invoke(ctx, Categories_GetCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) GetCategoryTree(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (*CategoryTree, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryTree)
+	err := c.cc.Invoke(ctx, Categories_GetCategoryTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) AddCategory(ctx context.Context, in *AddCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Category)
+	err := c.cc.Invoke(ctx, Categories_AddCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) RenameCategory(ctx context.Context, in *RenameCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Category)
+	err := c.cc.Invoke(ctx, Categories_RenameCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) MoveCategory(ctx context.Context, in *MoveCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Category)
+	err := c.cc.Invoke(ctx, Categories_MoveCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) RemoveCategory(ctx context.Context, in *RemoveCategoryRequest, opts ...grpc.CallOption) (*Removed, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Removed)
+	err := c.cc.Invoke(ctx, Categories_RemoveCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) ListQuestions(ctx context.Context, in *ListQuestionsRequest, opts ...grpc.CallOption) (*QuestionList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuestionList)
+	err := c.cc.Invoke(ctx, Categories_ListQuestions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) GetQuestion(ctx context.Context, in *GetQuestionRequest, opts ...grpc.CallOption) (*Question, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Question)
+	err := c.cc.Invoke(ctx, Categories_GetQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) AddQuestion(ctx context.Context, in *AddQuestionRequest, opts ...grpc.CallOption) (*Question, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Question)
+	err := c.cc.Invoke(ctx, Categories_AddQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) RenameQuestion(ctx context.Context, in *RenameQuestionRequest, opts ...grpc.CallOption) (*Question, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Question)
+	err := c.cc.Invoke(ctx, Categories_RenameQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) UpdateQuestion(ctx context.Context, in *UpdateQuestionRequest, opts ...grpc.CallOption) (*Question, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Question)
+	err := c.cc.Invoke(ctx, Categories_UpdateQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) RemoveQuestion(ctx context.Context, in *RemoveQuestionRequest, opts ...grpc.CallOption) (*RemoveQuestionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveQuestionResponse)
+	err := c.cc.Invoke(ctx, Categories_RemoveQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) ListEffectiveQuestions(ctx context.Context, in *ListQuestionsRequest, opts ...grpc.CallOption) (*QuestionList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuestionList)
+	err := c.cc.Invoke(ctx, Categories_ListEffectiveQuestions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) HideQuestion(ctx context.Context, in *HideQuestionRequest, opts ...grpc.CallOption) (*Question, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Question)
+	err := c.cc.Invoke(ctx, Categories_HideQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) UnhideQuestion(ctx context.Context, in *HideQuestionRequest, opts ...grpc.CallOption) (*Question, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Question)
+	err := c.cc.Invoke(ctx, Categories_UnhideQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) NextQuestions(ctx context.Context, in *NextQuestionsRequest, opts ...grpc.CallOption) (*QuestionList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuestionList)
+	err := c.cc.Invoke(ctx, Categories_NextQuestions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) ListOptions(ctx context.Context, in *ListOptionsRequest, opts ...grpc.CallOption) (*OptionList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OptionList)
+	err := c.cc.Invoke(ctx, Categories_ListOptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) GetOption(ctx context.Context, in *GetOptionRequest, opts ...grpc.CallOption) (*Option, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Option)
+	err := c.cc.Invoke(ctx, Categories_GetOption_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) AddOption(ctx context.Context, in *AddOptionRequest, opts ...grpc.CallOption) (*Option, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Option)
+	err := c.cc.Invoke(ctx, Categories_AddOption_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) RenameOption(ctx context.Context, in *RenameOptionRequest, opts ...grpc.CallOption) (*Option, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Option)
+	err := c.cc.Invoke(ctx, Categories_RenameOption_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoriesClient) RemoveOption(ctx context.Context, in *RemoveOptionRequest, opts ...grpc.CallOption) (*RemoveOptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveOptionResponse)
+	err := c.cc.Invoke(ctx, Categories_RemoveOption_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CategoriesServer is the server API for Categories service.
+// All implementations must embed UnimplementedCategoriesServer
+// for forward compatibility.
+type CategoriesServer interface {
+	ListCategories(context.Context, *ListCategoriesRequest) (*CategoryList, error)
+	GetCategory(context.Context, *GetCategoryRequest) (*GetCategoryResponse, error)
+	GetCategoryTree(context.Context, *GetCategoryRequest) (*CategoryTree, error)
+	AddCategory(context.Context, *AddCategoryRequest) (*Category, error)
+	RenameCategory(context.Context, *RenameCategoryRequest) (*Category, error)
+	MoveCategory(context.Context, *MoveCategoryRequest) (*Category, error)
+	RemoveCategory(context.Context, *RemoveCategoryRequest) (*Removed, error)
+	ListQuestions(context.Context, *ListQuestionsRequest) (*QuestionList, error)
+	GetQuestion(context.Context, *GetQuestionRequest) (*Question, error)
+	AddQuestion(context.Context, *AddQuestionRequest) (*Question, error)
+	RenameQuestion(context.Context, *RenameQuestionRequest) (*Question, error)
+	UpdateQuestion(context.Context, *UpdateQuestionRequest) (*Question, error)
+	RemoveQuestion(context.Context, *RemoveQuestionRequest) (*RemoveQuestionResponse, error)
+	ListEffectiveQuestions(context.Context, *ListQuestionsRequest) (*QuestionList, error)
+	HideQuestion(context.Context, *HideQuestionRequest) (*Question, error)
+	UnhideQuestion(context.Context, *HideQuestionRequest) (*Question, error)
+	NextQuestions(context.Context, *NextQuestionsRequest) (*QuestionList, error)
+	ListOptions(context.Context, *ListOptionsRequest) (*OptionList, error)
+	GetOption(context.Context, *GetOptionRequest) (*Option, error)
+	AddOption(context.Context, *AddOptionRequest) (*Option, error)
+	RenameOption(context.Context, *RenameOptionRequest) (*Option, error)
+	RemoveOption(context.Context, *RemoveOptionRequest) (*RemoveOptionResponse, error)
+	mustEmbedUnimplementedCategoriesServer()
+}
+
+// UnimplementedCategoriesServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCategoriesServer struct{}
+
+func (UnimplementedCategoriesServer) ListCategories(context.Context, *ListCategoriesRequest) (*CategoryList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCategories not implemented")
+}
+func (UnimplementedCategoriesServer) GetCategory(context.Context, *GetCategoryRequest) (*GetCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategory not implemented")
+}
+func (UnimplementedCategoriesServer) GetCategoryTree(context.Context, *GetCategoryRequest) (*CategoryTree, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategoryTree not implemented")
+}
+func (UnimplementedCategoriesServer) AddCategory(context.Context, *AddCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCategory not implemented")
+}
+func (UnimplementedCategoriesServer) RenameCategory(context.Context, *RenameCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameCategory not implemented")
+}
+func (UnimplementedCategoriesServer) MoveCategory(context.Context, *MoveCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveCategory not implemented")
+}
+func (UnimplementedCategoriesServer) RemoveCategory(context.Context, *RemoveCategoryRequest) (*Removed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveCategory not implemented")
+}
+func (UnimplementedCategoriesServer) ListQuestions(context.Context, *ListQuestionsRequest) (*QuestionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQuestions not implemented")
+}
+func (UnimplementedCategoriesServer) GetQuestion(context.Context, *GetQuestionRequest) (*Question, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuestion not implemented")
+}
+func (UnimplementedCategoriesServer) AddQuestion(context.Context, *AddQuestionRequest) (*Question, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddQuestion not implemented")
+}
+func (UnimplementedCategoriesServer) RenameQuestion(context.Context, *RenameQuestionRequest) (*Question, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameQuestion not implemented")
+}
+func (UnimplementedCategoriesServer) UpdateQuestion(context.Context, *UpdateQuestionRequest) (*Question, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateQuestion not implemented")
+}
+func (UnimplementedCategoriesServer) RemoveQuestion(context.Context, *RemoveQuestionRequest) (*RemoveQuestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveQuestion not implemented")
+}
+func (UnimplementedCategoriesServer) ListEffectiveQuestions(context.Context, *ListQuestionsRequest) (*QuestionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEffectiveQuestions not implemented")
+}
+func (UnimplementedCategoriesServer) HideQuestion(context.Context, *HideQuestionRequest) (*Question, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HideQuestion not implemented")
+}
+func (UnimplementedCategoriesServer) UnhideQuestion(context.Context, *HideQuestionRequest) (*Question, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnhideQuestion not implemented")
+}
+func (UnimplementedCategoriesServer) NextQuestions(context.Context, *NextQuestionsRequest) (*QuestionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NextQuestions not implemented")
+}
+func (UnimplementedCategoriesServer) ListOptions(context.Context, *ListOptionsRequest) (*OptionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOptions not implemented")
+}
+func (UnimplementedCategoriesServer) GetOption(context.Context, *GetOptionRequest) (*Option, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOption not implemented")
+}
+func (UnimplementedCategoriesServer) AddOption(context.Context, *AddOptionRequest) (*Option, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOption not implemented")
+}
+func (UnimplementedCategoriesServer) RenameOption(context.Context, *RenameOptionRequest) (*Option, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameOption not implemented")
+}
+func (UnimplementedCategoriesServer) RemoveOption(context.Context, *RemoveOptionRequest) (*RemoveOptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveOption not implemented")
+}
+func (UnimplementedCategoriesServer) mustEmbedUnimplementedCategoriesServer() {}
+func (UnimplementedCategoriesServer) testEmbeddedByValue()                    {}
+
+// UnsafeCategoriesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CategoriesServer will
+// result in compilation errors.
+type UnsafeCategoriesServer interface {
+	mustEmbedUnimplementedCategoriesServer()
+}
+
+func RegisterCategoriesServer(s grpc.ServiceRegistrar, srv CategoriesServer) {
+	// If the following call pancis, it indicates UnimplementedCategoriesServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Categories_ServiceDesc, srv)
+}
+
+func _Categories_ListCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).ListCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_ListCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).ListCategories(ctx, req.(*ListCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_GetCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).GetCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_GetCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).GetCategory(ctx, req.(*GetCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_GetCategoryTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).GetCategoryTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_GetCategoryTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).GetCategoryTree(ctx, req.(*GetCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_AddCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).AddCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_AddCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).AddCategory(ctx, req.(*AddCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_RenameCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).RenameCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_RenameCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).RenameCategory(ctx, req.(*RenameCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_MoveCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).MoveCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_MoveCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).MoveCategory(ctx, req.(*MoveCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_RemoveCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).RemoveCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_RemoveCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).RemoveCategory(ctx, req.(*RemoveCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_ListQuestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQuestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).ListQuestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_ListQuestions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).ListQuestions(ctx, req.(*ListQuestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_GetQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).GetQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_GetQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).GetQuestion(ctx, req.(*GetQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_AddQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).AddQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_AddQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).AddQuestion(ctx, req.(*AddQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_RenameQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).RenameQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_RenameQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).RenameQuestion(ctx, req.(*RenameQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_UpdateQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).UpdateQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_UpdateQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).UpdateQuestion(ctx, req.(*UpdateQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_RemoveQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).RemoveQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_RemoveQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).RemoveQuestion(ctx, req.(*RemoveQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_ListEffectiveQuestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQuestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).ListEffectiveQuestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_ListEffectiveQuestions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).ListEffectiveQuestions(ctx, req.(*ListQuestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_HideQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HideQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).HideQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_HideQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).HideQuestion(ctx, req.(*HideQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_UnhideQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HideQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).UnhideQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_UnhideQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).UnhideQuestion(ctx, req.(*HideQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_NextQuestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NextQuestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).NextQuestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_NextQuestions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).NextQuestions(ctx, req.(*NextQuestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_ListOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).ListOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_ListOptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).ListOptions(ctx, req.(*ListOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_GetOption_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).GetOption(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_GetOption_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).GetOption(ctx, req.(*GetOptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_AddOption_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).AddOption(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_AddOption_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).AddOption(ctx, req.(*AddOptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_RenameOption_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameOptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).RenameOption(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_RenameOption_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).RenameOption(ctx, req.(*RenameOptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Categories_RemoveOption_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveOptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoriesServer).RemoveOption(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Categories_RemoveOption_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoriesServer).RemoveOption(ctx, req.(*RemoveOptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Categories_ServiceDesc is the grpc.ServiceDesc for Categories service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Categories_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "categories.Categories",
+	HandlerType: (*CategoriesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCategories",
+			Handler:    _Categories_ListCategories_Handler,
+		},
+		{
+			MethodName: "GetCategory",
+			Handler:    _Categories_GetCategory_Handler,
+		},
+		{
+			MethodName: "GetCategoryTree",
+			Handler:    _Categories_GetCategoryTree_Handler,
+		},
+		{
+			MethodName: "AddCategory",
+			Handler:    _Categories_AddCategory_Handler,
+		},
+		{
+			MethodName: "RenameCategory",
+			Handler:    _Categories_RenameCategory_Handler,
+		},
+		{
+			MethodName: "MoveCategory",
+			Handler:    _Categories_MoveCategory_Handler,
+		},
+		{
+			MethodName: "RemoveCategory",
+			Handler:    _Categories_RemoveCategory_Handler,
+		},
+		{
+			MethodName: "ListQuestions",
+			Handler:    _Categories_ListQuestions_Handler,
+		},
+		{
+			MethodName: "GetQuestion",
+			Handler:    _Categories_GetQuestion_Handler,
+		},
+		{
+			MethodName: "AddQuestion",
+			Handler:    _Categories_AddQuestion_Handler,
+		},
+		{
+			MethodName: "RenameQuestion",
+			Handler:    _Categories_RenameQuestion_Handler,
+		},
+		{
+			MethodName: "UpdateQuestion",
+			Handler:    _Categories_UpdateQuestion_Handler,
+		},
+		{
+			MethodName: "RemoveQuestion",
+			Handler:    _Categories_RemoveQuestion_Handler,
+		},
+		{
+			MethodName: "ListEffectiveQuestions",
+			Handler:    _Categories_ListEffectiveQuestions_Handler,
+		},
+		{
+			MethodName: "HideQuestion",
+			Handler:    _Categories_HideQuestion_Handler,
+		},
+		{
+			MethodName: "UnhideQuestion",
+			Handler:    _Categories_UnhideQuestion_Handler,
+		},
+		{
+			MethodName: "NextQuestions",
+			Handler:    _Categories_NextQuestions_Handler,
+		},
+		{
+			MethodName: "ListOptions",
+			Handler:    _Categories_ListOptions_Handler,
+		},
+		{
+			MethodName: "GetOption",
+			Handler:    _Categories_GetOption_Handler,
+		},
+		{
+			MethodName: "AddOption",
+			Handler:    _Categories_AddOption_Handler,
+		},
+		{
+			MethodName: "RenameOption",
+			Handler:    _Categories_RenameOption_Handler,
+		},
+		{
+			MethodName: "RemoveOption",
+			Handler:    _Categories_RemoveOption_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "categories.proto",
+}
@@ -0,0 +1,145 @@
+package grpctransport
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/jgillard/practising-go-tdd/auth"
+	"github.com/jgillard/practising-go-tdd/grpc/pb"
+	internal "github.com/jgillard/practising-go-tdd/internal"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// userIDKey is the metadata naming the user a call acts for,
+// behaving as the HTTP API's X-User-ID header does
+const userIDKey = "x-user-id"
+
+// Server serves categories & questions over gRPC, see pb.CategoriesServer
+// Every call goes through an internal.Service, so behaves exactly as over HTTP
+type Server struct {
+	pb.UnimplementedCategoriesServer
+
+	service *internal.Service
+
+	// see SetAuthentication
+	authentication *auth.Authentication
+}
+
+// NewServer returns a gRPC server over the service,
+// which should be shared with any other transport over the same stores
+// e.g. NewServer(httpServer.Service())
+func NewServer(service *internal.Service) *Server {
+	return &Server{service: service}
+}
+
+// SetAuthentication requires every call to be authenticated, see auth.Authentication,
+// with its credentials (e.g. x-api-key or authorization) sent as metadata
+// Calls then act for the authenticated user, and are limited to the methods their role permits, see methodRoles
+// Call it before serving any calls
+func (s *Server) SetAuthentication(authentication *auth.Authentication) {
+	s.authentication = authentication
+}
+
+// GRPCServer returns a grpc.Server with the Server registered, ready to Serve a listener
+func (s *Server) GRPCServer() *grpc.Server {
+	g := grpc.NewServer(grpc.UnaryInterceptor(s.intercept))
+	pb.RegisterCategoriesServer(g, s)
+	return g
+}
+
+// intercept is the gRPC equivalent of the HTTP middleware,
+// working out who a call acts for, checking they may make it, and recovering from panics
+func (s *Server) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handle grpc.UnaryHandler) (res interface{}, err error) {
+	// a panic in a method or store fails only its own call, not the whole server
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("panic serving %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			res, err = nil, status.Error(codes.Internal, errorInternal)
+		}
+	}()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	userID := ""
+	if values := md.Get(userIDKey); len(values) > 0 {
+		userID = values[0]
+	}
+
+	// until SetAuthentication is called, calls act for whoever x-user-id names and may call any method
+	if s.authentication != nil {
+		identity, err := s.authentication.Authenticate(credentialsFromMetadata(md))
+
+		switch {
+		case err == auth.ErrNoCredentials:
+			return nil, status.Error(codes.Unauthenticated, auth.ErrorAuthenticationRequired)
+		case err != nil:
+			return nil, status.Error(codes.Unauthenticated, auth.ErrorInvalidCredentials)
+		case userID != "" && userID != identity.UserID:
+			return nil, status.Error(codes.PermissionDenied, auth.ErrorForbiddenUser)
+		case identity.Role < methodRole(info.FullMethod):
+			return nil, status.Error(codes.PermissionDenied, auth.ErrorForbiddenRole)
+		}
+
+		userID = identity.UserID
+	}
+
+	return handle(internal.WithUser(ctx, userID), req)
+}
+
+// credentialsFromMetadata gives a call's metadata to the Authenticators as headers,
+// its lowercase keys (e.g. x-api-key) canonicalised as headers' are
+func credentialsFromMetadata(md metadata.MD) http.Header {
+	credentials := http.Header{}
+	for key, values := range md {
+		for _, value := range values {
+			credentials.Add(key, value)
+		}
+	}
+	return credentials
+}
+
+// methodRoles is the least role permitted to call each method,
+// matching the roles for the equivalent HTTP routes
+var methodRoles = map[string]auth.Role{
+	pb.Categories_ListCategories_FullMethodName:  auth.RoleViewer,
+	pb.Categories_GetCategory_FullMethodName:     auth.RoleViewer,
+	pb.Categories_GetCategoryTree_FullMethodName: auth.RoleViewer,
+	pb.Categories_AddCategory_FullMethodName:     auth.RoleEditor,
+	pb.Categories_RenameCategory_FullMethodName:  auth.RoleEditor,
+	pb.Categories_MoveCategory_FullMethodName:    auth.RoleAdmin,
+	pb.Categories_RemoveCategory_FullMethodName:  auth.RoleAdmin,
+
+	pb.Categories_ListQuestions_FullMethodName:  auth.RoleViewer,
+	pb.Categories_GetQuestion_FullMethodName:    auth.RoleViewer,
+	pb.Categories_AddQuestion_FullMethodName:    auth.RoleEditor,
+	pb.Categories_RenameQuestion_FullMethodName: auth.RoleEditor,
+	pb.Categories_UpdateQuestion_FullMethodName: auth.RoleEditor,
+	pb.Categories_RemoveQuestion_FullMethodName: auth.RoleAdmin,
+
+	pb.Categories_ListEffectiveQuestions_FullMethodName: auth.RoleViewer,
+	pb.Categories_HideQuestion_FullMethodName:           auth.RoleEditor,
+	pb.Categories_UnhideQuestion_FullMethodName:         auth.RoleEditor,
+	pb.Categories_NextQuestions_FullMethodName:          auth.RoleViewer,
+
+	pb.Categories_ListOptions_FullMethodName:  auth.RoleViewer,
+	pb.Categories_GetOption_FullMethodName:    auth.RoleViewer,
+	pb.Categories_AddOption_FullMethodName:    auth.RoleEditor,
+	pb.Categories_RenameOption_FullMethodName: auth.RoleEditor,
+	pb.Categories_RemoveOption_FullMethodName: auth.RoleAdmin,
+}
+
+// methodRole returns the role needed to call the method,
+// any method missing from methodRoles is for admins only
+func methodRole(fullMethod string) auth.Role {
+	role, ok := methodRoles[fullMethod]
+	if !ok {
+		return auth.RoleAdmin
+	}
+	return role
+}
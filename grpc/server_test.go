@@ -0,0 +1,146 @@
+package grpctransport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jgillard/practising-go-tdd/auth"
+	"github.com/jgillard/practising-go-tdd/grpc/pb"
+	httptransport "github.com/jgillard/practising-go-tdd/http"
+	internal "github.com/jgillard/practising-go-tdd/internal"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAuthentication(t *testing.T) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: "", Owner: "alice"},
+		},
+	}
+	server := newTestServer(&categoryList, nil, nil)
+	server.SetAuthentication(&auth.Authentication{
+		Authenticators: []auth.Authenticator{
+			auth.APIKeys{
+				"viewer-key": {UserID: "alice", Role: auth.RoleViewer},
+				"admin-key":  {UserID: "alice", Role: auth.RoleAdmin},
+			},
+		},
+	})
+	client, stop := newTestClient(t, server)
+	defer stop()
+
+	withMetadata := func(pairs ...string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, pairs...)
+	}
+
+	t.Run("calls without credentials are unauthenticated", func(t *testing.T) {
+		_, err := client.ListCategories(ctx, &pb.ListCategoriesRequest{})
+
+		assertStatus(t, err, codes.Unauthenticated, auth.ErrorAuthenticationRequired)
+	})
+
+	t.Run("calls with the wrong credentials are unauthenticated", func(t *testing.T) {
+		_, err := client.ListCategories(withMetadata("x-api-key", "wrong-key"), &pb.ListCategoriesRequest{})
+
+		assertStatus(t, err, codes.Unauthenticated, auth.ErrorInvalidCredentials)
+	})
+
+	t.Run("calls act for the authenticated user", func(t *testing.T) {
+		categories, err := client.ListCategories(withMetadata("x-api-key", "viewer-key"), &pb.ListCategoriesRequest{})
+
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(categories.GetCategories()), 1)
+	})
+
+	t.Run("calls cannot act for another user", func(t *testing.T) {
+		_, err := client.ListCategories(withMetadata("x-api-key", "viewer-key", userIDKey, "bob"), &pb.ListCategoriesRequest{})
+
+		assertStatus(t, err, codes.PermissionDenied, auth.ErrorForbiddenUser)
+	})
+
+	t.Run("roles are enforced", func(t *testing.T) {
+		_, err := client.RemoveCategory(withMetadata("x-api-key", "viewer-key"), &pb.RemoveCategoryRequest{Id: "1234"})
+		assertStatus(t, err, codes.PermissionDenied, auth.ErrorForbiddenRole)
+
+		_, err = client.RemoveCategory(withMetadata("x-api-key", "admin-key"), &pb.RemoveCategoryRequest{Id: "1234"})
+		assertNoError(t, err)
+	})
+}
+
+func TestUsersAreIsolated(t *testing.T) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: "", Owner: "alice"},
+		},
+	}
+	client, stop := newTestClient(t, newTestServer(&categoryList, nil, nil))
+	defer stop()
+
+	asBob := metadata.AppendToOutgoingContext(ctx, userIDKey, "bob")
+
+	_, err := client.GetCategory(asBob, &pb.GetCategoryRequest{Id: "1234"})
+	assertStatus(t, err, codes.NotFound, internal.ErrorCategoryNotFound)
+
+	added, err := client.AddCategory(asBob, &pb.AddCategoryRequest{Name: "accommodation", ParentId: proto.String("")})
+	assertNoError(t, err)
+	assertStringsEqual(t, added.GetOwner(), "bob")
+}
+
+// panickingCategoryStore panics listing categories, as a buggy store might
+type panickingCategoryStore struct {
+	*internal.InMemoryCategoryStore
+}
+
+func (s panickingCategoryStore) ListCategories(ctx context.Context) (internal.CategoryList, error) {
+	panic("store exploded")
+}
+
+func TestPanicsFailOnlyTheirCall(t *testing.T) {
+	categoryStore := panickingCategoryStore{internal.NewInMemoryCategoryStore(nil)}
+	client, stop := newTestClient(t, NewServer(internal.NewService(categoryStore, internal.NewInMemoryQuestionStore(nil), nil)))
+	defer stop()
+
+	_, err := client.ListCategories(ctx, &pb.ListCategoriesRequest{})
+	assertStatus(t, err, codes.Internal, errorInternal)
+
+	_, err = client.GetCategory(ctx, &pb.GetCategoryRequest{Id: "1234"})
+	assertStatus(t, err, codes.NotFound, internal.ErrorCategoryNotFound)
+}
+
+func TestSharesStoresWithHTTP(t *testing.T) {
+	categoryStore := internal.NewInMemoryCategoryStore(nil)
+	httpServer := httptransport.NewServer(categoryStore, internal.NewInMemoryQuestionStore(nil), internal.NewInMemoryTransactionStore(nil))
+	client, stop := newTestClient(t, NewServer(httpServer.Service()))
+	defer stop()
+
+	added, err := client.AddCategory(ctx, &pb.AddCategoryRequest{Name: "accommodation", ParentId: proto.String("")})
+	assertNoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, "/categories/"+added.GetId(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := httptest.NewRecorder()
+	httpServer.ServeHTTP(res, req)
+
+	assertNumbersEqual(t, res.Result().StatusCode, http.StatusOK)
+	if !strings.Contains(res.Body.String(), `"name":"accommodation"`) {
+		t.Errorf("got body '%s' wanted the category added over gRPC", res.Body.String())
+	}
+
+	// the HTTP server now refuses the same name, as the gRPC server would
+	req, err = http.NewRequest(http.MethodPost, "/categories", strings.NewReader(`{"name":"accommodation","parentID":""}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res = httptest.NewRecorder()
+	httpServer.ServeHTTP(res, req)
+
+	assertNumbersEqual(t, res.Result().StatusCode, http.StatusConflict)
+}
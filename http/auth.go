@@ -0,0 +1,37 @@
+package httptransport
+
+import (
+	"net/http"
+
+	"github.com/jgillard/practising-go-tdd/auth"
+)
+
+// apiKeyQueryParam carries the API key for the Monzo webhook, which can't set the X-API-Key header
+// Nothing else may send it that way, as query strings end up in proxy & access logs
+const apiKeyQueryParam = "api_key"
+
+// credentials are what a request is authenticated with, its headers,
+// plus the API key from its query string when it's to the Monzo webhook
+func credentials(req *http.Request) http.Header {
+	if req.URL.Path != monzoWebhookPath || req.Header.Get(auth.APIKeyHeader) != "" {
+		return req.Header
+	}
+
+	key := req.URL.Query().Get(apiKeyQueryParam)
+	if key == "" {
+		return req.Header
+	}
+
+	header := req.Header.Clone()
+	header.Set(auth.APIKeyHeader, key)
+	return header
+}
+
+func (m *middleware) isExempt(path string) bool {
+	for _, exempt := range m.exempt {
+		if path == exempt {
+			return true
+		}
+	}
+	return false
+}
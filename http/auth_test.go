@@ -0,0 +1,146 @@
+package httptransport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jgillard/practising-go-tdd/auth"
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+func newAuthTestServer() (*Server, *internal.InMemoryCategoryStore) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: "", Owner: "alice"},
+		},
+	}
+	store := internal.NewInMemoryCategoryStore(&categoryList)
+	server := NewServer(store, nil, nil)
+
+	server.SetAuthentication(&auth.Authentication{
+		Authenticators: []auth.Authenticator{
+			auth.APIKeys{
+				"alices-key":  auth.Identity{UserID: "alice"},
+				"editors-key": auth.Identity{UserID: "alice", Role: auth.RoleEditor},
+			},
+			auth.NewHMACJWT([]byte("not-a-real-secret")),
+		},
+	}, "/status")
+	return server, store
+}
+
+func TestAuthentication(t *testing.T) {
+	server, _ := newAuthTestServer()
+
+	t.Run("test failure responses", func(t *testing.T) {
+		cases := map[string]struct {
+			header     string
+			value      string
+			userID     string
+			want       int
+			errorTitle string
+		}{
+			"no credentials": {
+				want:       http.StatusUnauthorized,
+				errorTitle: auth.ErrorAuthenticationRequired,
+			},
+			"unknown API key": {
+				header:     auth.APIKeyHeader,
+				value:      "bobs-key",
+				want:       http.StatusUnauthorized,
+				errorTitle: auth.ErrorInvalidCredentials,
+			},
+			"malformed token": {
+				header:     "Authorization",
+				value:      "Bearer abc.def",
+				want:       http.StatusUnauthorized,
+				errorTitle: auth.ErrorInvalidCredentials,
+			},
+			"acting for another user": {
+				header:     auth.APIKeyHeader,
+				value:      "alices-key",
+				userID:     "bob",
+				want:       http.StatusForbidden,
+				errorTitle: auth.ErrorForbiddenUser,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				req := newGetRequest(t, "/categories")
+				if c.header != "" {
+					req.Header.Set(c.header, c.value)
+				}
+				if c.userID != "" {
+					req.Header.Set(userIDKey, c.userID)
+				}
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+			})
+		}
+	})
+
+	t.Run("test success responses", func(t *testing.T) {
+		cases := map[string]func(req *http.Request){
+			"API key header": func(req *http.Request) { req.Header.Set(auth.APIKeyHeader, "alices-key") },
+			"naming the same user": func(req *http.Request) {
+				req.Header.Set(auth.APIKeyHeader, "alices-key")
+				req.Header.Set(userIDKey, "alice")
+			},
+		}
+
+		for name, authenticate := range cases {
+			t.Run(name, func(t *testing.T) {
+				req := newGetRequest(t, "/categories")
+				authenticate(req)
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				assertStatusCode(t, result.StatusCode, http.StatusOK)
+
+				// the request acts for the authenticated user
+				var got internal.CategoryList
+				unmarshallInterfaceFromBody(t, body, &got)
+				assertNumbersEqual(t, len(got.Categories), 1)
+			})
+		}
+	})
+
+	t.Run("the API key query parameter is only accepted by the Monzo webhook", func(t *testing.T) {
+		req := newGetRequest(t, "/categories?"+apiKeyQueryParam+"=alices-key")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatusCode(t, res.Result().StatusCode, http.StatusUnauthorized)
+
+		// an event the webhook ignores, so it's acknowledged once authenticated
+		req = newPostRequest(t, monzoWebhookPath+"?"+apiKeyQueryParam+"=editors-key", strings.NewReader(`{"type":"account.created"}`))
+		res = httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatusCode(t, res.Result().StatusCode, http.StatusOK)
+	})
+
+	t.Run("exempt paths don't need credentials", func(t *testing.T) {
+		req := newGetRequest(t, "/status")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatusCode(t, res.Result().StatusCode, http.StatusOK)
+	})
+}
@@ -1,6 +1,68 @@
 package httptransport
 
+import (
+	"net/http"
+
+	"github.com/jgillard/practising-go-tdd/auth"
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
 const (
 	// Generic
-	errorInvalidJSON = "request JSON invalid"
+	errorInvalidJSON       = "request JSON invalid"
+	errorInvalidJSONFields = "request JSON has a field of the wrong type"
+	errorUnreadableBody    = "request body could not be read"
+	errorInternal          = "internal server error"
 )
+
+// errorCodes gives each error above a stable, machine-readable code, see internal.ErrorCode
+var errorCodes = map[string]string{
+	errorInvalidJSON:       "invalid_json",
+	errorInvalidJSONFields: "invalid_json_fields",
+	errorUnreadableBody:    "unreadable_body",
+	errorInternal:          "internal",
+}
+
+// errorCodeUnknown is the code for a title with none, which is a bug
+const errorCodeUnknown = "unknown"
+
+// errorCode returns the code for title, which may be one of the errors above, auth's or internal's
+func errorCode(title string) string {
+	if code, ok := errorCodes[title]; ok {
+		return code
+	}
+	if code, ok := auth.ErrorCode(title); ok {
+		return code
+	}
+	if code, ok := internal.ErrorCode(title); ok {
+		return code
+	}
+	return errorCodeUnknown
+}
+
+// badRequestErrors are the problems with the shape of a request rather than its content,
+// which are reported as a 400 rather than a 422
+var badRequestErrors = map[string]bool{
+	internal.ErrorFieldMissing:           true,
+	internal.ErrorCascadeAndReassignBoth: true,
+	internal.ErrorTitleEmpty:             true,
+	internal.ErrorTypeEmpty:              true,
+	internal.ErrorInvalidType:            true,
+	internal.ErrorOptionsInvalid:         true,
+	internal.ErrorOptionEmpty:            true,
+	internal.ErrorDuplicateOption:        true,
+	internal.ErrorCurrencyEmpty:          true,
+	internal.ErrorTimestampEmpty:         true,
+	internal.ErrorInvalidFrom:            true,
+	internal.ErrorInvalidTo:              true,
+	internal.ErrorFromAfterTo:            true,
+	internal.ErrorInvalidGroupBy:         true,
+}
+
+// validationStatus returns the status to report a problem validating a request with
+func validationStatus(title string) int {
+	if badRequestErrors[title] {
+		return http.StatusBadRequest
+	}
+	return http.StatusUnprocessableEntity
+}
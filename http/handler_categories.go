@@ -2,10 +2,7 @@ package httptransport
 
 import (
 	"fmt"
-	"io/ioutil"
-	"log"
 	"net/http"
-	"reflect"
 
 	"github.com/julienschmidt/httprouter"
 
@@ -26,166 +23,158 @@ type CategoryPostRequest struct {
 	ParentID *string `json:"parentID"`
 }
 
+// CategoryMoveRequest is the parent to move a Category beneath
+// ParentID is a string to allow "" to signify moving to the top level
+type CategoryMoveRequest struct {
+	ParentID *string `json:"parentID"`
+}
+
 func (c *Server) categoryListHandler(res http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	categoryList := c.categoryStore.ListCategories()
+	ctx := req.Context()
 
-	payload := marshallResponse(categoryList)
+	categoryList, err := c.service.ListCategories(ctx)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
 
-	res.Write(payload)
+	writeResponse(res, http.StatusOK, categoryList)
 }
 
 func (c *Server) categoryGetHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
-	categoryID := ps.ByName("category")
+	ctx := req.Context()
 
-	category := c.categoryStore.GetCategory(categoryID)
+	categoryID := ps.ByName("category")
 
-	if reflect.DeepEqual(category, internal.Category{}) {
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorCategoryNotFound))
+	category, children, err := c.service.GetCategory(ctx, categoryID)
+	if err != nil {
+		writeServiceError(res, err)
 		return
 	}
 
-	children := c.categoryStore.GetChildCategories(categoryID)
-
 	responseStruct := CategoryGetResponse{
 		category,
 		children,
 	}
 
-	payload := marshallResponse(responseStruct)
-
-	res.Write(payload)
+	writeResponse(res, http.StatusOK, responseStruct)
 }
 
-func (c *Server) categoryPostHandler(res http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	requestBody, err := ioutil.ReadAll(req.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
+// categoryTreeHandler returns the category with its whole subtree nested beneath it
+func (c *Server) categoryTreeHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
 
-	if !jsonIsValid(requestBody) {
-		res.WriteHeader(http.StatusBadRequest)
-		res.Write(craftErrorPayload(errorInvalidJSON))
-		return
-	}
-
-	var got CategoryPostRequest
-	unmarshallRequest(requestBody, &got)
-
-	categoryName := got.Name
+	categoryID := ps.ByName("category")
 
-	if !ensureJSONFieldsPresent(res, got, CategoryPostRequest{}) {
-		res.Write(craftErrorPayload(internal.ErrorFieldMissing))
+	tree, err := c.service.GetCategoryTree(ctx, categoryID)
+	if err != nil {
+		writeServiceError(res, err)
 		return
 	}
 
-	if c.categoryStore.CategoryNameExists(categoryName) {
-		res.WriteHeader(http.StatusConflict)
-		res.Write(craftErrorPayload(internal.ErrorDuplicateCategoryName))
-		return
-	}
+	writeResponse(res, http.StatusOK, tree)
+}
+
+func (c *Server) categoryPostHandler(res http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	ctx := req.Context()
 
-	if !internal.IsValidCategoryName(categoryName) {
-		res.WriteHeader(http.StatusUnprocessableEntity)
-		res.Write(craftErrorPayload(internal.ErrorInvalidCategoryName))
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
 		return
 	}
 
-	// parentID not supplied
-	if got.ParentID == nil {
-		res.WriteHeader(http.StatusBadRequest)
-		res.Write(craftErrorPayload(internal.ErrorFieldMissing))
+	var got CategoryPostRequest
+	if !unmarshallRequest(res, requestBody, &got) {
 		return
 	}
 
-	parentID := *got.ParentID
-
-	if !c.categoryStore.CategoryIDExists(parentID) && parentID != "" {
-		res.WriteHeader(http.StatusUnprocessableEntity)
-		res.Write(craftErrorPayload(internal.ErrorParentIDNotFound))
+	if !ensureJSONFieldsPresent(res, got, CategoryPostRequest{}) {
 		return
 	}
 
-	// checks for parent already a subcategory (depth zero indexed)
-	// we currently confine to 2 levels of categories
-	if c.categoryStore.GetCategoryDepth(parentID) == 1 {
-		res.WriteHeader(http.StatusUnprocessableEntity)
-		res.Write(craftErrorPayload(internal.ErrorCategoryTooNested))
+	category, err := c.service.AddCategory(ctx, got.Name, got.ParentID)
+	if err != nil {
+		writeServiceError(res, err)
 		return
 	}
 
-	category := c.categoryStore.AddCategory(categoryName, parentID)
-
-	payload := marshallResponse(category)
-
 	res.Header().Set("Location", fmt.Sprintf("/categories/%s", category.ID))
-	res.WriteHeader(http.StatusCreated)
-	res.Write(payload)
+	writeResponse(res, http.StatusCreated, category)
 }
 
 func (c *Server) categoryPatchHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
-	categoryID := ps.ByName("category")
+	ctx := req.Context()
 
-	requestBody, err := ioutil.ReadAll(req.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
+	categoryID := ps.ByName("category")
 
-	if !jsonIsValid(requestBody) {
-		res.WriteHeader(http.StatusBadRequest)
-		res.Write(craftErrorPayload(errorInvalidJSON))
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
 		return
 	}
 
 	var got jsonName
-	unmarshallRequest(requestBody, &got)
-
-	categoryName := got.Name
+	if !unmarshallRequest(res, requestBody, &got) {
+		return
+	}
 
 	if !ensureJSONFieldsPresent(res, got, jsonName{}) {
-		res.Write(craftErrorPayload(internal.ErrorFieldMissing))
 		return
 	}
 
-	if !c.categoryStore.CategoryIDExists(categoryID) {
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorCategoryNotFound))
+	category, err := c.service.RenameCategory(ctx, categoryID, got.Name)
+	if err != nil {
+		writeServiceError(res, err)
 		return
 	}
 
-	if c.categoryStore.CategoryNameExists(categoryName) {
-		res.WriteHeader(http.StatusConflict)
-		res.Write(craftErrorPayload(internal.ErrorDuplicateCategoryName))
+	writeResponse(res, http.StatusOK, category)
+}
+
+// categoryMoveHandler reparents the category, carrying its subtree, questions & transactions with it
+func (c *Server) categoryMoveHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	categoryID := ps.ByName("category")
+
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
 		return
 	}
 
-	if !internal.IsValidCategoryName(categoryName) {
-		res.WriteHeader(http.StatusUnprocessableEntity)
-		res.Write(craftErrorPayload(internal.ErrorInvalidCategoryName))
+	var got CategoryMoveRequest
+	if !unmarshallRequest(res, requestBody, &got) {
 		return
 	}
 
-	category := c.categoryStore.RenameCategory(categoryID, categoryName)
-
-	payload := marshallResponse(category)
+	category, err := c.service.MoveCategory(ctx, categoryID, got.ParentID)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
 
-	res.WriteHeader(http.StatusOK)
-	res.Write(payload)
+	writeResponse(res, http.StatusOK, category)
 }
 
 func (c *Server) categoryDeleteHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
 	categoryID := ps.ByName("category")
 
-	if !c.categoryStore.CategoryIDExists(categoryID) {
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorCategoryNotFound))
+	// subcategories and transactions using the category block the removal,
+	// unless they are removed/uncategorised (?cascade=true),
+	// or the transactions are moved to another category (?reassignTo=<categoryID>)
+	cascade, ok := ensureCascadeValid(res, req)
+	if !ok {
 		return
 	}
 
-	c.categoryStore.DeleteCategory(categoryID)
+	reassignTo := req.URL.Query().Get("reassignTo")
 
-	payload := marshallResponse(jsonStatus{statusDeleted})
+	removed, err := c.service.RemoveCategory(ctx, categoryID, cascade, reassignTo)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
 
-	res.WriteHeader(http.StatusOK)
-	res.Write(payload)
+	writeResponse(res, http.StatusOK, jsonDeleted{statusDeleted, removed})
 }
@@ -21,7 +21,7 @@ func TestListCategories(t *testing.T) {
 		},
 	}
 	store := internal.NewInMemoryCategoryStore(&categoryList)
-	server := NewServer(store, nil)
+	server := NewServer(store, nil, nil)
 
 	t.Run("it returns a json category list", func(t *testing.T) {
 		req := newGetRequest(t, "/categories")
@@ -59,7 +59,7 @@ func TestGetCategory(t *testing.T) {
 		},
 	}
 	store := internal.NewInMemoryCategoryStore(&categoryList)
-	server := NewServer(store, nil)
+	server := NewServer(store, nil, nil)
 
 	t.Run("not-found failure reponse", func(t *testing.T) {
 		req := newGetRequest(t, "/categories/5678")
@@ -71,7 +71,7 @@ func TestGetCategory(t *testing.T) {
 
 		// check the response
 		assertStatusCode(t, result.StatusCode, http.StatusNotFound)
-		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+		assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
 		assertBodyErrorTitle(t, body, internal.ErrorCategoryNotFound)
 	})
 
@@ -121,6 +121,49 @@ func TestGetCategory(t *testing.T) {
 	})
 }
 
+func TestGetCategoryTree(t *testing.T) {
+
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+			internal.Category{ID: "abcdef", Name: "hostel", ParentID: "1234"},
+			internal.Category{ID: "ghijkm", Name: "dorm", ParentID: "abcdef"},
+		},
+	}
+	store := internal.NewInMemoryCategoryStore(&categoryList)
+	server := NewServer(store, nil, nil)
+
+	t.Run("not-found failure reponse", func(t *testing.T) {
+		req := newGetRequest(t, "/categories/5678/tree")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusNotFound)
+		assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+		assertBodyErrorTitle(t, body, internal.ErrorCategoryNotFound)
+	})
+
+	t.Run("get the nested subtree", func(t *testing.T) {
+		req := newGetRequest(t, "/categories/1234/tree")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		got := strings.TrimSpace(res.Body.String())
+		want := `{"id":"1234","name":"accommodation","parentID":"","children":[` +
+			`{"id":"abcdef","name":"hostel","parentID":"1234","children":[` +
+			`{"id":"ghijkm","name":"dorm","parentID":"abcdef","children":[]}]}]}`
+		assertStringsEqual(t, got, want)
+	})
+}
+
 func TestAddCategory(t *testing.T) {
 
 	categoryList := internal.CategoryList{
@@ -130,7 +173,7 @@ func TestAddCategory(t *testing.T) {
 		},
 	}
 	store := internal.NewInMemoryCategoryStore(&categoryList)
-	server := NewServer(store, nil)
+	server := NewServer(store, nil, nil)
 
 	t.Run("test failure responses & effect", func(t *testing.T) {
 		cases := map[string]struct {
@@ -149,12 +192,12 @@ func TestAddCategory(t *testing.T) {
 				errorTitle: internal.ErrorFieldMissing,
 			},
 			"duplicate name": {
-				input:      `{"name":"existing category name"}`,
+				input:      `{"name":"existing category name", "parentID":""}`,
 				want:       http.StatusConflict,
 				errorTitle: internal.ErrorDuplicateCategoryName,
 			},
 			"invalid name": {
-				input:      `{"name":"abc123!@£"}`,
+				input:      `{"name":"abc123!@£", "parentID":""}`,
 				want:       http.StatusUnprocessableEntity,
 				errorTitle: internal.ErrorInvalidCategoryName,
 			},
@@ -187,18 +230,43 @@ func TestAddCategory(t *testing.T) {
 
 				// check the response
 				assertStatusCode(t, result.StatusCode, c.want)
-				assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
 
 				assertBodyErrorTitle(t, body, c.errorTitle)
 
 				// check the store is unmodified
-				got := store.ListCategories()
+				got := listCategories(t, ctx, store)
 				want := categoryList
 				assertDeepEqual(t, got, want)
 			})
 		}
 	})
 
+	t.Run("every field problem is reported at once", func(t *testing.T) {
+		req := newPostRequest(t, "/categories", strings.NewReader(`{"name":"abc123!@£"}`))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// a missing field is more fundamental than an invalid one, so sets the status
+		assertStatusCode(t, result.StatusCode, http.StatusBadRequest)
+		assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+		assertBodyErrors(t, body, []jsonError{
+			{Title: internal.ErrorFieldMissing, Pointer: "/parentID"},
+			{Title: internal.ErrorInvalidCategoryName, Pointer: "/name"},
+		})
+
+		var got jsonProblem
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertStringsEqual(t, got.Type, "about:blank")
+		assertStringsEqual(t, got.Title, http.StatusText(http.StatusBadRequest))
+		assertNumbersEqual(t, got.Status, http.StatusBadRequest)
+
+		assertDeepEqual(t, listCategories(t, ctx, store), categoryList)
+	})
+
 	t.Run("test success response & effect without parentID", func(t *testing.T) {
 		categoryName := "new category name"
 		parentID := ""
@@ -227,7 +295,7 @@ func TestAddCategory(t *testing.T) {
 		assertStringsEqual(t, got.ParentID, parentID)
 
 		// check the store has been modified
-		got = store.ListCategories().Categories[2]
+		got = listCategories(t, ctx, store).Categories[2]
 		assertIsXid(t, got.ID)
 		assertStringsEqual(t, got.Name, categoryName)
 		assertStringsEqual(t, got.ParentID, parentID)
@@ -264,7 +332,7 @@ func TestAddCategory(t *testing.T) {
 		assertStringsEqual(t, got.ParentID, parentID)
 
 		// check the store has been modified
-		got = store.ListCategories().Categories[3]
+		got = listCategories(t, ctx, store).Categories[3]
 		assertIsXid(t, got.ID)
 		assertStringsEqual(t, got.Name, categoryName)
 		assertStringsEqual(t, got.ParentID, parentID)
@@ -282,7 +350,7 @@ func TestRenameCategory(t *testing.T) {
 		},
 	}
 	store := internal.NewInMemoryCategoryStore(&categoryList)
-	server := NewServer(store, nil)
+	server := NewServer(store, nil, nil)
 
 	t.Run("test failure responses & effect", func(t *testing.T) {
 		cases := map[string]struct {
@@ -335,12 +403,12 @@ func TestRenameCategory(t *testing.T) {
 
 				// check the response
 				assertStatusCode(t, result.StatusCode, c.want)
-				assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
 
 				assertBodyErrorTitle(t, body, c.errorTitle)
 
 				// check the store is unmodified
-				got := store.ListCategories()
+				got := listCategories(t, ctx, store)
 				want := categoryList
 				assertDeepEqual(t, got, want)
 			})
@@ -370,12 +438,135 @@ func TestRenameCategory(t *testing.T) {
 		assertStringsEqual(t, responseBody.ParentID, renamedCategory.ParentID)
 
 		// check the store is updated
-		got := store.ListCategories().Categories[0].Name
+		got := listCategories(t, ctx, store).Categories[0].Name
 		want := renamedCategory.Name
 		assertStringsEqual(t, got, want)
 	})
 }
 
+func TestMoveCategory(t *testing.T) {
+
+	newServer := func() (*Server, *internal.InMemoryCategoryStore, internal.CategoryList) {
+		categoryList := internal.CategoryList{
+			Categories: []internal.Category{
+				internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+				internal.Category{ID: "5678", Name: "food", ParentID: ""},
+				internal.Category{ID: "abcdef", Name: "hostel", ParentID: "1234"},
+			},
+		}
+		store := internal.NewInMemoryCategoryStore(&categoryList)
+		return NewServer(store, nil, nil), store, categoryList
+	}
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		cases := map[string]struct {
+			path       string
+			input      string
+			want       int
+			errorTitle string
+		}{
+			"invalid json": {
+				path:       "/categories/abcdef/move",
+				input:      `{"foo":`,
+				want:       http.StatusBadRequest,
+				errorTitle: errorInvalidJSON,
+			},
+			"parentID is missing": {
+				path:       "/categories/abcdef/move",
+				input:      `{}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorFieldMissing,
+			},
+			"category doesn't exist": {
+				path:       "/categories/9999/move",
+				input:      `{"parentID":""}`,
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorCategoryNotFound,
+			},
+			"parentID doesn't exist": {
+				path:       "/categories/abcdef/move",
+				input:      `{"parentID":"9999"}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorParentIDNotFound,
+			},
+			"parentID is a subcategory of the category": {
+				path:       "/categories/1234/move",
+				input:      `{"parentID":"abcdef"}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorParentIDIsDescendant,
+			},
+			"category would be too nested": {
+				path:       "/categories/1234/move",
+				input:      `{"parentID":"5678"}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorCategoryTooNested,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				server, store, categoryList := newServer()
+
+				req := newPostRequest(t, c.path, strings.NewReader(c.input))
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the store is unmodified
+				assertDeepEqual(t, listCategories(t, ctx, store), categoryList)
+			})
+		}
+	})
+
+	t.Run("move a subcategory beneath another category", func(t *testing.T) {
+		server, store, _ := newServer()
+
+		req := newPostRequest(t, "/categories/abcdef/move", strings.NewReader(`{"parentID":"5678"}`))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var got internal.Category
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertStringsEqual(t, got.ParentID, "5678")
+
+		moved, err := store.GetCategory(ctx, "abcdef")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertStringsEqual(t, moved.ParentID, "5678")
+	})
+
+	t.Run("move a subcategory to the top level", func(t *testing.T) {
+		server, store, _ := newServer()
+
+		req := newPostRequest(t, "/categories/abcdef/move", strings.NewReader(`{"parentID":""}`))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatusCode(t, res.Result().StatusCode, http.StatusOK)
+
+		moved, err := store.GetCategory(ctx, "abcdef")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertStringsEqual(t, moved.ParentID, "")
+	})
+}
+
 func TestRemoveCategory(t *testing.T) {
 
 	existingCategory := internal.Category{ID: "1234", Name: "accommodation"}
@@ -385,7 +576,7 @@ func TestRemoveCategory(t *testing.T) {
 		},
 	}
 	store := internal.NewInMemoryCategoryStore(&categoryList)
-	server := NewServer(store, nil)
+	server := NewServer(store, nil, nil)
 
 	t.Run("test failure responses & effect", func(t *testing.T) {
 		cases := map[string]struct {
@@ -411,12 +602,12 @@ func TestRemoveCategory(t *testing.T) {
 
 				// check the response
 				assertStatusCode(t, result.StatusCode, c.want)
-				assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
 
 				assertBodyErrorTitle(t, body, c.errorTitle)
 
 				// check the store is unmodified
-				got := store.ListCategories()
+				got := listCategories(t, ctx, store)
 				want := categoryList
 				assertDeepEqual(t, got, want)
 			})
@@ -437,8 +628,262 @@ func TestRemoveCategory(t *testing.T) {
 		assertBodyJSONIsStatus(t, body, statusDeleted)
 
 		// check store is updated
-		got := len(store.ListCategories().Categories)
+		got := len(listCategories(t, ctx, store).Categories)
 		want := 0
 		assertNumbersEqual(t, got, want)
 	})
 }
+
+func TestRemoveCategoryInUse(t *testing.T) {
+
+	newServer := func() (*Server, *internal.InMemoryCategoryStore, *internal.InMemoryTransactionStore) {
+		categoryList := internal.CategoryList{
+			Categories: []internal.Category{
+				internal.Category{ID: "1234", Name: "accommodation"},
+				internal.Category{ID: "2345", Name: "lodging"},
+			},
+		}
+		questionList := internal.QuestionList{
+			Questions: []internal.Question{
+				internal.Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+			},
+		}
+		transactionList := internal.TransactionList{
+			Transactions: []internal.Transaction{
+				internal.Transaction{ID: "abcdef", Amount: -1200, Currency: "GBP", CategoryID: "1234", Answers: []internal.Answer{
+					{QuestionID: "1", Value: float64(2)},
+				}},
+			},
+		}
+		categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+		questionStore := internal.NewInMemoryQuestionStore(&questionList)
+		transactionStore := internal.NewInMemoryTransactionStore(&transactionList)
+		return NewServer(categoryStore, questionStore, transactionStore), categoryStore, transactionStore
+	}
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		cases := map[string]struct {
+			path       string
+			want       int
+			errorTitle string
+		}{
+			"category in use": {
+				path:       "/categories/1234",
+				want:       http.StatusConflict,
+				errorTitle: internal.ErrorCategoryInUse,
+			},
+			"cascade isn't a boolean": {
+				path:       "/categories/1234?cascade=foo",
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorInvalidCascade,
+			},
+			"cascade and reassignTo": {
+				path:       "/categories/1234?cascade=true&reassignTo=2345",
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorCascadeAndReassignBoth,
+			},
+			"reassignTo doesn't exist": {
+				path:       "/categories/1234?reassignTo=5678",
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorReassignToNotFound,
+			},
+			"reassignTo is the category being removed": {
+				path:       "/categories/1234?reassignTo=1234",
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorReassignToSelf,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				server, categoryStore, transactionStore := newServer()
+
+				req := newDeleteRequest(t, c.path)
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the stores are unmodified
+				assertNumbersEqual(t, len(listCategories(t, ctx, categoryStore).Categories), 2)
+				assertStringsEqual(t, listTransactions(t, ctx, transactionStore).Transactions[0].CategoryID, "1234")
+			})
+		}
+	})
+
+	t.Run("in-use response reports the referencing transactions", func(t *testing.T) {
+		server, _, _ := newServer()
+
+		req := newDeleteRequest(t, "/categories/1234")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusConflict)
+		assertBodyInUseReferences(t, body, 1)
+	})
+
+	t.Run("cascade uncategorises the transactions", func(t *testing.T) {
+		server, categoryStore, transactionStore := newServer()
+
+		req := newDeleteRequest(t, "/categories/1234?cascade=true")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check response
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertBodyJSONIsStatus(t, body, statusDeleted)
+
+		// check stores are updated
+		assertNumbersEqual(t, len(listCategories(t, ctx, categoryStore).Categories), 1)
+		got := listTransactions(t, ctx, transactionStore).Transactions[0]
+		assertStringsEqual(t, got.CategoryID, "")
+		assertNumbersEqual(t, len(got.Answers), 0)
+	})
+
+	t.Run("reassignTo moves the transactions", func(t *testing.T) {
+		server, categoryStore, transactionStore := newServer()
+
+		req := newDeleteRequest(t, "/categories/1234?reassignTo=2345")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check response
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertBodyJSONIsStatus(t, body, statusDeleted)
+
+		// check stores are updated
+		assertNumbersEqual(t, len(listCategories(t, ctx, categoryStore).Categories), 1)
+		got := listTransactions(t, ctx, transactionStore).Transactions[0]
+		assertStringsEqual(t, got.CategoryID, "2345")
+		assertNumbersEqual(t, len(got.Answers), 0)
+	})
+}
+
+func TestRemoveCategoryWithSubcategories(t *testing.T) {
+
+	newServer := func() (*Server, *internal.InMemoryCategoryStore, *internal.InMemoryQuestionStore, *internal.InMemoryTransactionStore) {
+		categoryList := internal.CategoryList{
+			Categories: []internal.Category{
+				internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+				internal.Category{ID: "2345", Name: "hostel", ParentID: "1234"},
+				internal.Category{ID: "3456", Name: "hotel", ParentID: "1234"},
+				internal.Category{ID: "4567", Name: "food and drink", ParentID: ""},
+			},
+		}
+		questionList := internal.QuestionList{
+			Questions: []internal.Question{
+				internal.Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+				internal.Question{ID: "2", Title: "how many beds in dorm?", CategoryID: "2345", Type: "number"},
+				internal.Question{ID: "3", Title: "which meal?", CategoryID: "4567", Type: "number"},
+			},
+		}
+		transactionList := internal.TransactionList{
+			Transactions: []internal.Transaction{
+				internal.Transaction{ID: "abcdef", Amount: -1200, Currency: "GBP", CategoryID: "2345", Answers: []internal.Answer{
+					{QuestionID: "2", Value: float64(8)},
+				}},
+			},
+		}
+		categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+		questionStore := internal.NewInMemoryQuestionStore(&questionList)
+		transactionStore := internal.NewInMemoryTransactionStore(&transactionList)
+		return NewServer(categoryStore, questionStore, transactionStore), categoryStore, questionStore, transactionStore
+	}
+
+	t.Run("rejected without cascade", func(t *testing.T) {
+		server, categoryStore, questionStore, _ := newServer()
+
+		req := newDeleteRequest(t, "/categories/1234")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check the response
+		assertStatusCode(t, result.StatusCode, http.StatusConflict)
+		assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+		assertBodyErrorTitle(t, body, internal.ErrorCategoryHasChildren)
+
+		var got jsonInUseErrors
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertNumbersEqual(t, got.References.Categories, 2)
+
+		// check the stores are unmodified
+		assertNumbersEqual(t, len(listCategories(t, ctx, categoryStore).Categories), 4)
+		assertNumbersEqual(t, len(listQuestions(t, ctx, questionStore).Questions), 3)
+	})
+
+	t.Run("cascade removes the subtree and its questions", func(t *testing.T) {
+		server, categoryStore, questionStore, transactionStore := newServer()
+
+		req := newDeleteRequest(t, "/categories/1234?cascade=true")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check the response
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+		assertBodyJSONIsStatus(t, body, statusDeleted)
+
+		var got jsonDeleted
+		unmarshallInterfaceFromBody(t, body, &got)
+		want := internal.Removed{
+			Categories:             []string{"3456", "2345", "1234"},
+			Questions:              []string{"2", "1"},
+			ReassignedTransactions: 1,
+		}
+		assertDeepEqual(t, got.Removed, want)
+
+		// check the stores are updated
+		wantCategories := internal.CategoryList{
+			Categories: []internal.Category{
+				internal.Category{ID: "4567", Name: "food and drink", ParentID: ""},
+			},
+		}
+		assertDeepEqual(t, listCategories(t, ctx, categoryStore), wantCategories)
+		assertNumbersEqual(t, len(listQuestions(t, ctx, questionStore).Questions), 1)
+		assertStringsEqual(t, listTransactions(t, ctx, transactionStore).Transactions[0].CategoryID, "")
+	})
+
+	t.Run("leaf category removes its own questions", func(t *testing.T) {
+		server, _, questionStore, _ := newServer()
+
+		req := newDeleteRequest(t, "/categories/4567")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+
+		var got jsonDeleted
+		unmarshallInterfaceFromBody(t, body, &got)
+		want := internal.Removed{
+			Categories: []string{"4567"},
+			Questions:  []string{"3"},
+		}
+		assertDeepEqual(t, got.Removed, want)
+
+		assertNumbersEqual(t, len(listQuestions(t, ctx, questionStore).Questions), 2)
+	})
+}
@@ -0,0 +1,106 @@
+package httptransport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+// concurrency is how many requests the concurrency tests send at once
+// run with -race to have the detector check them
+const concurrency = 50
+
+// slowCategoryStore widens the gap between a handler's checks and its changes,
+// giving concurrent requests every chance to interleave there
+type slowCategoryStore struct {
+	*internal.InMemoryCategoryStore
+}
+
+func (s slowCategoryStore) CategoryNameExists(ctx context.Context, categoryName string) (bool, error) {
+	exists, err := s.InMemoryCategoryStore.CategoryNameExists(ctx, categoryName)
+	time.Sleep(time.Millisecond)
+	return exists, err
+}
+
+// sendConcurrently serves every request at once, returning the status codes seen
+func sendConcurrently(server http.Handler, newRequest func(i int) *http.Request) map[int]int {
+	var mu sync.Mutex
+	statusCodes := make(map[int]int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res := httptest.NewRecorder()
+			server.ServeHTTP(res, newRequest(i))
+
+			mu.Lock()
+			statusCodes[res.Code]++
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	return statusCodes
+}
+
+func TestAddCategoryConcurrently(t *testing.T) {
+
+	t.Run("duplicate names can't slip through", func(t *testing.T) {
+		store := internal.NewInMemoryCategoryStore(nil)
+		server := NewServer(slowCategoryStore{store}, nil, nil)
+
+		got := sendConcurrently(server, func(i int) *http.Request {
+			return newPostRequest(t, "/categories", strings.NewReader(`{"name":"accommodation", "parentID":""}`))
+		})
+
+		assertNumbersEqual(t, got[http.StatusCreated], 1)
+		assertNumbersEqual(t, got[http.StatusConflict], concurrency-1)
+		assertNumbersEqual(t, len(listCategories(t, ctx, store).Categories), 1)
+	})
+
+	t.Run("reads alongside writes", func(t *testing.T) {
+		store := internal.NewInMemoryCategoryStore(nil)
+		server := NewServer(store, nil, nil)
+
+		got := sendConcurrently(server, func(i int) *http.Request {
+			if i%2 == 0 {
+				return newGetRequest(t, "/categories")
+			}
+			// names may only contain letters
+			body := fmt.Sprintf(`{"name":"category %c%c", "parentID":""}`, 'a'+i/26, 'a'+i%26)
+			return newPostRequest(t, "/categories", strings.NewReader(body))
+		})
+
+		assertNumbersEqual(t, got[http.StatusOK], concurrency/2)
+		assertNumbersEqual(t, got[http.StatusCreated], concurrency/2)
+		assertNumbersEqual(t, len(listCategories(t, ctx, store).Categories), concurrency/2)
+	})
+}
+
+func TestAddQuestionConcurrently(t *testing.T) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+		},
+	}
+	categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+	questionStore := internal.NewInMemoryQuestionStore(nil)
+	server := NewServer(categoryStore, questionStore, nil)
+
+	got := sendConcurrently(server, func(i int) *http.Request {
+		return newPostRequest(t, "/categories/1234/questions", strings.NewReader(`{"title":"how many nights?", "type":"number"}`))
+	})
+
+	assertNumbersEqual(t, got[http.StatusCreated], 1)
+	assertNumbersEqual(t, got[http.StatusConflict], concurrency-1)
+	assertNumbersEqual(t, len(listQuestions(t, ctx, questionStore).Questions), 1)
+}
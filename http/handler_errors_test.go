@@ -0,0 +1,178 @@
+package httptransport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+// panickingCategoryStore stands in for a store hitting a bug it can't recover from
+type panickingCategoryStore struct {
+	*internal.InMemoryCategoryStore
+}
+
+func (s panickingCategoryStore) ListCategories(ctx context.Context) (internal.CategoryList, error) {
+	panic("category store is broken")
+}
+
+// failingCategoryStore stands in for a backend that can't be reached
+type failingCategoryStore struct {
+	*internal.InMemoryCategoryStore
+}
+
+func (s failingCategoryStore) GetCategory(ctx context.Context, categoryID string) (internal.Category, error) {
+	return internal.Category{}, errors.New("database is unreachable")
+}
+
+// unreadableBody fails every read, as a client disconnecting mid-request would
+type unreadableBody struct{}
+
+func (unreadableBody) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func newErrorTestServer() *Server {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+		},
+	}
+	questionList := internal.QuestionList{
+		Questions: []internal.Question{
+			internal.Question{ID: "5678", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+		},
+	}
+
+	categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+	questionStore := internal.NewInMemoryQuestionStore(&questionList)
+	transactionStore := internal.NewInMemoryTransactionStore(nil)
+
+	return NewServer(panickingCategoryStore{categoryStore}, questionStore, transactionStore)
+}
+
+func TestServerSurvivesErrors(t *testing.T) {
+
+	server := newErrorTestServer()
+
+	// each request would previously have exited the process,
+	// so the status check after them proves the server is still serving
+	assertStillServing := func(t *testing.T) {
+		t.Helper()
+		res := httptest.NewRecorder()
+		server.ServeHTTP(res, newGetRequest(t, "/status"))
+		assertStatusCode(t, res.Code, http.StatusOK)
+	}
+
+	t.Run("a panic is a 500 for that request only", func(t *testing.T) {
+		req := newGetRequest(t, "/categories")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusInternalServerError)
+		assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+		assertBodyErrorTitle(t, body, errorInternal)
+		assertStillServing(t)
+	})
+
+	wrongTypeCases := map[string]struct {
+		method string
+		path   string
+		body   string
+	}{
+		"add category":    {http.MethodPost, "/categories", `{"name": 5}`},
+		"rename category": {http.MethodPatch, "/categories/1234", `{"name": 5}`},
+		"add question":    {http.MethodPost, "/categories/1234/questions", `{"title": 5, "type": "string"}`},
+		"rename question": {http.MethodPatch, "/categories/1234/questions/5678", `{"title": 5}`},
+	}
+
+	for name, c := range wrongTypeCases {
+		t.Run(name+" with a field of the wrong type is a 400", func(t *testing.T) {
+			req, _ := http.NewRequest(c.method, c.path, strings.NewReader(c.body))
+			res := httptest.NewRecorder()
+
+			server.ServeHTTP(res, req)
+			result := res.Result()
+			body := readBodyJSON(t, result.Body)
+
+			assertStatusCode(t, result.StatusCode, http.StatusBadRequest)
+			assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+			assertBodyErrorTitle(t, body, errorInvalidJSONFields)
+			assertStillServing(t)
+		})
+	}
+
+	unreadableCases := map[string]struct {
+		method string
+		path   string
+	}{
+		"add category":       {http.MethodPost, "/categories"},
+		"rename category":    {http.MethodPatch, "/categories/1234"},
+		"add question":       {http.MethodPost, "/categories/1234/questions"},
+		"rename question":    {http.MethodPatch, "/categories/1234/questions/5678"},
+		"add transaction":    {http.MethodPost, "/transactions"},
+		"update transaction": {http.MethodPatch, "/transactions/abcd"},
+		"monzo webhook":      {http.MethodPost, "/webhooks/monzo"},
+	}
+
+	for name, c := range unreadableCases {
+		t.Run(name+" with an unreadable body is a 400", func(t *testing.T) {
+			req, _ := http.NewRequest(c.method, c.path, unreadableBody{})
+			res := httptest.NewRecorder()
+
+			server.ServeHTTP(res, req)
+			result := res.Result()
+			body := readBodyJSON(t, result.Body)
+
+			assertStatusCode(t, result.StatusCode, http.StatusBadRequest)
+			assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+			assertBodyErrorTitle(t, body, errorUnreadableBody)
+			assertStillServing(t)
+		})
+	}
+}
+
+func TestStoreErrorsAreMappedToStatusCodes(t *testing.T) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+		},
+	}
+
+	t.Run("not found is a 404 with the store's title", func(t *testing.T) {
+		server := NewServer(internal.NewInMemoryCategoryStore(&categoryList), nil, nil)
+
+		req := newGetRequest(t, "/categories/abcd")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusNotFound)
+		assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+		assertBodyErrorTitle(t, body, internal.ErrorCategoryNotFound)
+	})
+
+	t.Run("any other error is a 500 that doesn't leak the cause", func(t *testing.T) {
+		server := NewServer(failingCategoryStore{internal.NewInMemoryCategoryStore(&categoryList)}, nil, nil)
+
+		req := newGetRequest(t, "/categories/1234")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusInternalServerError)
+		assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+		assertBodyErrorTitle(t, body, errorInternal)
+	})
+}
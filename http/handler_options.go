@@ -0,0 +1,120 @@
+package httptransport
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func (c *Server) optionListHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	categoryID := ps.ByName("category")
+	questionID := ps.ByName("question")
+
+	options, err := c.service.ListOptions(ctx, categoryID, questionID)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	writeResponse(res, http.StatusOK, jsonOptionList{options})
+}
+
+func (c *Server) optionGetHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	categoryID := ps.ByName("category")
+	questionID := ps.ByName("question")
+	optionID := ps.ByName("option")
+
+	option, err := c.service.GetOption(ctx, categoryID, questionID, optionID)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	writeResponse(res, http.StatusOK, option)
+}
+
+func (c *Server) optionPostHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	categoryID := ps.ByName("category")
+	questionID := ps.ByName("question")
+
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
+		return
+	}
+
+	var got jsonOptionTitle
+	if !unmarshallRequest(res, requestBody, &got) {
+		return
+	}
+
+	if !ensureJSONFieldsPresent(res, got, jsonOptionTitle{}) {
+		return
+	}
+
+	option, err := c.service.AddOption(ctx, categoryID, questionID, *got.Title)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	res.Header().Set("Location", fmt.Sprintf("/categories/%s/questions/%s/options/%s", categoryID, questionID, option.ID))
+	writeResponse(res, http.StatusCreated, option)
+}
+
+func (c *Server) optionPatchHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	categoryID := ps.ByName("category")
+	questionID := ps.ByName("question")
+	optionID := ps.ByName("option")
+
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
+		return
+	}
+
+	var got jsonOptionTitle
+	if !unmarshallRequest(res, requestBody, &got) {
+		return
+	}
+
+	if !ensureJSONFieldsPresent(res, got, jsonOptionTitle{}) {
+		return
+	}
+
+	option, err := c.service.RenameOption(ctx, categoryID, questionID, optionID, *got.Title)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	writeResponse(res, http.StatusOK, option)
+}
+
+func (c *Server) optionDeleteHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	categoryID := ps.ByName("category")
+	questionID := ps.ByName("question")
+	optionID := ps.ByName("option")
+
+	// answers choosing the option are either removed (?cascade=true) or block the removal
+	cascade, ok := ensureCascadeValid(res, req)
+	if !ok {
+		return
+	}
+
+	if err := c.service.RemoveOption(ctx, categoryID, questionID, optionID, cascade); err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	writeResponse(res, http.StatusOK, jsonStatus{statusDeleted})
+}
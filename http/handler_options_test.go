@@ -0,0 +1,446 @@
+package httptransport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+func newOptionsTestServer() (*Server, *internal.InMemoryQuestionStore, *internal.InMemoryTransactionStore) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation"},
+			internal.Category{ID: "2345", Name: "food"},
+		},
+	}
+	questionList := internal.QuestionList{
+		Questions: []internal.Question{
+			internal.Question{ID: "1", Title: "what kind?", CategoryID: "1234", Type: "string", Options: internal.OptionList{
+				{ID: "a", Title: "hotel"},
+				{ID: "b", Title: "hostel"},
+			}},
+			internal.Question{ID: "2", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+			internal.Question{ID: "3", Title: "which meal?", CategoryID: "2345", Type: "string", Options: internal.OptionList{}},
+		},
+	}
+	transactionList := internal.TransactionList{
+		Transactions: []internal.Transaction{
+			internal.Transaction{ID: "abcdef", Amount: -1200, Currency: "GBP", CategoryID: "1234", Answers: []internal.Answer{
+				{QuestionID: "1", Value: "a"},
+				{QuestionID: "2", Value: float64(2)},
+			}},
+		},
+	}
+	categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+	questionStore := internal.NewInMemoryQuestionStore(&questionList)
+	transactionStore := internal.NewInMemoryTransactionStore(&transactionList)
+	return NewServer(categoryStore, questionStore, transactionStore), questionStore, transactionStore
+}
+
+func getQuestionOptions(t *testing.T, store *internal.InMemoryQuestionStore, questionID string) internal.OptionList {
+	t.Helper()
+	question, err := store.GetQuestion(ctx, questionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return question.Options
+}
+
+func TestListOptions(t *testing.T) {
+	server, _, _ := newOptionsTestServer()
+
+	t.Run("it returns the question's options in order", func(t *testing.T) {
+		req := newGetRequest(t, "/categories/1234/questions/1/options")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var got jsonOptionList
+		unmarshallInterfaceFromBody(t, body, &got)
+		want := internal.OptionList{{ID: "a", Title: "hotel"}, {ID: "b", Title: "hostel"}}
+		assertDeepEqual(t, got.Options, want)
+	})
+
+	t.Run("it returns an empty list for a question without options", func(t *testing.T) {
+		req := newGetRequest(t, "/categories/2345/questions/3/options")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+
+		var got jsonOptionList
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertDeepEqual(t, got.Options, internal.OptionList{})
+	})
+
+	t.Run("test failure responses", func(t *testing.T) {
+		cases := map[string]struct {
+			path       string
+			want       int
+			errorTitle string
+		}{
+			"number question": {
+				path:       "/categories/1234/questions/2/options",
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorQuestionHasNoOptions,
+			},
+			"question doesn't belong to category": {
+				path:       "/categories/2345/questions/1/options",
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorQuestionDoesntBelongToCategory,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				req := newGetRequest(t, c.path)
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+			})
+		}
+	})
+}
+
+func TestGetOption(t *testing.T) {
+	server, _, _ := newOptionsTestServer()
+
+	t.Run("it returns the option", func(t *testing.T) {
+		req := newGetRequest(t, "/categories/1234/questions/1/options/b")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+
+		var got internal.Option
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertDeepEqual(t, got, internal.Option{ID: "b", Title: "hostel"})
+	})
+
+	t.Run("option doesn't exist", func(t *testing.T) {
+		req := newGetRequest(t, "/categories/1234/questions/1/options/c")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusNotFound)
+		assertBodyErrorTitle(t, body, internal.ErrorOptionNotFound)
+	})
+}
+
+func TestAddOption(t *testing.T) {
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		cases := map[string]struct {
+			path       string
+			input      string
+			want       int
+			errorTitle string
+		}{
+			"invalid json": {
+				path:       "/categories/1234/questions/1/options",
+				input:      `{"foo":}`,
+				want:       http.StatusBadRequest,
+				errorTitle: errorInvalidJSON,
+			},
+			"missing title": {
+				path:       "/categories/1234/questions/1/options",
+				input:      `{}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorFieldMissing,
+			},
+			"empty title": {
+				path:       "/categories/1234/questions/1/options",
+				input:      `{"title":""}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorOptionEmpty,
+			},
+			"duplicate title": {
+				path:       "/categories/1234/questions/1/options",
+				input:      `{"title":"hostel"}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorDuplicateOption,
+			},
+			"number question": {
+				path:       "/categories/1234/questions/2/options",
+				input:      `{"title":"two"}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorQuestionHasNoOptions,
+			},
+			"category doesn't exist": {
+				path:       "/categories/5678/questions/1/options",
+				input:      `{"title":"motel"}`,
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorCategoryNotFound,
+			},
+			"question doesn't exist": {
+				path:       "/categories/1234/questions/4/options",
+				input:      `{"title":"motel"}`,
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorQuestionNotFound,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				server, questionStore, _ := newOptionsTestServer()
+
+				req := newPostRequest(t, c.path, strings.NewReader(c.input))
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the store is unmodified
+				assertNumbersEqual(t, len(getQuestionOptions(t, questionStore, "1")), 2)
+			})
+		}
+	})
+
+	t.Run("test success response & effect", func(t *testing.T) {
+		server, questionStore, _ := newOptionsTestServer()
+
+		req := newPostRequest(t, "/categories/1234/questions/1/options", strings.NewReader(`{"title":"motel"}`))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check the response
+		assertStatusCode(t, result.StatusCode, http.StatusCreated)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var got internal.Option
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertIsXid(t, got.ID)
+		assertStringsEqual(t, got.Title, "motel")
+		assertStringsEqual(t, result.Header.Get("Location"), "/categories/1234/questions/1/options/"+got.ID)
+
+		// check the store is updated, with the option added last
+		options := getQuestionOptions(t, questionStore, "1")
+		assertNumbersEqual(t, len(options), 3)
+		assertDeepEqual(t, options[2], got)
+	})
+}
+
+func TestRenameOption(t *testing.T) {
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		cases := map[string]struct {
+			path       string
+			input      string
+			want       int
+			errorTitle string
+		}{
+			"empty title": {
+				path:       "/categories/1234/questions/1/options/a",
+				input:      `{"title":""}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorOptionEmpty,
+			},
+			"duplicate title": {
+				path:       "/categories/1234/questions/1/options/a",
+				input:      `{"title":"hostel"}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorDuplicateOption,
+			},
+			"option doesn't exist": {
+				path:       "/categories/1234/questions/1/options/c",
+				input:      `{"title":"motel"}`,
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorOptionNotFound,
+			},
+			"number question": {
+				path:       "/categories/1234/questions/2/options/a",
+				input:      `{"title":"motel"}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorQuestionHasNoOptions,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				server, questionStore, _ := newOptionsTestServer()
+
+				req := newPatchRequest(t, c.path, strings.NewReader(c.input))
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the store is unmodified
+				assertStringsEqual(t, getQuestionOptions(t, questionStore, "1")[0].Title, "hotel")
+			})
+		}
+	})
+
+	t.Run("renaming an option to its own title is allowed", func(t *testing.T) {
+		server, _, _ := newOptionsTestServer()
+
+		req := newPatchRequest(t, "/categories/1234/questions/1/options/a", strings.NewReader(`{"title":"hotel"}`))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		assertStatusCode(t, res.Result().StatusCode, http.StatusOK)
+	})
+
+	t.Run("test success response & effect", func(t *testing.T) {
+		server, questionStore, transactionStore := newOptionsTestServer()
+
+		req := newPatchRequest(t, "/categories/1234/questions/1/options/a", strings.NewReader(`{"title":"motel"}`))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check the response
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+
+		var got internal.Option
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertDeepEqual(t, got, internal.Option{ID: "a", Title: "motel"})
+
+		// check the store is updated, and answers still choose the option
+		assertDeepEqual(t, getQuestionOptions(t, questionStore, "1")[0], got)
+		assertDeepEqual(t, listTransactions(t, ctx, transactionStore).Transactions[0].Answers[0].Value, "a")
+	})
+}
+
+func TestRemoveOption(t *testing.T) {
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		cases := map[string]struct {
+			path       string
+			want       int
+			errorTitle string
+		}{
+			"option in use": {
+				path:       "/categories/1234/questions/1/options/a",
+				want:       http.StatusConflict,
+				errorTitle: internal.ErrorOptionInUse,
+			},
+			"cascade isn't a boolean": {
+				path:       "/categories/1234/questions/1/options/a?cascade=foo",
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorInvalidCascade,
+			},
+			"option doesn't exist": {
+				path:       "/categories/1234/questions/1/options/c",
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorOptionNotFound,
+			},
+			"question doesn't belong to category": {
+				path:       "/categories/2345/questions/1/options/a",
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorQuestionDoesntBelongToCategory,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				server, questionStore, transactionStore := newOptionsTestServer()
+
+				req := newDeleteRequest(t, c.path)
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the stores are unmodified
+				assertNumbersEqual(t, len(getQuestionOptions(t, questionStore, "1")), 2)
+				assertNumbersEqual(t, len(listTransactions(t, ctx, transactionStore).Transactions[0].Answers), 2)
+			})
+		}
+	})
+
+	t.Run("in-use response reports the referencing transactions", func(t *testing.T) {
+		server, _, _ := newOptionsTestServer()
+
+		req := newDeleteRequest(t, "/categories/1234/questions/1/options/a")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusConflict)
+		assertBodyInUseReferences(t, body, 1)
+	})
+
+	t.Run("an option no answer chooses is removed", func(t *testing.T) {
+		server, questionStore, _ := newOptionsTestServer()
+
+		req := newDeleteRequest(t, "/categories/1234/questions/1/options/b")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertBodyJSONIsStatus(t, body, statusDeleted)
+		assertDeepEqual(t, getQuestionOptions(t, questionStore, "1"), internal.OptionList{{ID: "a", Title: "hotel"}})
+	})
+
+	t.Run("cascade removes the answers choosing the option", func(t *testing.T) {
+		server, questionStore, transactionStore := newOptionsTestServer()
+
+		req := newDeleteRequest(t, "/categories/1234/questions/1/options/a?cascade=true")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check response
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertBodyJSONIsStatus(t, body, statusDeleted)
+
+		// check stores are updated, leaving answers to other questions
+		assertDeepEqual(t, getQuestionOptions(t, questionStore, "1"), internal.OptionList{{ID: "b", Title: "hostel"}})
+		answers := listTransactions(t, ctx, transactionStore).Transactions[0].Answers
+		assertDeepEqual(t, answers, []internal.Answer{{QuestionID: "2", Value: float64(2)}})
+	})
+}
@@ -3,228 +3,203 @@ package httptransport
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"net/http"
-	"reflect"
+	"strings"
 
 	internal "github.com/jgillard/practising-go-tdd/internal"
 	"github.com/julienschmidt/httprouter"
 )
 
 func (c *Server) questionListHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
-	categoryID := ps.ByName("category")
+	ctx := req.Context()
 
-	questionList := c.questionStore.ListQuestionsForCategory(categoryID)
+	categoryID := ps.ByName("category")
 
-	payload := marshallResponse(questionList)
+	questionList, err := c.service.ListQuestions(ctx, categoryID)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
 
-	res.Write(payload)
+	writeResponse(res, http.StatusOK, questionList)
 }
 
-func (c *Server) questionGetHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
-	questionID := ps.ByName("question")
+// questionEffectiveListHandler lists the questions asked in the category,
+// including those inherited from its ancestors, see internal.Service.ListEffectiveQuestions
+func (c *Server) questionEffectiveListHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
 
-	question := c.questionStore.GetQuestion(questionID)
+	categoryID := ps.ByName("category")
 
-	if reflect.DeepEqual(question, internal.Question{}) {
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorQuestionNotFound))
+	questionList, err := c.service.ListEffectiveQuestions(ctx, categoryID)
+	if err != nil {
+		writeServiceError(res, err)
 		return
 	}
 
-	payload := marshallResponse(question)
-
-	res.WriteHeader(http.StatusOK)
-	res.Write(payload)
+	writeResponse(res, http.StatusOK, questionList)
 }
 
-func (c *Server) questionPostHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
-	categoryID := ps.ByName("category")
+// questionNextHandler lists the questions still to be answered in the category given some answers,
+// follow-ups only being listed once the answers they depend on are given, see internal.Service.NextQuestions
+// It changes nothing, but is a POST so the answers can be sent as they would be for a transaction
+func (c *Server) questionNextHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
 
-	requestBody, err := ioutil.ReadAll(req.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
+	categoryID := ps.ByName("category")
 
-	if !jsonIsValid(requestBody) {
-		res.WriteHeader(http.StatusBadRequest)
-		res.Write(craftErrorPayload(errorInvalidJSON))
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
 		return
 	}
 
-	var got internal.QuestionPostRequest
-	err = json.Unmarshal(requestBody, &got)
-	if err != nil {
-		// json.unmarshall explodes if options is not the correct shape, so catch that here
-		switch t := err.(type) {
-		case *json.UnmarshalTypeError:
-			if t.Field == "options" {
-				fmt.Println(`"options" object is wrong shape`)
-				res.WriteHeader(http.StatusBadRequest)
-				res.Write(craftErrorPayload(internal.ErrorOptionsInvalid))
-			}
-		default:
-			log.Fatal(err)
-		}
+	var got jsonAnswers
+	if !unmarshallRequest(res, requestBody, &got) {
 		return
 	}
 
-	if !ensureJSONFieldsPresent(res, got, internal.QuestionPostRequest{}) {
-		res.Write(craftErrorPayload(internal.ErrorFieldMissing))
+	if !ensureJSONFieldsPresent(res, got, jsonAnswers{}) {
 		return
 	}
 
-	if !ensureStringFieldNonEmpty(res, "title", got.Title) {
-		res.Write(craftErrorPayload(internal.ErrorTitleEmpty))
+	questionList, err := c.service.NextQuestions(ctx, categoryID, *got.Answers)
+	if err != nil {
+		writeServiceError(res, err)
 		return
 	}
 
-	if !ensureStringFieldNonEmpty(res, "type", got.Type) {
-		res.Write(craftErrorPayload(internal.ErrorTypeEmpty))
+	writeResponse(res, http.StatusOK, questionList)
+}
+
+func (c *Server) questionGetHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	questionID := ps.ByName("question")
+
+	question, err := c.service.GetQuestion(ctx, questionID)
+	if err != nil {
+		writeServiceError(res, err)
 		return
 	}
 
-	if !internal.IsValidOptionType(got.Type) {
-		res.WriteHeader(http.StatusBadRequest)
-		res.Write(craftErrorPayload(internal.ErrorInvalidType))
+	writeResponse(res, http.StatusOK, question)
+}
+
+func (c *Server) questionPostHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	categoryID := ps.ByName("category")
+
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
 		return
 	}
 
-	// perform checks on nested options object
-	if got.Options != nil {
-		for _, opt := range *got.Options {
-			if !ensureStringFieldNonEmpty(res, "options", opt) {
-				res.Write(craftErrorPayload(internal.ErrorOptionEmpty))
-				return
-			}
-		}
+	var got internal.QuestionPostRequest
+	if err := json.Unmarshal(requestBody, &got); err != nil {
+		fmt.Println(err)
 
-		if !ensureNoDuplicates(res, "options", *got.Options) {
-			res.Write(craftErrorPayload(internal.ErrorDuplicateOption))
-			return
+		// json.unmarshall explodes if options is not the correct shape, so catch that here
+		title := errorInvalidJSONFields
+		if t, ok := err.(*json.UnmarshalTypeError); ok && strings.Split(t.Field, ".")[0] == "options" {
+			title = internal.ErrorOptionsInvalid
 		}
-	}
 
-	if c.questionStore.QuestionTitleExists(categoryID, got.Title) {
-		fmt.Println(`"title" already exists`)
-		res.WriteHeader(http.StatusConflict)
-		res.Write(craftErrorPayload(internal.ErrorDuplicateTitle))
+		writeErrors(res, http.StatusBadRequest, wrongTypeJSONError(title, err))
 		return
 	}
 
-	if c.categoryStore != nil && !c.categoryStore.CategoryIDExists(categoryID) {
-		fmt.Println(`"categoryID" in path doesn't exist`)
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorCategoryNotFound))
+	if !ensureJSONFieldsPresent(res, got, internal.QuestionPostRequest{}) {
 		return
 	}
 
-	question := c.questionStore.AddQuestion(categoryID, got)
-
-	payload := marshallResponse(question)
+	question, err := c.service.AddQuestion(ctx, categoryID, got)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
 
 	res.Header().Set("Location", fmt.Sprintf("/categories/%s/questions/%s", categoryID, question.ID))
-	res.WriteHeader(http.StatusCreated)
-	res.Write(payload)
+	writeResponse(res, http.StatusCreated, question)
 }
 
 func (c *Server) questionPatchHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
 	categoryID := ps.ByName("category")
 	questionID := ps.ByName("question")
 
-	requestBody, err := ioutil.ReadAll(req.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	if !jsonIsValid(requestBody) {
-		res.WriteHeader(http.StatusBadRequest)
-		res.Write(craftErrorPayload(errorInvalidJSON))
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
 		return
 	}
 
-	var got jsonTitle
-	unmarshallRequest(requestBody, &got)
-
-	questionTitle := got.Title
-
-	if !ensureJSONFieldsPresent(res, got, jsonTitle{}) {
-		res.Write(craftErrorPayload(internal.ErrorFieldMissing))
+	var got internal.QuestionPatchRequest
+	if !unmarshallRequest(res, requestBody, &got) {
 		return
 	}
 
-	if !internal.IsValidQuestionTitle(questionTitle) {
-		fmt.Println(`"title" is not a valid string`)
-		res.WriteHeader(http.StatusUnprocessableEntity)
-		res.Write(craftErrorPayload(internal.ErrorInvalidTitle))
+	if !ensureJSONFieldsPresent(res, got, internal.QuestionPatchRequest{}) {
 		return
 	}
 
-	if c.categoryStore != nil && !c.categoryStore.CategoryIDExists(categoryID) {
-		fmt.Println(`"categoryID" in path doesn't exist`)
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorCategoryNotFound))
+	question, err := c.service.UpdateQuestion(ctx, categoryID, questionID, got)
+	if err != nil {
+		writeServiceError(res, err)
 		return
 	}
 
-	if !c.questionStore.QuestionIDExists(questionID) {
-		fmt.Println(`"questionID" in path doesn't exist`)
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorQuestionNotFound))
-		return
-	}
+	writeResponse(res, http.StatusOK, question)
+}
+
+func (c *Server) questionDeleteHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
 
-	if !c.questionStore.QuestionBelongsToCategory(questionID, categoryID) {
-		fmt.Println(`"questionID" in path doesn't belong to "categoryID" in path`)
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorQuestionDoesntBelongToCategory))
+	categoryID := ps.ByName("category")
+	questionID := ps.ByName("question")
+
+	// answers to the question are either removed (?cascade=true) or block the removal
+	cascade, ok := ensureCascadeValid(res, req)
+	if !ok {
 		return
 	}
 
-	if c.questionStore.QuestionTitleExists(categoryID, got.Title) {
-		fmt.Println(`"title" already exists`)
-		res.WriteHeader(http.StatusConflict)
-		res.Write(craftErrorPayload(internal.ErrorDuplicateTitle))
+	if err := c.service.RemoveQuestion(ctx, categoryID, questionID, cascade); err != nil {
+		writeServiceError(res, err)
 		return
 	}
 
-	question := c.questionStore.RenameQuestion(questionID, questionTitle)
-	payload := marshallResponse(question)
-
-	res.WriteHeader(http.StatusOK)
-	res.Write(payload)
+	writeResponse(res, http.StatusOK, jsonStatus{statusDeleted})
 }
 
-func (c *Server) questionDeleteHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+// questionHideHandler stops the category inheriting a question, which is returned
+func (c *Server) questionHideHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
 	categoryID := ps.ByName("category")
 	questionID := ps.ByName("question")
 
-	if c.categoryStore != nil && !c.categoryStore.CategoryIDExists(categoryID) {
-		fmt.Println(`"categoryID" in path doesn't exist`)
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorCategoryNotFound))
+	question, err := c.service.HideQuestion(ctx, categoryID, questionID)
+	if err != nil {
+		writeServiceError(res, err)
 		return
 	}
 
-	if !c.questionStore.QuestionIDExists(questionID) {
-		fmt.Println(`"questionID" in path doesn't exist`)
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorQuestionNotFound))
-		return
-	}
+	writeResponse(res, http.StatusOK, question)
+}
 
-	if !c.questionStore.QuestionBelongsToCategory(questionID, categoryID) {
-		fmt.Println(`"questionID" in path doesn't belong to "categoryID" in path`)
-		res.WriteHeader(http.StatusNotFound)
-		res.Write(craftErrorPayload(internal.ErrorQuestionDoesntBelongToCategory))
-		return
-	}
+// questionUnhideHandler lets the category inherit a question it hid again, which is returned
+func (c *Server) questionUnhideHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
 
-	c.questionStore.DeleteQuestion(questionID)
+	categoryID := ps.ByName("category")
+	questionID := ps.ByName("question")
 
-	payload := marshallResponse(jsonStatus{statusDeleted})
+	question, err := c.service.UnhideQuestion(ctx, categoryID, questionID)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
 
-	res.WriteHeader(http.StatusOK)
-	res.Write(payload)
+	writeResponse(res, http.StatusOK, question)
 }
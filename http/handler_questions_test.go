@@ -23,7 +23,7 @@ func TestListQuestionsForCategory(t *testing.T) {
 		},
 	}
 	questionStore := internal.NewInMemoryQuestionStore(&questionList)
-	server := NewServer(nil, questionStore)
+	server := NewServer(nil, questionStore, nil)
 
 	t.Run("it returns a json question list for a category", func(t *testing.T) {
 		req := newGetRequest(t, "/categories/1234/questions")
@@ -87,7 +87,7 @@ func TestGetQuestion(t *testing.T) {
 		},
 	}
 	questionStore := internal.NewInMemoryQuestionStore(&questionList)
-	server := NewServer(nil, questionStore)
+	server := NewServer(nil, questionStore, nil)
 
 	t.Run("test failure responses & effect", func(t *testing.T) {
 		cases := map[string]struct {
@@ -112,7 +112,7 @@ func TestGetQuestion(t *testing.T) {
 				body := readBodyJSON(t, result.Body)
 
 				assertStatusCode(t, result.StatusCode, c.want)
-				assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
 				assertBodyErrorTitle(t, body, c.errorTitle)
 			})
 		}
@@ -158,7 +158,7 @@ func TestAddQuestion(t *testing.T) {
 		}
 		categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
 		questionStore := internal.NewInMemoryQuestionStore(&questionList)
-		server := NewServer(categoryStore, questionStore)
+		server := NewServer(categoryStore, questionStore, nil)
 
 		cases := map[string]struct {
 			path       string
@@ -172,12 +172,30 @@ func TestAddQuestion(t *testing.T) {
 				want:       http.StatusBadRequest,
 				errorTitle: errorInvalidJSON,
 			},
+			"constraint not allowed for the type": {
+				path:       "/categories/1234/questions",
+				input:      `{"title":"any notes?", "type":"text", "constraints":{"min":1}}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorConstraintNotAllowed,
+			},
+			"default outside the constraints": {
+				path:       "/categories/1234/questions",
+				input:      `{"title":"how many guests?", "type":"number", "constraints":{"max":4, "default":5}}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorInvalidDefault,
+			},
 			"title is empty": {
 				path:       "/categories/1234/questions",
 				input:      `{"title":"", "type":"number"}`,
 				want:       http.StatusBadRequest,
 				errorTitle: internal.ErrorTitleEmpty,
 			},
+			"title is invalid": {
+				path:       "/categories/1234/questions",
+				input:      `{"title":"!!!", "type":"number"}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorInvalidTitle,
+			},
 			"title is duplicate": {
 				path:       "/categories/1234/questions",
 				input:      `{"title":"how many nights?", "type":"number"}`,
@@ -208,6 +226,12 @@ func TestAddQuestion(t *testing.T) {
 				want:       http.StatusBadRequest,
 				errorTitle: internal.ErrorDuplicateOption,
 			},
+			"options for a type without them": {
+				path:       "/categories/1234/questions",
+				input:      `{"title":"foo", "type":"boolean", "options":["yes", "no"]}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorOptionsNotAllowed,
+			},
 			"options contains empty string": {
 				path:       "/categories/1234/questions",
 				input:      `{"title":"foo", "type":"string", "options":[""]}`,
@@ -234,21 +258,42 @@ func TestAddQuestion(t *testing.T) {
 
 				// check the response
 				assertStatusCode(t, result.StatusCode, c.want)
-				assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
 
 				assertBodyErrorTitle(t, body, c.errorTitle)
 
 				// check the store is unmodified
-				got := questionStore.ListQuestions()
+				got := listQuestions(t, ctx, questionStore)
 				want := questionList
 				assertDeepEqual(t, got, want)
 			})
 		}
+
+		t.Run("every field problem is reported at once", func(t *testing.T) {
+			requestBody := strings.NewReader(`{"title":"", "type":"foo", "options":["a", "", "a"]}`)
+			req := newPostRequest(t, "/categories/1234/questions", requestBody)
+			res := httptest.NewRecorder()
+
+			server.ServeHTTP(res, req)
+			result := res.Result()
+			body := readBodyJSON(t, result.Body)
+
+			assertStatusCode(t, result.StatusCode, http.StatusBadRequest)
+			assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+			assertBodyErrors(t, body, []jsonError{
+				{Title: internal.ErrorTitleEmpty, Pointer: "/title"},
+				{Title: internal.ErrorInvalidType, Pointer: "/type"},
+				{Title: internal.ErrorOptionEmpty, Pointer: "/options/1"},
+				{Title: internal.ErrorDuplicateOption, Pointer: "/options/2"},
+			})
+
+			assertDeepEqual(t, listQuestions(t, ctx, questionStore), questionList)
+		})
 	})
 
 	t.Run("add type:number question", func(t *testing.T) {
 		questionStore := internal.NewInMemoryQuestionStore(nil)
-		server := NewServer(nil, questionStore)
+		server := NewServer(nil, questionStore, nil)
 
 		categoryID := "1"
 		title := "how many nights?"
@@ -283,7 +328,7 @@ func TestAddQuestion(t *testing.T) {
 		assertOptionsNil(t, got.Options)
 
 		// check the store has been modified
-		got = questionStore.ListQuestions().Questions[0]
+		got = listQuestions(t, ctx, questionStore).Questions[0]
 		assertIsXid(t, got.ID)
 		assertStringsEqual(t, got.Title, title)
 		assertStringsEqual(t, got.CategoryID, categoryID)
@@ -295,7 +340,7 @@ func TestAddQuestion(t *testing.T) {
 
 	t.Run("add type:string question without options", func(t *testing.T) {
 		questionStore := internal.NewInMemoryQuestionStore(nil)
-		server := NewServer(nil, questionStore)
+		server := NewServer(nil, questionStore, nil)
 
 		categoryID := "1"
 		title := "what number?"
@@ -329,7 +374,7 @@ func TestAddQuestion(t *testing.T) {
 		assertOptionsNil(t, got.Options)
 
 		// check the store has been modified
-		got = questionStore.ListQuestions().Questions[0]
+		got = listQuestions(t, ctx, questionStore).Questions[0]
 		assertIsXid(t, got.ID)
 		assertStringsEqual(t, got.Title, title)
 		assertStringsEqual(t, got.CategoryID, categoryID)
@@ -342,7 +387,7 @@ func TestAddQuestion(t *testing.T) {
 
 	t.Run("add type:string question with empty options", func(t *testing.T) {
 		questionStore := internal.NewInMemoryQuestionStore(nil)
-		server := NewServer(nil, questionStore)
+		server := NewServer(nil, questionStore, nil)
 
 		categoryID := "1"
 		title := "which meal?"
@@ -378,7 +423,7 @@ func TestAddQuestion(t *testing.T) {
 		assertDeepEqual(t, got.Options, internal.OptionList{})
 
 		// check the store has been modified
-		got = questionStore.ListQuestions().Questions[0]
+		got = listQuestions(t, ctx, questionStore).Questions[0]
 		assertIsXid(t, got.ID)
 		assertStringsEqual(t, got.Title, title)
 		assertStringsEqual(t, got.CategoryID, categoryID)
@@ -391,7 +436,7 @@ func TestAddQuestion(t *testing.T) {
 
 	t.Run("add type:string question with options", func(t *testing.T) {
 		questionStore := internal.NewInMemoryQuestionStore(nil)
-		server := NewServer(nil, questionStore)
+		server := NewServer(nil, questionStore, nil)
 
 		categoryID := "1"
 		title := "which meal?"
@@ -430,7 +475,7 @@ func TestAddQuestion(t *testing.T) {
 		assertStringsEqual(t, got.Options[1].Title, options[1])
 
 		// check the store has been modified
-		got = questionStore.ListQuestions().Questions[0]
+		got = listQuestions(t, ctx, questionStore).Questions[0]
 		assertIsXid(t, got.ID)
 		assertStringsEqual(t, got.Title, title)
 		assertStringsEqual(t, got.CategoryID, categoryID)
@@ -463,7 +508,7 @@ func TestRenameQuestion(t *testing.T) {
 	}
 	categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
 	questionStore := internal.NewInMemoryQuestionStore(&questionList)
-	server := NewServer(categoryStore, questionStore)
+	server := NewServer(categoryStore, questionStore, nil)
 
 	t.Run("test failure responses & effect", func(t *testing.T) {
 		cases := map[string]struct {
@@ -490,6 +535,12 @@ func TestRenameQuestion(t *testing.T) {
 				want:       http.StatusUnprocessableEntity,
 				errorTitle: internal.ErrorInvalidTitle,
 			},
+			"invalid constraints": {
+				path:       "/categories/1234/questions/1",
+				input:      `{"constraints":{"min":5, "max":1}}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorMinAboveMax,
+			},
 			"duplicate title": {
 				path:       "/categories/1234/questions/1",
 				input:      `{"title":"how much nougat?"}`,
@@ -528,11 +579,11 @@ func TestRenameQuestion(t *testing.T) {
 
 				// check the response
 				assertStatusCode(t, result.StatusCode, c.want)
-				assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
 				assertBodyErrorTitle(t, body, c.errorTitle)
 
 				// check the store is unmodified
-				got := questionStore.ListQuestions()
+				got := listQuestions(t, ctx, questionStore)
 				want := questionList
 				assertDeepEqual(t, got, want)
 			})
@@ -566,10 +617,205 @@ func TestRenameQuestion(t *testing.T) {
 		assertStringsEqual(t, responseBody.Type, renamedQuestion.Type)
 
 		// check the store is updated
-		got := questionStore.ListQuestions().Questions[0].Title
+		got := listQuestions(t, ctx, questionStore).Questions[0].Title
 		want := renamedQuestion.Title
 		assertStringsEqual(t, got, want)
 	})
+
+	t.Run("test constraints success responses & effect", func(t *testing.T) {
+		requestBody := strings.NewReader(`{"constraints":{"required":true, "min":1, "unit":"nuggets", "default":6}}`)
+		req := newPatchRequest(t, "/categories/1234/questions/2", requestBody)
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check the response
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var responseBody internal.Question
+		unmarshallInterfaceFromBody(t, body, &responseBody)
+
+		min := float64(1)
+		want := &internal.Constraints{Required: true, Min: &min, Unit: "nuggets", Default: float64(6)}
+		assertStringsEqual(t, responseBody.Title, "how much nougat?")
+		assertDeepEqual(t, responseBody.Constraints, want)
+
+		// check the store is updated
+		got := listQuestions(t, ctx, questionStore).Questions[1].Constraints
+		assertDeepEqual(t, got, want)
+	})
+}
+
+func TestQuestionInheritance(t *testing.T) {
+
+	newServer := func() (*Server, *internal.InMemoryQuestionStore) {
+		categoryList := internal.CategoryList{
+			Categories: []internal.Category{
+				internal.Category{ID: "1234", Name: "food and drink", ParentID: ""},
+				internal.Category{ID: "5678", Name: "restaurants", ParentID: "1234"},
+			},
+		}
+		questionList := internal.QuestionList{
+			Questions: []internal.Question{
+				internal.Question{ID: "1", Title: "which meal?", CategoryID: "1234", Type: "string", Options: internal.OptionList{
+					{ID: "a", Title: "breakfast"},
+					{ID: "b", Title: "lunch"},
+					{ID: "c", Title: "dinner"},
+				}},
+				internal.Question{ID: "2", Title: "how many people?", CategoryID: "1234", Type: "number"},
+				internal.Question{ID: "3", Title: "any tip?", CategoryID: "5678", Type: "boolean"},
+			},
+		}
+		categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+		questionStore := internal.NewInMemoryQuestionStore(&questionList)
+		return NewServer(categoryStore, questionStore, nil), questionStore
+	}
+
+	listEffective := func(t *testing.T, server *Server, categoryID string) []string {
+		t.Helper()
+		req := newGetRequest(t, fmt.Sprintf("/categories/%s/effective-questions", categoryID))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var got internal.QuestionList
+		unmarshallInterfaceFromBody(t, body, &got)
+
+		var ids []string
+		for _, q := range got.Questions {
+			ids = append(ids, q.ID)
+		}
+		return ids
+	}
+
+	t.Run("subcategories inherit their ancestors' questions", func(t *testing.T) {
+		server, _ := newServer()
+
+		assertDeepEqual(t, listEffective(t, server, "5678"), []string{"1", "2", "3"})
+		assertDeepEqual(t, listEffective(t, server, "1234"), []string{"1", "2"})
+	})
+
+	t.Run("an override replaces the inherited question", func(t *testing.T) {
+		server, _ := newServer()
+
+		requestBody := strings.NewReader(`{"title":"which meal?", "type":"string", "options":["lunch", "dinner"], "overrides":"1"}`)
+		req := newPostRequest(t, "/categories/5678/questions", requestBody)
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusCreated)
+
+		var override internal.Question
+		unmarshallInterfaceFromBody(t, body, &override)
+		assertStringsEqual(t, override.Overrides, "1")
+
+		assertDeepEqual(t, listEffective(t, server, "5678"), []string{override.ID, "2", "3"})
+	})
+
+	t.Run("a hidden question isn't inherited until unhidden", func(t *testing.T) {
+		server, questionStore := newServer()
+
+		req := newPutRequest(t, "/categories/5678/hidden-questions/2", nil)
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+
+		var hidden internal.Question
+		unmarshallInterfaceFromBody(t, body, &hidden)
+		assertDeepEqual(t, hidden.HiddenIn, []string{"5678"})
+
+		assertDeepEqual(t, listEffective(t, server, "5678"), []string{"1", "3"})
+
+		req = newDeleteRequest(t, "/categories/5678/hidden-questions/2")
+		res = httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		assertStatusCode(t, res.Result().StatusCode, http.StatusOK)
+
+		assertDeepEqual(t, listEffective(t, server, "5678"), []string{"1", "2", "3"})
+
+		got, err := questionStore.GetQuestion(ctx, "2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertNumbersEqual(t, len(got.HiddenIn), 0)
+	})
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		cases := map[string]struct {
+			method     string
+			path       string
+			input      string
+			want       int
+			errorTitle string
+		}{
+			"effective questions of a category that doesn't exist": {
+				method:     http.MethodGet,
+				path:       "/categories/9999/effective-questions",
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorCategoryNotFound,
+			},
+			"overriding a question that isn't inherited": {
+				method:     http.MethodPost,
+				path:       "/categories/5678/questions",
+				input:      `{"title":"any tips?", "type":"boolean", "overrides":"3"}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorQuestionNotInherited,
+			},
+			"hiding a question that isn't inherited": {
+				method:     http.MethodPut,
+				path:       "/categories/1234/hidden-questions/1",
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorQuestionNotInherited,
+			},
+			"unhiding a question that isn't hidden": {
+				method:     http.MethodDelete,
+				path:       "/categories/5678/hidden-questions/1",
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorQuestionNotHidden,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				server, questionStore := newServer()
+				before := listQuestions(t, ctx, questionStore)
+
+				req, err := http.NewRequest(c.method, c.path, strings.NewReader(c.input))
+				if err != nil {
+					t.Fatal(err)
+				}
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the store is unmodified
+				assertDeepEqual(t, listQuestions(t, ctx, questionStore), before)
+			})
+		}
+	})
 }
 
 func TestRemoveQuestion(t *testing.T) {
@@ -587,7 +833,7 @@ func TestRemoveQuestion(t *testing.T) {
 	}
 	categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
 	questionStore := internal.NewInMemoryQuestionStore(&questionList)
-	server := NewServer(categoryStore, questionStore)
+	server := NewServer(categoryStore, questionStore, nil)
 
 	t.Run("test failure responses & effect", func(t *testing.T) {
 		cases := map[string]struct {
@@ -623,12 +869,12 @@ func TestRemoveQuestion(t *testing.T) {
 
 				// check the response
 				assertStatusCode(t, result.StatusCode, c.want)
-				assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
 
 				assertBodyErrorTitle(t, body, c.errorTitle)
 
 				// check the store is unmodified
-				got := questionStore.ListQuestions()
+				got := listQuestions(t, ctx, questionStore)
 				want := questionList
 				assertDeepEqual(t, got, want)
 			})
@@ -649,8 +895,301 @@ func TestRemoveQuestion(t *testing.T) {
 		assertBodyJSONIsStatus(t, body, statusDeleted)
 
 		// check store is updated
-		got := len(questionStore.ListQuestions().Questions)
+		got := len(listQuestions(t, ctx, questionStore).Questions)
 		want := 0
 		assertNumbersEqual(t, got, want)
 	})
 }
+
+func TestRemoveQuestionInUse(t *testing.T) {
+
+	newServer := func() (*Server, *internal.InMemoryQuestionStore, *internal.InMemoryTransactionStore) {
+		categoryList := internal.CategoryList{
+			Categories: []internal.Category{
+				internal.Category{ID: "1234", Name: "accommodation"},
+			},
+		}
+		questionList := internal.QuestionList{
+			Questions: []internal.Question{
+				internal.Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+			},
+		}
+		transactionList := internal.TransactionList{
+			Transactions: []internal.Transaction{
+				internal.Transaction{ID: "abcdef", Amount: -1200, Currency: "GBP", CategoryID: "1234", Answers: []internal.Answer{
+					{QuestionID: "1", Value: float64(2)},
+				}},
+			},
+		}
+		categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+		questionStore := internal.NewInMemoryQuestionStore(&questionList)
+		transactionStore := internal.NewInMemoryTransactionStore(&transactionList)
+		return NewServer(categoryStore, questionStore, transactionStore), questionStore, transactionStore
+	}
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		cases := map[string]struct {
+			path       string
+			want       int
+			errorTitle string
+		}{
+			"question in use": {
+				path:       "/categories/1234/questions/1",
+				want:       http.StatusConflict,
+				errorTitle: internal.ErrorQuestionInUse,
+			},
+			"cascade isn't a boolean": {
+				path:       "/categories/1234/questions/1?cascade=foo",
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorInvalidCascade,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				server, questionStore, transactionStore := newServer()
+
+				req := newDeleteRequest(t, c.path)
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the stores are unmodified
+				assertNumbersEqual(t, len(listQuestions(t, ctx, questionStore).Questions), 1)
+				assertNumbersEqual(t, len(listTransactions(t, ctx, transactionStore).Transactions[0].Answers), 1)
+			})
+		}
+	})
+
+	t.Run("in-use response reports the referencing transactions", func(t *testing.T) {
+		server, _, _ := newServer()
+
+		req := newDeleteRequest(t, "/categories/1234/questions/1")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusConflict)
+		assertBodyInUseReferences(t, body, 1)
+	})
+
+	t.Run("cascade removes the answers", func(t *testing.T) {
+		server, questionStore, transactionStore := newServer()
+
+		req := newDeleteRequest(t, "/categories/1234/questions/1?cascade=true")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check response
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertBodyJSONIsStatus(t, body, statusDeleted)
+
+		// check stores are updated
+		assertNumbersEqual(t, len(listQuestions(t, ctx, questionStore).Questions), 0)
+		assertNumbersEqual(t, len(listTransactions(t, ctx, transactionStore).Transactions[0].Answers), 0)
+	})
+}
+
+func TestQuestionDependencies(t *testing.T) {
+
+	newServer := func() (*Server, *internal.InMemoryQuestionStore) {
+		categoryList := internal.CategoryList{
+			Categories: []internal.Category{
+				internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+			},
+		}
+		questionList := internal.QuestionList{
+			Questions: []internal.Question{
+				internal.Question{ID: "1", Title: "what kind?", CategoryID: "1234", Type: "string", Options: internal.OptionList{
+					{ID: "a", Title: "hotel"},
+					{ID: "b", Title: "hostel"},
+				}},
+				internal.Question{ID: "2", Title: "star rating?", CategoryID: "1234", Type: "number",
+					DependsOn: &internal.Dependency{QuestionID: "1", OptionID: "a"}},
+			},
+		}
+		categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+		questionStore := internal.NewInMemoryQuestionStore(&questionList)
+		return NewServer(categoryStore, questionStore, nil), questionStore
+	}
+
+	listNext := func(t *testing.T, server *Server, answers string) []string {
+		t.Helper()
+		requestBody := strings.NewReader(fmt.Sprintf(`{"answers":%s}`, answers))
+		req := newPostRequest(t, "/categories/1234/next-questions", requestBody)
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var got internal.QuestionList
+		unmarshallInterfaceFromBody(t, body, &got)
+
+		ids := []string{}
+		for _, q := range got.Questions {
+			ids = append(ids, q.ID)
+		}
+		return ids
+	}
+
+	t.Run("follow-ups are next once their dependency is met", func(t *testing.T) {
+		server, _ := newServer()
+
+		assertDeepEqual(t, listNext(t, server, `[]`), []string{"1"})
+		assertDeepEqual(t, listNext(t, server, `[{"questionID":"1", "value":"a"}]`), []string{"2"})
+		assertDeepEqual(t, listNext(t, server, `[{"questionID":"1", "value":"b"}]`), []string{})
+	})
+
+	t.Run("a question is made a follow-up", func(t *testing.T) {
+		server, questionStore := newServer()
+
+		requestBody := strings.NewReader(`{"title":"how many nights?", "type":"number", "dependsOn":{"questionID":"1", "optionID":"b"}}`)
+		req := newPostRequest(t, "/categories/1234/questions", requestBody)
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusCreated)
+
+		var got internal.Question
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertDeepEqual(t, got.DependsOn, &internal.Dependency{QuestionID: "1", OptionID: "b"})
+
+		assertDeepEqual(t, listNext(t, server, `[{"questionID":"1", "value":"b"}]`), []string{got.ID})
+
+		stored, err := questionStore.GetQuestion(ctx, got.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertDeepEqual(t, stored, got)
+	})
+
+	t.Run("a question with follow-ups is in use", func(t *testing.T) {
+		server, questionStore := newServer()
+		before := listQuestions(t, ctx, questionStore)
+
+		req := newDeleteRequest(t, "/categories/1234/questions/1")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusConflict)
+		assertBodyErrorTitle(t, body, internal.ErrorQuestionInUse)
+
+		var got jsonInUseErrors
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertNumbersEqual(t, got.References.Questions, 1)
+
+		assertDeepEqual(t, listQuestions(t, ctx, questionStore), before)
+	})
+
+	t.Run("cascading stops its follow-ups depending on it", func(t *testing.T) {
+		server, questionStore := newServer()
+
+		req := newDeleteRequest(t, "/categories/1234/questions/1?cascade=true")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		assertStatusCode(t, res.Result().StatusCode, http.StatusOK)
+
+		got, err := questionStore.GetQuestion(ctx, "2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.DependsOn != nil {
+			t.Errorf("got dependency %v wanted none", got.DependsOn)
+		}
+		assertDeepEqual(t, listNext(t, server, `[]`), []string{"2"})
+	})
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		cases := map[string]struct {
+			method     string
+			path       string
+			input      string
+			want       int
+			errorTitle string
+		}{
+			"next questions without answers": {
+				method:     http.MethodPost,
+				path:       "/categories/1234/next-questions",
+				input:      `{}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorFieldMissing,
+			},
+			"next questions given an answer to a follow-up that isn't asked": {
+				method:     http.MethodPost,
+				path:       "/categories/1234/next-questions",
+				input:      `{"answers":[{"questionID":"2", "value":4}]}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorAnswerNotAsked,
+			},
+			"next questions of a category that doesn't exist": {
+				method:     http.MethodPost,
+				path:       "/categories/9999/next-questions",
+				input:      `{"answers":[]}`,
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorCategoryNotFound,
+			},
+			"depending on a question that doesn't exist": {
+				method:     http.MethodPost,
+				path:       "/categories/1234/questions",
+				input:      `{"title":"how many nights?", "type":"number", "dependsOn":{"questionID":"9", "optionID":"a"}}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorDependencyNotFound,
+			},
+			"depending on each other": {
+				method:     http.MethodPatch,
+				path:       "/categories/1234/questions/1",
+				input:      `{"dependsOn":{"questionID":"2", "min":3}}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorDependencyCycle,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				server, questionStore := newServer()
+				before := listQuestions(t, ctx, questionStore)
+
+				req, err := http.NewRequest(c.method, c.path, strings.NewReader(c.input))
+				if err != nil {
+					t.Fatal(err)
+				}
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the store is unmodified
+				assertDeepEqual(t, listQuestions(t, ctx, questionStore), before)
+			})
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package httptransport
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+func (c *Server) spendingReportHandler(res http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	query := req.URL.Query()
+
+	report, err := c.service.SpendingReport(req.Context(), internal.SpendingReportRequest{
+		From:    query.Get("from"),
+		To:      query.Get("to"),
+		GroupBy: query.Get("groupBy"),
+	})
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	writeResponse(res, http.StatusOK, report)
+}
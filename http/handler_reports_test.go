@@ -0,0 +1,97 @@
+package httptransport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+func TestSpendingReport(t *testing.T) {
+
+	transactionList := internal.TransactionList{
+		Transactions: []internal.Transaction{
+			internal.Transaction{ID: "abcdef", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: transactionTimestamp, CategoryID: "2345", Answers: []internal.Answer{
+				{QuestionID: "2", Value: "a"},
+			}},
+			internal.Transaction{ID: "ghijkm", Amount: -9000, Currency: "GBP", Merchant: "Hotel", Timestamp: transactionTimestamp.AddDate(0, 1, 0), CategoryID: "1234"},
+		},
+	}
+	server, _ := newTransactionTestServer(&transactionList)
+
+	t.Run("test failure responses", func(t *testing.T) {
+		cases := map[string]struct {
+			path       string
+			errorTitle string
+		}{
+			"invalid from":    {"/reports/spending?from=yesterday", internal.ErrorInvalidFrom},
+			"invalid to":      {"/reports/spending?to=2019-13-01", internal.ErrorInvalidTo},
+			"from after to":   {"/reports/spending?from=2019-04-01&to=2019-03-01", internal.ErrorFromAfterTo},
+			"invalid groupBy": {"/reports/spending?groupBy=merchant", internal.ErrorInvalidGroupBy},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				req := newGetRequest(t, c.path)
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				assertStatusCode(t, result.StatusCode, http.StatusBadRequest)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+			})
+		}
+	})
+
+	t.Run("it defaults to grouping by category", func(t *testing.T) {
+		req := newGetRequest(t, "/reports/spending")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var got internal.SpendingReport
+		unmarshallInterfaceFromBody(t, body, &got)
+
+		assertStringsEqual(t, got.GroupBy, internal.GroupByCategory)
+		assertDeepEqual(t, got.Groups, []internal.SpendingGroup{
+			{Key: "1234", Name: "accommodation", Count: 1, Totals: map[string]int64{"GBP": -9000}},
+			{Key: "2345", Name: "food and drink", Count: 1, Totals: map[string]int64{"GBP": -350}},
+		})
+		assertDeepEqual(t, got.OptionCounts, []internal.QuestionOptionCount{
+			{QuestionID: "2", Title: "which meal?", CategoryID: "2345", Options: []internal.OptionCount{
+				{ID: "a", Title: "brekkie", Count: 1},
+			}},
+		})
+	})
+
+	t.Run("it limits to the date range and groups by month", func(t *testing.T) {
+		from := transactionTimestamp.Format("2006-01-02")
+		to := transactionTimestamp.AddDate(0, 0, 1).Format(time.RFC3339)
+
+		req := newGetRequest(t, "/reports/spending?groupBy=month&from="+from+"&to="+to)
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+
+		var got internal.SpendingReport
+		unmarshallInterfaceFromBody(t, body, &got)
+
+		assertDeepEqual(t, got.Groups, []internal.SpendingGroup{
+			{Key: "2019-03", Count: 1, Totals: map[string]int64{"GBP": -350}},
+		})
+	})
+}
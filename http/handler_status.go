@@ -10,6 +10,5 @@ import (
 
 func (c *Server) statusHandler(res http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	status := internal.GetStatus()
-	payload := marshallResponse(jsonStatus{status})
-	res.Write(payload)
+	writeResponse(res, http.StatusOK, jsonStatus{status})
 }
@@ -7,7 +7,7 @@ import (
 )
 
 func TestStatusHandler(t *testing.T) {
-	server := NewServer(nil, nil)
+	server := NewServer(nil, nil, nil)
 	req := newGetRequest(t, "/status")
 	res := httptest.NewRecorder()
 
@@ -0,0 +1,113 @@
+package httptransport
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+// TransactionPatchRequest (re)categorises a Transaction,
+// replacing all of its answers
+// CategoryID is a pointer to allow "" to uncategorise a Transaction
+type TransactionPatchRequest struct {
+	CategoryID *string           `json:"categoryID"`
+	Answers    []internal.Answer `json:"answers"`
+}
+
+func (c *Server) transactionListHandler(res http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	ctx := req.Context()
+
+	transactionList, err := c.transactionStore.ListTransactions(ctx)
+	if err != nil {
+		writeStoreError(res, err)
+		return
+	}
+
+	writeResponse(res, http.StatusOK, transactionList)
+}
+
+func (c *Server) transactionGetHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	transactionID := ps.ByName("transaction")
+
+	transaction, err := c.transactionStore.GetTransaction(ctx, transactionID)
+	if err != nil {
+		writeStoreError(res, err)
+		return
+	}
+
+	writeResponse(res, http.StatusOK, transaction)
+}
+
+func (c *Server) transactionPostHandler(res http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	ctx := req.Context()
+
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
+		return
+	}
+
+	var got internal.TransactionPostRequest
+	if !unmarshallRequest(res, requestBody, &got) {
+		return
+	}
+
+	transaction, err := c.service.AddTransaction(ctx, got)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	res.Header().Set("Location", fmt.Sprintf("/transactions/%s", transaction.ID))
+	writeResponse(res, http.StatusCreated, transaction)
+}
+
+func (c *Server) transactionPatchHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	transactionID := ps.ByName("transaction")
+
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
+		return
+	}
+
+	var got TransactionPatchRequest
+	if !unmarshallRequest(res, requestBody, &got) {
+		return
+	}
+
+	if got.CategoryID == nil {
+		writeErrors(res, http.StatusBadRequest, newJSONError(internal.ErrorFieldMissing, "/categoryID"))
+		return
+	}
+
+	previous, transaction, err := c.service.CategoriseTransaction(ctx, transactionID, *got.CategoryID, got.Answers)
+	if err != nil {
+		writeServiceError(res, err)
+		return
+	}
+
+	if c.onCategorised != nil {
+		c.onCategorised(previous, transaction)
+	}
+
+	writeResponse(res, http.StatusOK, transaction)
+}
+
+func (c *Server) transactionDeleteHandler(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	ctx := req.Context()
+
+	transactionID := ps.ByName("transaction")
+
+	if err := c.transactionStore.DeleteTransaction(ctx, transactionID); err != nil {
+		writeStoreError(res, err)
+		return
+	}
+
+	writeResponse(res, http.StatusOK, jsonStatus{statusDeleted})
+}
@@ -0,0 +1,609 @@
+package httptransport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+var transactionTimestamp = time.Date(2019, time.March, 1, 12, 30, 0, 0, time.UTC)
+
+func newTransactionTestServer(transactionList *internal.TransactionList) (*Server, *internal.InMemoryTransactionStore) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+			internal.Category{ID: "2345", Name: "food and drink", ParentID: ""},
+		},
+	}
+	questionList := internal.QuestionList{
+		Questions: []internal.Question{
+			internal.Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+			internal.Question{ID: "2", Title: "which meal?", CategoryID: "2345", Type: "string", Options: internal.OptionList{
+				{ID: "a", Title: "brekkie"},
+			}},
+		},
+	}
+	categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+	questionStore := internal.NewInMemoryQuestionStore(&questionList)
+	transactionStore := internal.NewInMemoryTransactionStore(transactionList)
+	return NewServer(categoryStore, questionStore, transactionStore), transactionStore
+}
+
+func TestListTransactions(t *testing.T) {
+
+	transactionList := internal.TransactionList{
+		Transactions: []internal.Transaction{
+			internal.Transaction{ID: "abcdef", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: transactionTimestamp},
+		},
+	}
+	server, _ := newTransactionTestServer(&transactionList)
+
+	t.Run("it returns a json transaction list", func(t *testing.T) {
+		req := newGetRequest(t, "/transactions")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var got internal.TransactionList
+		unmarshallInterfaceFromBody(t, body, &got)
+
+		want := transactionList
+		assertDeepEqual(t, got, want)
+	})
+}
+
+func TestGetTransaction(t *testing.T) {
+
+	transactionList := internal.TransactionList{
+		Transactions: []internal.Transaction{
+			internal.Transaction{ID: "abcdef", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: transactionTimestamp, CategoryID: "2345", Answers: []internal.Answer{
+				{QuestionID: "2", Value: "a"},
+			}},
+		},
+	}
+	server, _ := newTransactionTestServer(&transactionList)
+
+	t.Run("not-found failure reponse", func(t *testing.T) {
+		req := newGetRequest(t, "/transactions/ghijkm")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusNotFound)
+		assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+		assertBodyErrorTitle(t, body, internal.ErrorTransactionNotFound)
+	})
+
+	t.Run("get transaction with answers", func(t *testing.T) {
+		req := newGetRequest(t, "/transactions/abcdef")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var got internal.Transaction
+		unmarshallInterfaceFromBody(t, body, &got)
+		want := transactionList.Transactions[0]
+		assertDeepEqual(t, got, want)
+	})
+}
+
+func TestAddTransaction(t *testing.T) {
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		server, store := newTransactionTestServer(nil)
+
+		cases := map[string]struct {
+			input      string
+			want       int
+			errorTitle string
+		}{
+			"invalid json": {
+				input:      `{"foo":`,
+				want:       http.StatusBadRequest,
+				errorTitle: errorInvalidJSON,
+			},
+			"amount is not an integer": {
+				input:      `{"amount":"-350", "currency":"GBP", "timestamp":"2019-03-01T12:30:00Z"}`,
+				want:       http.StatusBadRequest,
+				errorTitle: errorInvalidJSONFields,
+			},
+			"timestamp is not RFC3339": {
+				input:      `{"amount":-350, "currency":"GBP", "timestamp":"yesterday"}`,
+				want:       http.StatusBadRequest,
+				errorTitle: errorInvalidJSONFields,
+			},
+			"amount missing": {
+				input:      `{"currency":"GBP", "timestamp":"2019-03-01T12:30:00Z"}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorFieldMissing,
+			},
+			"currency is empty": {
+				input:      `{"amount":-350, "currency":"", "timestamp":"2019-03-01T12:30:00Z"}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorCurrencyEmpty,
+			},
+			"timestamp missing": {
+				input:      `{"amount":-350, "currency":"GBP"}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorTimestampEmpty,
+			},
+			"category doesn't exist": {
+				input:      `{"amount":-350, "currency":"GBP", "timestamp":"2019-03-01T12:30:00Z", "categoryID":"5678"}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorCategoryNotFound,
+			},
+			"answer to a question from another category": {
+				input:      `{"amount":-350, "currency":"GBP", "timestamp":"2019-03-01T12:30:00Z", "categoryID":"1234", "answers":[{"questionID":"2", "value":"a"}]}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorAnswerQuestionNotFound,
+			},
+			"answers when uncategorised": {
+				input:      `{"amount":-350, "currency":"GBP", "timestamp":"2019-03-01T12:30:00Z", "answers":[{"questionID":"1", "value":2}]}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorAnswerQuestionNotFound,
+			},
+			"answer is the wrong type": {
+				input:      `{"amount":-350, "currency":"GBP", "timestamp":"2019-03-01T12:30:00Z", "categoryID":"1234", "answers":[{"questionID":"1", "value":"two"}]}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorInvalidAnswer,
+			},
+			"answer is an unknown option": {
+				input:      `{"amount":-350, "currency":"GBP", "timestamp":"2019-03-01T12:30:00Z", "categoryID":"2345", "answers":[{"questionID":"2", "value":"b"}]}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorInvalidAnswer,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				requestBody := strings.NewReader(c.input)
+				req := newPostRequest(t, "/transactions", requestBody)
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the store is unmodified
+				got := len(listTransactions(t, ctx, store).Transactions)
+				assertNumbersEqual(t, got, 0)
+			})
+		}
+
+		problemCases := map[string]struct {
+			input string
+			want  int
+			errs  []jsonError
+		}{
+			"every missing field": {
+				input: `{"currency":""}`,
+				want:  http.StatusBadRequest,
+				errs: []jsonError{
+					{Title: internal.ErrorFieldMissing, Pointer: "/amount"},
+					{Title: internal.ErrorCurrencyEmpty, Pointer: "/currency"},
+					{Title: internal.ErrorTimestampEmpty, Pointer: "/timestamp"},
+				},
+			},
+			"every invalid answer": {
+				input: `{"amount":-350, "currency":"GBP", "timestamp":"2019-03-01T12:30:00Z", "categoryID":"1234", "answers":[{"questionID":"1", "value":"two"}, {"questionID":"2", "value":"a"}]}`,
+				want:  http.StatusUnprocessableEntity,
+				errs: []jsonError{
+					{Title: internal.ErrorInvalidAnswer, Pointer: "/answers/0/value"},
+					{Title: internal.ErrorAnswerQuestionNotFound, Pointer: "/answers/1/questionID"},
+				},
+			},
+			"field of the wrong type": {
+				input: `{"amount":"-350", "currency":"GBP", "timestamp":"2019-03-01T12:30:00Z"}`,
+				want:  http.StatusBadRequest,
+				errs: []jsonError{
+					{Title: errorInvalidJSONFields, Pointer: "/amount", Detail: "cannot be a string"},
+				},
+			},
+		}
+
+		for name, c := range problemCases {
+			t.Run(name+" is reported at once", func(t *testing.T) {
+				req := newPostRequest(t, "/transactions", strings.NewReader(c.input))
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrors(t, body, c.errs)
+
+				assertNumbersEqual(t, len(listTransactions(t, ctx, store).Transactions), 0)
+			})
+		}
+	})
+
+	t.Run("test success response & effect", func(t *testing.T) {
+		server, store := newTransactionTestServer(nil)
+
+		amount := int64(-350)
+		tpr := internal.TransactionPostRequest{
+			Amount:     &amount,
+			Currency:   "GBP",
+			Merchant:   "Pret",
+			Timestamp:  transactionTimestamp,
+			CategoryID: "2345",
+			Answers:    []internal.Answer{{QuestionID: "2", Value: "a"}},
+		}
+		payload, _ := json.Marshal(tpr)
+		req := newPostRequest(t, "/transactions", bytes.NewReader(payload))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusCreated)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var got internal.Transaction
+		unmarshallInterfaceFromBody(t, body, &got)
+
+		want := internal.Transaction{
+			ID:         got.ID,
+			Amount:     amount,
+			Currency:   tpr.Currency,
+			Merchant:   tpr.Merchant,
+			Timestamp:  tpr.Timestamp,
+			CategoryID: tpr.CategoryID,
+			Answers:    tpr.Answers,
+		}
+
+		// check the response
+		assertIsXid(t, got.ID)
+		assertDeepEqual(t, got, want)
+
+		// check the store has been modified
+		got = listTransactions(t, ctx, store).Transactions[0]
+		assertDeepEqual(t, got, want)
+
+		// get ID from store and check that's in returned Location header
+		assertStringsEqual(t, result.Header.Get("Location"), fmt.Sprintf("/transactions/%s", got.ID))
+	})
+}
+
+func TestCategoriseTransaction(t *testing.T) {
+
+	transactionList := internal.TransactionList{
+		Transactions: []internal.Transaction{
+			internal.Transaction{ID: "abcdef", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: transactionTimestamp},
+		},
+	}
+	server, store := newTransactionTestServer(&transactionList)
+
+	var previous, categorised []internal.Transaction
+	server.OnTransactionCategorised(func(p, t internal.Transaction) {
+		previous = append(previous, p)
+		categorised = append(categorised, t)
+	})
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		cases := map[string]struct {
+			path       string
+			input      string
+			want       int
+			errorTitle string
+		}{
+			"invalid json": {
+				path:       "/transactions/abcdef",
+				input:      `{"foo":`,
+				want:       http.StatusBadRequest,
+				errorTitle: errorInvalidJSON,
+			},
+			"categoryID missing": {
+				path:       "/transactions/abcdef",
+				input:      `{}`,
+				want:       http.StatusBadRequest,
+				errorTitle: internal.ErrorFieldMissing,
+			},
+			"ID not found": {
+				path:       "/transactions/ghijkm",
+				input:      `{"categoryID":"1234"}`,
+				want:       http.StatusNotFound,
+				errorTitle: internal.ErrorTransactionNotFound,
+			},
+			"category doesn't exist": {
+				path:       "/transactions/abcdef",
+				input:      `{"categoryID":"5678"}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorCategoryNotFound,
+			},
+			"question answered twice": {
+				path:       "/transactions/abcdef",
+				input:      `{"categoryID":"1234", "answers":[{"questionID":"1", "value":2}, {"questionID":"1", "value":3}]}`,
+				want:       http.StatusUnprocessableEntity,
+				errorTitle: internal.ErrorDuplicateAnswer,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				requestBody := strings.NewReader(c.input)
+				req := newPatchRequest(t, c.path, requestBody)
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, c.want)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the store is unmodified
+				got := listTransactions(t, ctx, store)
+				want := transactionList
+				assertDeepEqual(t, got, want)
+				assertNumbersEqual(t, len(categorised), 0)
+			})
+		}
+	})
+
+	t.Run("test success response & effect", func(t *testing.T) {
+		categoryID := "1234"
+		answers := []internal.Answer{{QuestionID: "1", Value: float64(2)}}
+
+		requestBody, _ := json.Marshal(TransactionPatchRequest{CategoryID: &categoryID, Answers: answers})
+		req := newPatchRequest(t, "/transactions/abcdef", bytes.NewReader(requestBody))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check the response
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+
+		var got internal.Transaction
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertStringsEqual(t, got.ID, "abcdef")
+		assertStringsEqual(t, got.CategoryID, categoryID)
+		assertDeepEqual(t, got.Answers, answers)
+
+		// check the store is updated
+		got = listTransactions(t, ctx, store).Transactions[0]
+		assertStringsEqual(t, got.CategoryID, categoryID)
+		assertDeepEqual(t, got.Answers, answers)
+
+		// check the categorisation was passed on
+		assertNumbersEqual(t, len(categorised), 1)
+		assertDeepEqual(t, previous[0], internal.Transaction{ID: "abcdef", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: transactionTimestamp})
+		assertDeepEqual(t, categorised[0], got)
+	})
+}
+
+func TestCategoriseTransactionConstraints(t *testing.T) {
+	min, max := float64(1), float64(30)
+
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: ""},
+		},
+	}
+	questionList := internal.QuestionList{
+		Questions: []internal.Question{
+			internal.Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number", Constraints: &internal.Constraints{
+				Required: true, Min: &min, Max: &max, Unit: "nights",
+			}},
+			internal.Question{ID: "2", Title: "how many guests?", CategoryID: "1234", Type: "number", Constraints: &internal.Constraints{
+				Default: float64(1),
+			}},
+		},
+	}
+	transactionList := internal.TransactionList{
+		Transactions: []internal.Transaction{
+			internal.Transaction{ID: "abcdef", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: transactionTimestamp},
+		},
+	}
+	categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+	questionStore := internal.NewInMemoryQuestionStore(&questionList)
+	store := internal.NewInMemoryTransactionStore(&transactionList)
+	server := NewServer(categoryStore, questionStore, store)
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		cases := map[string]struct {
+			input      string
+			errorTitle string
+		}{
+			"required question unanswered": {
+				input:      `{"categoryID":"1234", "answers":[{"questionID":"2", "value":2}]}`,
+				errorTitle: internal.ErrorAnswerRequired,
+			},
+			"answer out of range": {
+				input:      `{"categoryID":"1234", "answers":[{"questionID":"1", "value":31}]}`,
+				errorTitle: internal.ErrorAnswerOutOfRange,
+			},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				req := newPatchRequest(t, "/transactions/abcdef", strings.NewReader(c.input))
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				// check the response
+				assertStatusCode(t, result.StatusCode, http.StatusUnprocessableEntity)
+				assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check the store is unmodified
+				assertDeepEqual(t, listTransactions(t, ctx, store), transactionList)
+			})
+		}
+	})
+
+	t.Run("unanswered required questions are named", func(t *testing.T) {
+		requestBody := strings.NewReader(`{"categoryID":"1234", "answers":[]}`)
+		req := newPatchRequest(t, "/transactions/abcdef", requestBody)
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusUnprocessableEntity)
+		assertBodyErrors(t, body, []jsonError{
+			{Title: internal.ErrorAnswerRequired, Pointer: "/answers", Detail: "question 1 is unanswered"},
+		})
+	})
+
+	t.Run("defaults answer unanswered questions", func(t *testing.T) {
+		requestBody := strings.NewReader(`{"categoryID":"1234", "answers":[{"questionID":"1", "value":3}]}`)
+		req := newPatchRequest(t, "/transactions/abcdef", requestBody)
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+
+		var got internal.Transaction
+		unmarshallInterfaceFromBody(t, body, &got)
+
+		want := []internal.Answer{
+			{QuestionID: "1", Value: float64(3)},
+			{QuestionID: "2", Value: float64(1)},
+		}
+		assertDeepEqual(t, got.Answers, want)
+		assertDeepEqual(t, listTransactions(t, ctx, store).Transactions[0].Answers, want)
+	})
+}
+
+func TestCategoriseTransactionInheritedQuestions(t *testing.T) {
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "food and drink", ParentID: ""},
+			internal.Category{ID: "5678", Name: "restaurants", ParentID: "1234"},
+		},
+	}
+	questionList := internal.QuestionList{
+		Questions: []internal.Question{
+			internal.Question{ID: "1", Title: "which meal?", CategoryID: "1234", Type: "string", Options: internal.OptionList{
+				{ID: "a", Title: "breakfast"},
+			}},
+			internal.Question{ID: "2", Title: "how many people?", CategoryID: "1234", Type: "number", HiddenIn: []string{"5678"}},
+		},
+	}
+	transactionList := internal.TransactionList{
+		Transactions: []internal.Transaction{
+			internal.Transaction{ID: "abcdef", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: transactionTimestamp},
+		},
+	}
+	store := internal.NewInMemoryTransactionStore(&transactionList)
+	server := NewServer(internal.NewInMemoryCategoryStore(&categoryList), internal.NewInMemoryQuestionStore(&questionList), store)
+
+	t.Run("a question hidden in the category can't be answered", func(t *testing.T) {
+		requestBody := strings.NewReader(`{"categoryID":"5678", "answers":[{"questionID":"2", "value":2}]}`)
+		req := newPatchRequest(t, "/transactions/abcdef", requestBody)
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusUnprocessableEntity)
+		assertBodyErrorTitle(t, body, internal.ErrorAnswerQuestionNotFound)
+		assertDeepEqual(t, listTransactions(t, ctx, store), transactionList)
+	})
+
+	t.Run("an inherited question can be answered", func(t *testing.T) {
+		requestBody := strings.NewReader(`{"categoryID":"5678", "answers":[{"questionID":"1", "value":"a"}]}`)
+		req := newPatchRequest(t, "/transactions/abcdef", requestBody)
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+
+		var got internal.Transaction
+		unmarshallInterfaceFromBody(t, body, &got)
+		assertDeepEqual(t, got.Answers, []internal.Answer{{QuestionID: "1", Value: "a"}})
+	})
+}
+
+func TestRemoveTransaction(t *testing.T) {
+
+	transactionList := internal.TransactionList{
+		Transactions: []internal.Transaction{
+			internal.Transaction{ID: "abcdef", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: transactionTimestamp},
+		},
+	}
+	server, store := newTransactionTestServer(&transactionList)
+
+	t.Run("test failure responses & effect", func(t *testing.T) {
+		req := newDeleteRequest(t, "/transactions/ghijkm")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check the response
+		assertStatusCode(t, result.StatusCode, http.StatusNotFound)
+		assertContentType(t, result.Header.Get(contentTypeKey), problemContentType)
+		assertBodyErrorTitle(t, body, internal.ErrorTransactionNotFound)
+
+		// check the store is unmodified
+		got := listTransactions(t, ctx, store)
+		want := transactionList
+		assertDeepEqual(t, got, want)
+	})
+
+	t.Run("test success response & effect", func(t *testing.T) {
+		req := newDeleteRequest(t, "/transactions/abcdef")
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		// check response
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+		assertBodyJSONIsStatus(t, body, statusDeleted)
+
+		// check store is updated
+		got := len(listTransactions(t, ctx, store).Transactions)
+		want := 0
+		assertNumbersEqual(t, got, want)
+	})
+}
@@ -0,0 +1,108 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+// ctx is the shared user's context, which requests without a user act for
+var ctx = context.Background()
+
+func asUser(req *http.Request, userID string) *http.Request {
+	req.Header.Set(userIDKey, userID)
+	return req
+}
+
+func TestUsersAreIsolated(t *testing.T) {
+
+	categoryList := internal.CategoryList{
+		Categories: []internal.Category{
+			internal.Category{ID: "1234", Name: "accommodation", ParentID: "", Owner: "alice"},
+		},
+	}
+	questionList := internal.QuestionList{
+		Questions: []internal.Question{
+			internal.Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number", Owner: "alice"},
+		},
+	}
+	transactionList := internal.TransactionList{
+		Transactions: []internal.Transaction{
+			internal.Transaction{ID: "abcdef", Amount: -350, Currency: "GBP", Merchant: "Hostel", Timestamp: transactionTimestamp, CategoryID: "1234", Owner: "alice"},
+		},
+	}
+	categoryStore := internal.NewInMemoryCategoryStore(&categoryList)
+	questionStore := internal.NewInMemoryQuestionStore(&questionList)
+	transactionStore := internal.NewInMemoryTransactionStore(&transactionList)
+	server := NewServer(categoryStore, questionStore, transactionStore)
+
+	alice := internal.WithUser(ctx, "alice")
+
+	t.Run("another user's resources are not found", func(t *testing.T) {
+		cases := map[string]struct {
+			req        *http.Request
+			errorTitle string
+		}{
+			"get category":       {newGetRequest(t, "/categories/1234"), internal.ErrorCategoryNotFound},
+			"rename category":    {newPatchRequest(t, "/categories/1234", strings.NewReader(`{"name":"hotels"}`)), internal.ErrorCategoryNotFound},
+			"remove category":    {newDeleteRequest(t, "/categories/1234?cascade=true"), internal.ErrorCategoryNotFound},
+			"add subcategory":    {newPostRequest(t, "/categories", strings.NewReader(`{"name":"hostel","parentID":"1234"}`)), internal.ErrorParentIDNotFound},
+			"get question":       {newGetRequest(t, "/categories/1234/questions/1"), internal.ErrorQuestionNotFound},
+			"get transaction":    {newGetRequest(t, "/transactions/abcdef"), internal.ErrorTransactionNotFound},
+			"remove transaction": {newDeleteRequest(t, "/transactions/abcdef"), internal.ErrorTransactionNotFound},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, asUser(c.req, "bob"))
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				if result.StatusCode != http.StatusNotFound && result.StatusCode != http.StatusUnprocessableEntity {
+					t.Errorf("got status %d wanted %d or %d", result.StatusCode, http.StatusNotFound, http.StatusUnprocessableEntity)
+				}
+				assertBodyErrorTitle(t, body, c.errorTitle)
+
+				// check alice's stores are unmodified
+				assertDeepEqual(t, listCategories(t, alice, categoryStore), categoryList)
+				assertDeepEqual(t, listQuestions(t, alice, questionStore), questionList)
+				assertDeepEqual(t, listTransactions(t, alice, transactionStore), transactionList)
+			})
+		}
+	})
+
+	t.Run("lists only include the user's own resources", func(t *testing.T) {
+		for path, want := range map[string]string{
+			"/categories":                `{"categories":null}`,
+			"/categories/1234/questions": `{"questions":null}`,
+			"/transactions":              `{"transactions":null}`,
+		} {
+			res := httptest.NewRecorder()
+
+			server.ServeHTTP(res, asUser(newGetRequest(t, path), "bob"))
+
+			assertStatusCode(t, res.Result().StatusCode, http.StatusOK)
+			assertStringsEqual(t, strings.TrimSpace(res.Body.String()), want)
+		}
+	})
+
+	t.Run("category names only need to be unique per user", func(t *testing.T) {
+		res := httptest.NewRecorder()
+
+		req := newPostRequest(t, "/categories", strings.NewReader(`{"name":"accommodation","parentID":""}`))
+		server.ServeHTTP(res, asUser(req, "bob"))
+
+		assertStatusCode(t, res.Result().StatusCode, http.StatusCreated)
+
+		bobs := listCategories(t, internal.WithUser(ctx, "bob"), categoryStore).Categories
+		assertNumbersEqual(t, len(bobs), 1)
+		assertStringsEqual(t, bobs[0].Owner, "bob")
+		assertDeepEqual(t, listCategories(t, alice, categoryStore), categoryList)
+	})
+}
@@ -0,0 +1,61 @@
+package httptransport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+	"github.com/jgillard/practising-go-tdd/monzo"
+)
+
+// monzoWebhookPath is where Monzo is configured to send its webhooks
+const monzoWebhookPath = "/webhooks/monzo"
+
+const (
+	statusImported  = "imported"
+	statusDuplicate = "duplicate"
+	statusIgnored   = "ignored"
+)
+
+// monzoWebhookHandler stores transactions as Monzo creates them
+// Monzo retries anything but a 2xx, so repeat deliveries and event types
+// we don't handle are acknowledged with a 200 rather than rejected
+func (c *Server) monzoWebhookHandler(res http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	ctx := req.Context()
+
+	requestBody, ok := readRequestBody(res, req)
+	if !ok {
+		return
+	}
+
+	var got monzo.WebhookEvent
+	if !unmarshallRequest(res, requestBody, &got) {
+		return
+	}
+
+	if got.Type != monzo.WebhookTransactionCreated {
+		fmt.Printf("ignoring Monzo webhook of type %q\n", got.Type)
+		writeResponse(res, http.StatusOK, jsonStatus{statusIgnored})
+		return
+	}
+
+	if got.Data.ID == "" {
+		writeErrors(res, http.StatusBadRequest, newJSONError(internal.ErrorFieldMissing, "/data/id"))
+		return
+	}
+
+	status := statusImported
+	_, err := c.service.ImportTransaction(ctx, monzo.NewTransaction(got.Data, c.monzoMapping))
+	switch {
+	case errors.Is(err, internal.ErrConflict):
+		status = statusDuplicate
+	case err != nil:
+		writeServiceError(res, err)
+		return
+	}
+
+	writeResponse(res, http.StatusOK, jsonStatus{status})
+}
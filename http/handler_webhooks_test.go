@@ -0,0 +1,112 @@
+package httptransport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+	"github.com/jgillard/practising-go-tdd/monzo"
+)
+
+const monzoTransactionCreated = `{
+	"type": "transaction.created",
+	"data": {
+		"id": "tx_00008zIcpb1TB4yeIFXMzx",
+		"amount": -350,
+		"created": "2019-03-01T12:30:00.000Z",
+		"currency": "GBP",
+		"description": "PRET A MANGER",
+		"category": "eating_out",
+		"merchant": {"id": "merch_00008zIcpbAKe8shBxXUtl", "name": "Pret A Manger"}
+	}
+}`
+
+func TestMonzoWebhook(t *testing.T) {
+
+	t.Run("stores a created transaction once, categorised by the mapping", func(t *testing.T) {
+		server, transactionStore := newTransactionTestServer(nil)
+		server.SetMonzoCategoryMapping(monzo.CategoryMapping{"eating_out": "2345"})
+
+		for i, want := range []string{statusImported, statusDuplicate} {
+			req := newPostRequest(t, "/webhooks/monzo", strings.NewReader(monzoTransactionCreated))
+			res := httptest.NewRecorder()
+
+			server.ServeHTTP(res, req)
+			result := res.Result()
+			body := readBodyJSON(t, result.Body)
+
+			assertStatusCode(t, result.StatusCode, http.StatusOK)
+			assertContentType(t, result.Header.Get(contentTypeKey), jsonContentType)
+			assertBodyJSONIsStatus(t, body, want)
+
+			transactions := listTransactions(t, ctx, transactionStore).Transactions
+			if len(transactions) != 1 {
+				t.Fatalf("delivery %d: got %d transactions wanted 1", i+1, len(transactions))
+			}
+		}
+
+		got := listTransactions(t, ctx, transactionStore).Transactions[0]
+		assertStringsEqual(t, got.MonzoID, "tx_00008zIcpb1TB4yeIFXMzx")
+		assertStringsEqual(t, got.Merchant, "Pret A Manger")
+		assertStringsEqual(t, got.CategoryID, "2345")
+		assertNumbersEqual(t, int(got.Amount), -350)
+		if !got.Timestamp.Equal(transactionTimestamp) {
+			t.Errorf("got timestamp %v wanted %v", got.Timestamp, transactionTimestamp)
+		}
+	})
+
+	t.Run("leaves the transaction uncategorised without a mapping", func(t *testing.T) {
+		server, transactionStore := newTransactionTestServer(nil)
+
+		req := newPostRequest(t, "/webhooks/monzo", strings.NewReader(monzoTransactionCreated))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+
+		assertStatusCode(t, res.Result().StatusCode, http.StatusOK)
+		assertStringsEqual(t, listTransactions(t, ctx, transactionStore).Transactions[0].CategoryID, "")
+	})
+
+	t.Run("acknowledges other event types without storing anything", func(t *testing.T) {
+		server, transactionStore := newTransactionTestServer(nil)
+
+		req := newPostRequest(t, "/webhooks/monzo", strings.NewReader(`{"type": "account.updated", "data": {}}`))
+		res := httptest.NewRecorder()
+
+		server.ServeHTTP(res, req)
+		result := res.Result()
+		body := readBodyJSON(t, result.Body)
+
+		assertStatusCode(t, result.StatusCode, http.StatusOK)
+		assertBodyJSONIsStatus(t, body, statusIgnored)
+		assertNumbersEqual(t, len(listTransactions(t, ctx, transactionStore).Transactions), 0)
+	})
+
+	cases := map[string]struct {
+		body  string
+		title string
+	}{
+		"invalid json":           {`{"type": "transaction.created",`, errorInvalidJSON},
+		"wrong field type":       {`{"type": "transaction.created", "data": {"id": "tx_1", "amount": "lots"}}`, errorInvalidJSONFields},
+		"missing transaction ID": {`{"type": "transaction.created", "data": {"amount": -350}}`, internal.ErrorFieldMissing},
+	}
+
+	for name, c := range cases {
+		t.Run("rejects "+name, func(t *testing.T) {
+			server, transactionStore := newTransactionTestServer(nil)
+
+			req := newPostRequest(t, "/webhooks/monzo", strings.NewReader(c.body))
+			res := httptest.NewRecorder()
+
+			server.ServeHTTP(res, req)
+			result := res.Result()
+			body := readBodyJSON(t, result.Body)
+
+			assertStatusCode(t, result.StatusCode, http.StatusBadRequest)
+			assertBodyErrorTitle(t, body, c.title)
+			assertNumbersEqual(t, len(listTransactions(t, ctx, transactionStore).Transactions), 0)
+		})
+	}
+}
@@ -2,70 +2,229 @@ package httptransport
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
 )
 
-func marshallResponse(data interface{}) []byte {
+// writeResponse writes data as the JSON body of a response with status,
+// or an internal error if it can't be marshalled
+func writeResponse(res http.ResponseWriter, status int, data interface{}) {
 	payload, err := json.Marshal(data)
 	if err != nil {
-		log.Fatal(err)
+		log.Printf("marshalling response: %v", err)
+		writeError(res, http.StatusInternalServerError, errorInternal)
+		return
+	}
+
+	res.WriteHeader(status)
+	res.Write(payload)
+}
+
+// newJSONError describes a problem titled title, with the field at pointer ("" if it's not about a field)
+func newJSONError(title, pointer string) jsonError {
+	return jsonError{
+		Code:    errorCode(title),
+		Title:   title,
+		Pointer: pointer,
+	}
+}
+
+// writeError responds with status and a problem document for title
+func writeError(res http.ResponseWriter, status int, title string) {
+	writeErrors(res, status, newJSONError(title, ""))
+}
+
+// writeErrors responds with status and a problem document listing errs
+func writeErrors(res http.ResponseWriter, status int, errs ...jsonError) {
+	writeProblem(res, status, newJSONProblem(status, errs))
+}
+
+// writeInUseError responds that something can't be removed, as references still refer to it
+func writeInUseError(res http.ResponseWriter, title string, references jsonReferences) {
+	writeProblem(res, http.StatusConflict, jsonInUseErrors{
+		jsonProblem: newJSONProblem(http.StatusConflict, []jsonError{newJSONError(title, "")}),
+		References:  references,
+	})
+}
+
+func newJSONProblem(status int, errs []jsonError) jsonProblem {
+	return jsonProblem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Errors: errs,
 	}
-	return payload
 }
 
-func unmarshallRequest(body []byte, got interface{}) {
-	err := json.Unmarshal(body, got)
-	// json.unmarshall will not error if fields don't match
-	// the error below will catch invalid json
+// writeProblem writes a problem document, which is only ever strings & ints so can't fail to marshall
+// if it somehow does, the panic is turned into a 500 by the middleware
+func writeProblem(res http.ResponseWriter, status int, problem interface{}) {
+	payload, err := json.Marshal(problem)
 	if err != nil {
-		log.Fatal(err)
-		return
+		panic(err)
 	}
+
+	res.Header().Set(contentTypeKey, problemContentType)
+	res.WriteHeader(status)
+	res.Write(payload)
 }
 
-func craftErrorPayload(errorString string) []byte {
-	errorResponse := jsonErrors{}
-	errorResponse.Errors = append(errorResponse.Errors, jsonError{errorString})
-	payload := marshallResponse(errorResponse)
-	return payload
+// problems collects every problem found validating a request, so they can be reported at once
+// the response has the status of the first problem added, so check the most fundamental first
+type problems struct {
+	status int
+	errors []jsonError
 }
 
-func ensureJSONFieldsPresent(res http.ResponseWriter, got, desired interface{}) bool {
-	// if after unmarshall got is empty...
-	if got == desired {
-		fmt.Println("json field(s) missing from request")
-		res.WriteHeader(http.StatusBadRequest)
+// add records a problem titled title with the field at pointer ("" if it's not about a field)
+func (p *problems) add(status int, title, pointer string) {
+	p.addWithDetail(status, title, pointer, "")
+}
+
+// addWithDetail is add, with detail saying more about the problem than its title & pointer can
+func (p *problems) addWithDetail(status int, title, pointer, detail string) {
+	if len(p.errors) == 0 {
+		p.status = status
+	}
+	jsonErr := newJSONError(title, pointer)
+	jsonErr.Detail = detail
+	p.errors = append(p.errors, jsonErr)
+}
+
+// addFieldErrors records each of a domain validation's problems, see validationStatus
+func (p *problems) addFieldErrors(errs internal.FieldErrors) {
+	for _, e := range errs {
+		p.addWithDetail(validationStatus(e.Title), e.Title, e.Pointer, e.Detail)
+	}
+}
+
+// write responds with the problems found, if there were any, returning whether it did
+func (p *problems) write(res http.ResponseWriter) bool {
+	if len(p.errors) == 0 {
 		return false
 	}
+
+	fmt.Printf("request has %d problem(s)\n", len(p.errors))
+	writeErrors(res, p.status, p.errors...)
 	return true
 }
 
-func ensureStringFieldNonEmpty(res http.ResponseWriter, key, title string) bool {
-	if title == "" {
-		fmt.Println(fmt.Sprintf(`"%s" missing from request`, key))
-		res.WriteHeader(http.StatusBadRequest)
+// writeServiceError responds to an error returned by the service,
+// which may be problems validating the request, something being in use, or anything a store returns
+func writeServiceError(res http.ResponseWriter, err error) {
+	var fieldErrs internal.FieldErrors
+	var inUseErr *internal.InUseError
+
+	switch {
+	case errors.As(err, &fieldErrs):
+		var p problems
+		p.addFieldErrors(fieldErrs)
+		p.write(res)
+	case errors.As(err, &inUseErr):
+		fmt.Println(inUseErr.Title)
+		writeInUseError(res, inUseErr.Title, jsonReferences{Categories: inUseErr.Categories, Transactions: inUseErr.Transactions, Questions: inUseErr.Questions})
+	default:
+		writeStoreError(res, err)
+	}
+}
+
+// writeStoreError responds to an error returned by a store
+// ErrNotFound & ErrConflict are the request's fault, and are reported with the title the store gave them
+// anything else is the store failing, which the user can do nothing about
+func writeStoreError(res http.ResponseWriter, err error) {
+	jsonErr := newJSONError(err.Error(), "")
+	var storeErr *internal.StoreError
+	if errors.As(err, &storeErr) {
+		jsonErr = newJSONError(storeErr.Title, storeErr.Pointer)
+	}
+
+	switch {
+	case errors.Is(err, internal.ErrNotFound):
+		fmt.Println(jsonErr.Title)
+		writeErrors(res, http.StatusNotFound, jsonErr)
+	case errors.Is(err, internal.ErrConflict):
+		fmt.Println(jsonErr.Title)
+		writeErrors(res, http.StatusConflict, jsonErr)
+	default:
+		log.Printf("store error: %v", err)
+		writeError(res, http.StatusInternalServerError, errorInternal)
+	}
+}
+
+// readRequestBody reads the request body, which must be valid JSON
+// it writes a 400 & returns false if it can't be read or isn't JSON
+func readRequestBody(res http.ResponseWriter, req *http.Request) ([]byte, bool) {
+	requestBody, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		fmt.Println(err)
+		writeError(res, http.StatusBadRequest, errorUnreadableBody)
+		return nil, false
+	}
+
+	if !jsonIsValid(requestBody) {
+		writeError(res, http.StatusBadRequest, errorInvalidJSON)
+		return nil, false
+	}
+
+	return requestBody, true
+}
+
+// unmarshallRequest unmarshalls the (already valid) JSON body into got
+// json.unmarshall will not error if fields don't match, but will if one has the wrong type
+// in which case it writes a 400 pointing at the field & returns false
+func unmarshallRequest(res http.ResponseWriter, body []byte, got interface{}) bool {
+	if err := json.Unmarshal(body, got); err != nil {
+		fmt.Println(err)
+		writeErrors(res, http.StatusBadRequest, wrongTypeJSONError(errorInvalidJSONFields, err))
 		return false
 	}
 	return true
 }
 
-func ensureNoDuplicates(res http.ResponseWriter, key string, strings []string) bool {
-	noDuplicates := true
+// wrongTypeJSONError describes an error unmarshalling a request as a problem titled title,
+// pointing at the field of the wrong type if json.Unmarshal said which it was
+func wrongTypeJSONError(title string, err error) jsonError {
+	jsonErr := newJSONError(title, "")
 
-	counts := make(map[string]int)
-	for _, str := range strings {
-		counts[str]++
+	if t, ok := err.(*json.UnmarshalTypeError); ok && t.Field != "" {
+		jsonErr.Pointer = "/" + strings.Replace(t.Field, ".", "/", -1)
+		jsonErr.Detail = fmt.Sprintf("cannot be a %s", t.Value)
 	}
 
-	for _, count := range counts {
-		if count > 1 {
-			fmt.Printf(`"%s" contains duplicate strings`, key)
-			res.WriteHeader(http.StatusBadRequest)
-			noDuplicates = false
-		}
+	return jsonErr
+}
+
+// ensureJSONFieldsPresent writes a 400 & returns false if none of got's fields were set
+func ensureJSONFieldsPresent(res http.ResponseWriter, got, desired interface{}) bool {
+	// if after unmarshall got is empty...
+	if got == desired {
+		fmt.Println("json field(s) missing from request")
+		writeError(res, http.StatusBadRequest, internal.ErrorFieldMissing)
+		return false
+	}
+	return true
+}
+
+// ensureCascadeValid parses the optional ?cascade= query parameter
+func ensureCascadeValid(res http.ResponseWriter, req *http.Request) (bool, bool) {
+	value := req.URL.Query().Get("cascade")
+	if value == "" {
+		return false, true
+	}
+
+	cascade, err := strconv.ParseBool(value)
+	if err != nil {
+		fmt.Println(`"cascade" is not a boolean`)
+		writeError(res, http.StatusBadRequest, internal.ErrorInvalidCascade)
+		return false, false
 	}
 
-	return noDuplicates
+	return cascade, true
 }
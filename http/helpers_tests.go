@@ -1,6 +1,7 @@
 package httptransport
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -37,6 +38,14 @@ func newPatchRequest(t *testing.T, path string, body io.Reader) *http.Request {
 	return req
 }
 
+func newPutRequest(t *testing.T, path string, body io.Reader) *http.Request {
+	req, err := http.NewRequest(http.MethodPut, path, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
 func newDeleteRequest(t *testing.T, path string) *http.Request {
 	req, err := http.NewRequest(http.MethodDelete, path, nil)
 	if err != nil {
@@ -117,26 +126,86 @@ func unmarshallInterfaceFromBody(t *testing.T, bodyBytes []byte, got interface{}
 
 func assertBodyErrorTitle(t *testing.T, bodyBytes []byte, title string) {
 	t.Helper()
-	var errors jsonErrors
+	var problem jsonProblem
 
-	err := json.Unmarshal(bodyBytes, &errors)
+	err := json.Unmarshal(bodyBytes, &problem)
 	// check for syntax error or type mismatch
 	if err != nil {
-		t.Log("cannot unmarshall into jsonErrors")
+		t.Log("cannot unmarshall into jsonProblem")
 		t.Fatal(err)
 	}
 
-	if len(errors.Errors) != 1 {
-		t.Fatalf("expected %d errors in response, got %d", 1, len(errors.Errors))
+	if len(problem.Errors) != 1 {
+		t.Fatalf("expected %d errors in response, got %d", 1, len(problem.Errors))
 	}
 
-	assertStringsEqual(t, errors.Errors[0].Title, title)
+	assertStringsEqual(t, problem.Errors[0].Title, title)
+	assertErrorHasCode(t, problem.Errors[0])
 
 }
 
+// assertBodyErrors checks the body lists exactly the errors in want, in order, each with its code
+func assertBodyErrors(t *testing.T, bodyBytes []byte, want []jsonError) {
+	t.Helper()
+	var problem jsonProblem
+	unmarshallInterfaceFromBody(t, bodyBytes, &problem)
+
+	for i := range want {
+		want[i].Code = errorCode(want[i].Title)
+	}
+
+	assertDeepEqual(t, problem.Errors, want)
+	for _, e := range problem.Errors {
+		assertErrorHasCode(t, e)
+	}
+}
+
+func assertErrorHasCode(t *testing.T, e jsonError) {
+	t.Helper()
+	if e.Code == "" || e.Code == errorCodeUnknown {
+		t.Errorf("error '%s' has no code", e.Title)
+	}
+}
+
 func assertOptionsNil(t *testing.T, got internal.OptionList) {
 	t.Helper()
 	if got != nil {
 		t.Fatalf("Options should not have been set, got %v", got)
 	}
 }
+
+func assertBodyInUseReferences(t *testing.T, bodyBytes []byte, transactions int) {
+	t.Helper()
+	var body jsonInUseErrors
+	unmarshallInterfaceFromBody(t, bodyBytes, &body)
+	assertNumbersEqual(t, body.References.Transactions, transactions)
+}
+
+// listCategories, listQuestions & listTransactions read back what a store holds,
+// failing the test straight away if they can't
+func listCategories(t *testing.T, ctx context.Context, store internal.CategoryStore) internal.CategoryList {
+	t.Helper()
+	categoryList, err := store.ListCategories(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return categoryList
+}
+
+func listQuestions(t *testing.T, ctx context.Context, store *internal.InMemoryQuestionStore) internal.QuestionList {
+	t.Helper()
+	questionList, err := store.ListQuestions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return questionList
+}
+
+func listTransactions(t *testing.T, ctx context.Context, store internal.TransactionStore) internal.TransactionList {
+	t.Helper()
+	transactionList, err := store.ListTransactions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transactionList
+}
@@ -1,6 +1,10 @@
 package httptransport
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
 
 type jsonName struct {
 	Name string `json:"name"`
@@ -10,18 +14,63 @@ type jsonTitle struct {
 	Title string `json:"title"`
 }
 
+// jsonOptionTitle is the body adding or renaming an option,
+// Title is a pointer so that an empty title can be told apart from it not being given at all
+type jsonOptionTitle struct {
+	Title *string `json:"title"`
+}
+
+type jsonOptionList struct {
+	Options internal.OptionList `json:"options"`
+}
+
+// jsonAnswers is the body asking for the next questions, given the answers so far
+type jsonAnswers struct {
+	Answers *[]internal.Answer `json:"answers"`
+}
+
 type jsonStatus struct {
 	Status string `json:"status"`
 }
 
+// jsonDeleted reports everything removed alongside a category
+type jsonDeleted struct {
+	Status  string           `json:"status"`
+	Removed internal.Removed `json:"removed"`
+}
+
+// jsonError is one problem with a request
+// Code is stable for clients to match on, where Title is for people and may be reworded
+// Pointer is a JSON Pointer (RFC 6901) to the offending field of the request body, if there is one
 type jsonError struct {
-	Title string `json:"title"`
+	Code    string `json:"code"`
+	Title   string `json:"title"`
+	Pointer string `json:"pointer,omitempty"`
+	Detail  string `json:"detail,omitempty"`
 }
 
-type jsonErrors struct {
+// jsonProblem is the body of every error response, an RFC 7807 problem details object
+// its type is always about:blank, so its title is the text of its status,
+// and the problems themselves are listed in Errors
+type jsonProblem struct {
+	Type   string      `json:"type"`
+	Title  string      `json:"title"`
+	Status int         `json:"status"`
 	Errors []jsonError `json:"errors"`
 }
 
+type jsonReferences struct {
+	Categories   int `json:"categories,omitempty"`
+	Transactions int `json:"transactions"`
+	Questions    int `json:"questions,omitempty"`
+}
+
+// jsonInUseErrors is returned when removing something that is still referenced
+type jsonInUseErrors struct {
+	jsonProblem
+	References jsonReferences `json:"references"`
+}
+
 const (
 	contentTypeKey = "Content-Type"
 	statusDeleted  = "deleted"
@@ -0,0 +1,38 @@
+package httptransport
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/jgillard/practising-go-tdd/auth"
+)
+
+type roleKey struct{}
+
+func withRole(ctx context.Context, role auth.Role) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// roleFromContext returns the role set by the middleware,
+// requests that didn't pass through it are trusted as admins
+func roleFromContext(ctx context.Context) auth.Role {
+	role, ok := ctx.Value(roleKey{}).(auth.Role)
+	if !ok {
+		return auth.RoleAdmin
+	}
+	return role
+}
+
+// allow only lets requests by users with at least role through to handle
+// Every route is registered through it in NewServer, so permissions live in one place
+func (c *Server) allow(role auth.Role, handle httprouter.Handle) httprouter.Handle {
+	return func(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		if roleFromContext(req.Context()) < role {
+			writeError(res, http.StatusForbidden, auth.ErrorForbiddenRole)
+			return
+		}
+		handle(res, req, ps)
+	}
+}
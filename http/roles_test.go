@@ -0,0 +1,99 @@
+package httptransport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jgillard/practising-go-tdd/auth"
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+func TestRoles(t *testing.T) {
+
+	newServer := func() *Server {
+		categoryList := internal.CategoryList{
+			Categories: []internal.Category{
+				internal.Category{ID: "1234", Name: "accommodation", ParentID: "", Owner: "alice"},
+				internal.Category{ID: "5678", Name: "hostels", ParentID: "1234", Owner: "alice"},
+			},
+		}
+		questionList := internal.QuestionList{
+			Questions: []internal.Question{
+				internal.Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number", Owner: "alice"},
+				internal.Question{ID: "2", Title: "what kind?", CategoryID: "1234", Type: "string", Options: internal.OptionList{
+					internal.Option{ID: "a", Title: "hotel"},
+				}, Owner: "alice"},
+			},
+		}
+		server := NewServer(
+			internal.NewInMemoryCategoryStore(&categoryList),
+			internal.NewInMemoryQuestionStore(&questionList),
+			internal.NewInMemoryTransactionStore(nil),
+		)
+		server.SetAuthentication(&auth.Authentication{
+			Authenticators: []auth.Authenticator{auth.APIKeys{
+				"viewer": auth.Identity{UserID: "alice", Role: auth.RoleViewer},
+				"editor": auth.Identity{UserID: "alice", Role: auth.RoleEditor},
+				"admin":  auth.Identity{UserID: "alice", Role: auth.RoleAdmin},
+			}},
+		})
+		return server
+	}
+
+	requests := map[string]struct {
+		method  string
+		path    string
+		body    string
+		minimum auth.Role
+	}{
+		"list categories":          {http.MethodGet, "/categories", "", auth.RoleViewer},
+		"category tree":            {http.MethodGet, "/categories/1234/tree", "", auth.RoleViewer},
+		"get question":             {http.MethodGet, "/categories/1234/questions/1", "", auth.RoleViewer},
+		"list options":             {http.MethodGet, "/categories/1234/questions/2/options", "", auth.RoleViewer},
+		"list effective questions": {http.MethodGet, "/categories/5678/effective-questions", "", auth.RoleViewer},
+		"hide question":            {http.MethodPut, "/categories/5678/hidden-questions/1", "", auth.RoleEditor},
+		"next questions":           {http.MethodPost, "/categories/5678/next-questions", `{"answers":[]}`, auth.RoleViewer},
+		"spending report":          {http.MethodGet, "/reports/spending", "", auth.RoleViewer},
+		"add category":             {http.MethodPost, "/categories", `{"name":"food","parentID":""}`, auth.RoleEditor},
+		"rename category":          {http.MethodPatch, "/categories/1234", `{"name":"hotels"}`, auth.RoleEditor},
+		"rename question":          {http.MethodPatch, "/categories/1234/questions/1", `{"title":"how many guests?"}`, auth.RoleEditor},
+		"add option":               {http.MethodPost, "/categories/1234/questions/2/options", `{"title":"hostel"}`, auth.RoleEditor},
+		"rename option":            {http.MethodPatch, "/categories/1234/questions/2/options/a", `{"title":"motel"}`, auth.RoleEditor},
+		"add transaction":          {http.MethodPost, "/transactions", `{"amount":-350,"currency":"GBP","timestamp":"2019-03-01T12:30:00Z"}`, auth.RoleEditor},
+		"move category":            {http.MethodPost, "/categories/1234/move", `{"parentID":""}`, auth.RoleAdmin},
+		"remove question":          {http.MethodDelete, "/categories/1234/questions/1", "", auth.RoleAdmin},
+		"remove option":            {http.MethodDelete, "/categories/1234/questions/2/options/a", "", auth.RoleAdmin},
+		"remove category":          {http.MethodDelete, "/categories/1234?cascade=true", "", auth.RoleAdmin},
+	}
+
+	for name, r := range requests {
+		for _, role := range []auth.Role{auth.RoleViewer, auth.RoleEditor, auth.RoleAdmin} {
+			t.Run(name+" as "+role.String(), func(t *testing.T) {
+				server := newServer()
+
+				req, err := http.NewRequest(r.method, r.path, strings.NewReader(r.body))
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Set(auth.APIKeyHeader, role.String())
+				res := httptest.NewRecorder()
+
+				server.ServeHTTP(res, req)
+				result := res.Result()
+				body := readBodyJSON(t, result.Body)
+
+				if role < r.minimum {
+					assertStatusCode(t, result.StatusCode, http.StatusForbidden)
+					assertBodyErrorTitle(t, body, auth.ErrorForbiddenRole)
+					return
+				}
+
+				if result.StatusCode >= 300 {
+					t.Errorf("got status %d wanted success, body %s", result.StatusCode, body)
+				}
+			})
+		}
+	}
+}
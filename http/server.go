@@ -1,54 +1,169 @@
 package httptransport
 
 import (
+	"log"
 	"net/http"
+	"runtime/debug"
 
 	"github.com/julienschmidt/httprouter"
 
+	"github.com/jgillard/practising-go-tdd/auth"
 	internal "github.com/jgillard/practising-go-tdd/internal"
+	"github.com/jgillard/practising-go-tdd/monzo"
 )
 
 // Server matches the interface of http.Handler
-// and adds a question and category store
+// and adds a question, category and transaction store
 type Server struct {
-	categoryStore internal.CategoryStore
-	questionStore internal.QuestionStore
+	categoryStore    internal.CategoryStore
+	questionStore    internal.QuestionStore
+	transactionStore internal.TransactionStore
 	http.Handler
+
+	// applies the rules for categories & questions over the same stores
+	service *internal.Service
+
+	// categorises transactions arriving by Monzo webhook
+	monzoMapping monzo.CategoryMapping
+	// called with each transaction after it's (re)categorised
+	onCategorised func(previous, categorised internal.Transaction)
+
+	middleware *middleware
 }
 
 const jsonContentType = "application/json"
 
+// problemContentType is the content type of error responses, see jsonProblem
+const problemContentType = "application/problem+json"
+
+// userIDKey is the header naming the user a request acts for,
+// every store call is scoped to that user's categories, questions & transactions
+// without it requests act for the single shared user ""
+// Once authentication is set, requests act for the authenticated user,
+// and naming anyone else is forbidden
+const userIDKey = "X-User-ID"
+
 type middleware struct {
 	handler http.Handler
+
+	// see Server.SetAuthentication
+	authentication *auth.Authentication
+	exempt         []string
 }
 
 func (m *middleware) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	res.Header().Set(contentTypeKey, jsonContentType)
-	m.handler.ServeHTTP(res, req)
+
+	// a panic in a handler or store fails only its own request, not the whole server
+	defer func() {
+		err := recover()
+		if err == nil {
+			return
+		}
+		// net/http's own way to abort a response, so leave it to net/http
+		if err == http.ErrAbortHandler {
+			panic(err)
+		}
+
+		log.Printf("panic serving %s %s: %v\n%s", req.Method, req.URL.Path, err, debug.Stack())
+		writeError(res, http.StatusInternalServerError, errorInternal)
+	}()
+
+	ctx := req.Context()
+	userID := req.Header.Get(userIDKey)
+
+	// without authentication there are no roles, everyone is trusted as an admin
+	if m.authentication != nil && !m.isExempt(req.URL.Path) {
+		identity, err := m.authentication.Authenticate(credentials(req))
+
+		switch {
+		case err == auth.ErrNoCredentials:
+			res.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(res, http.StatusUnauthorized, auth.ErrorAuthenticationRequired)
+			return
+		case err != nil:
+			res.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeError(res, http.StatusUnauthorized, auth.ErrorInvalidCredentials)
+			return
+		case userID != "" && userID != identity.UserID:
+			writeError(res, http.StatusForbidden, auth.ErrorForbiddenUser)
+			return
+		}
+
+		userID = identity.UserID
+		ctx = withRole(ctx, identity.Role)
+	}
+
+	ctx = internal.WithUser(ctx, userID)
+
+	m.handler.ServeHTTP(res, req.WithContext(ctx))
+}
+
+// Service returns the service the server applies the rules for categories & questions with,
+// for other transports over the same stores to share, so their changes are serialised with the server's
+func (c *Server) Service() *internal.Service {
+	return c.service
 }
 
-// NewServer returns a category & question server,
+// serialised runs mutating handlers that change the stores directly one at a time,
+// and never alongside a change made by the service, see internal.Service.Serialise
+// the stores themselves are safe for concurrent use, so reads aren't held up
+func (c *Server) serialised(handle httprouter.Handle) httprouter.Handle {
+	return func(res http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		c.service.Serialise(func() {
+			handle(res, req, ps)
+		})
+	}
+}
+
+// NewServer returns a category, question & transaction server,
 // with a router & middleware
-func NewServer(cats internal.CategoryStore, questions internal.QuestionStore) *Server {
+func NewServer(cats internal.CategoryStore, questions internal.QuestionStore, transactions internal.TransactionStore) *Server {
 	p := new(Server)
 
 	p.categoryStore = cats
 	p.questionStore = questions
+	p.transactionStore = transactions
+	p.service = internal.NewService(cats, questions, transactions)
 
+	// viewers can read everything, editors can change everything but the category tree's structure,
+	// and only admins can remove categories & their questions
 	router := httprouter.New()
 	router.GET("/status", p.statusHandler)
 
-	router.GET("/categories", p.categoryListHandler)
-	router.GET("/categories/:category", p.categoryGetHandler)
-	router.POST("/categories", p.categoryPostHandler)
-	router.PATCH("/categories/:category", p.categoryPatchHandler)
-	router.DELETE("/categories/:category", p.categoryDeleteHandler)
-
-	router.GET("/categories/:category/questions", p.questionListHandler)
-	router.GET("/categories/:category/questions/:question", p.questionGetHandler)
-	router.POST("/categories/:category/questions", p.questionPostHandler)
-	router.PATCH("/categories/:category/questions/:question", p.questionPatchHandler)
-	router.DELETE("/categories/:category/questions/:question", p.questionDeleteHandler)
+	router.GET("/categories", p.allow(auth.RoleViewer, p.categoryListHandler))
+	router.GET("/categories/:category", p.allow(auth.RoleViewer, p.categoryGetHandler))
+	router.GET("/categories/:category/tree", p.allow(auth.RoleViewer, p.categoryTreeHandler))
+	router.POST("/categories", p.allow(auth.RoleEditor, p.categoryPostHandler))
+	router.PATCH("/categories/:category", p.allow(auth.RoleEditor, p.categoryPatchHandler))
+	router.POST("/categories/:category/move", p.allow(auth.RoleAdmin, p.categoryMoveHandler))
+	router.DELETE("/categories/:category", p.allow(auth.RoleAdmin, p.categoryDeleteHandler))
+
+	router.GET("/categories/:category/questions", p.allow(auth.RoleViewer, p.questionListHandler))
+	router.GET("/categories/:category/questions/:question", p.allow(auth.RoleViewer, p.questionGetHandler))
+	router.POST("/categories/:category/questions", p.allow(auth.RoleEditor, p.questionPostHandler))
+	router.PATCH("/categories/:category/questions/:question", p.allow(auth.RoleEditor, p.questionPatchHandler))
+	router.DELETE("/categories/:category/questions/:question", p.allow(auth.RoleAdmin, p.questionDeleteHandler))
+	router.GET("/categories/:category/effective-questions", p.allow(auth.RoleViewer, p.questionEffectiveListHandler))
+	router.POST("/categories/:category/next-questions", p.allow(auth.RoleViewer, p.questionNextHandler))
+	router.PUT("/categories/:category/hidden-questions/:question", p.allow(auth.RoleEditor, p.questionHideHandler))
+	router.DELETE("/categories/:category/hidden-questions/:question", p.allow(auth.RoleEditor, p.questionUnhideHandler))
+
+	router.GET("/categories/:category/questions/:question/options", p.allow(auth.RoleViewer, p.optionListHandler))
+	router.GET("/categories/:category/questions/:question/options/:option", p.allow(auth.RoleViewer, p.optionGetHandler))
+	router.POST("/categories/:category/questions/:question/options", p.allow(auth.RoleEditor, p.optionPostHandler))
+	router.PATCH("/categories/:category/questions/:question/options/:option", p.allow(auth.RoleEditor, p.optionPatchHandler))
+	router.DELETE("/categories/:category/questions/:question/options/:option", p.allow(auth.RoleAdmin, p.optionDeleteHandler))
+
+	router.GET("/transactions", p.allow(auth.RoleViewer, p.transactionListHandler))
+	router.GET("/transactions/:transaction", p.allow(auth.RoleViewer, p.transactionGetHandler))
+	router.POST("/transactions", p.allow(auth.RoleEditor, p.transactionPostHandler))
+	router.PATCH("/transactions/:transaction", p.allow(auth.RoleEditor, p.transactionPatchHandler))
+	router.DELETE("/transactions/:transaction", p.allow(auth.RoleEditor, p.serialised(p.transactionDeleteHandler)))
+
+	router.GET("/reports/spending", p.allow(auth.RoleViewer, p.spendingReportHandler))
+
+	router.POST(monzoWebhookPath, p.allow(auth.RoleEditor, p.monzoWebhookHandler))
 
 	router.NotFound = http.HandlerFunc(func(res http.ResponseWriter, _ *http.Request) {
 		res.WriteHeader(http.StatusNotFound)
@@ -58,7 +173,32 @@ func NewServer(cats internal.CategoryStore, questions internal.QuestionStore) *S
 		res.WriteHeader(http.StatusMethodNotAllowed)
 	})
 
-	p.Handler = &middleware{router}
+	p.middleware = &middleware{handler: router}
+	p.Handler = p.middleware
 
 	return p
 }
+
+// SetMonzoCategoryMapping sets how transactions received by Monzo webhook are categorised
+// without one they're stored uncategorised
+// Call it before serving any requests
+func (c *Server) SetMonzoCategoryMapping(mapping monzo.CategoryMapping) {
+	c.monzoMapping = mapping
+}
+
+// OnTransactionCategorised sets a func to be called with each transaction, as it was before and is now,
+// after a PATCH (re)categorises it, e.g. to export it back to Monzo
+// It's called before responding, so anything slow should be done in a goroutine
+// Call it before serving any requests
+func (c *Server) OnTransactionCategorised(f func(previous, categorised internal.Transaction)) {
+	c.onCategorised = f
+}
+
+// SetAuthentication requires every request, except to the exempt paths (e.g. "/status"),
+// to be authenticated, see auth.Authentication
+// Requests then act for the authenticated user, see userIDKey, and are limited by their role, see allow
+// Call it before serving any requests
+func (c *Server) SetAuthentication(authentication *auth.Authentication, exempt ...string) {
+	c.middleware.authentication = authentication
+	c.middleware.exempt = exempt
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FileCategoryStore is an InMemoryCategoryStore
+// which snapshots its categories to a JSON file after every change
+// It is safe for concurrent use
+type FileCategoryStore struct {
+	*InMemoryCategoryStore
+	path string
+
+	// held across each change and its snapshot,
+	// so snapshots are written in the same order as the changes
+	writeMu sync.Mutex
+}
+
+// NewFileCategoryStore returns a FileCategoryStore pointer,
+// loaded with any categories previously saved to path
+func NewFileCategoryStore(path string) (*FileCategoryStore, error) {
+	var categoryList CategoryList
+	if err := readJSONFile(path, &categoryList); err != nil {
+		return nil, err
+	}
+	return &FileCategoryStore{InMemoryCategoryStore: NewInMemoryCategoryStore(&categoryList), path: path}, nil
+}
+
+func (s *FileCategoryStore) AddCategory(ctx context.Context, categoryName, parentID string) (Category, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryCategoryStore.listAll()
+
+	category, err := s.InMemoryCategoryStore.AddCategory(ctx, categoryName, parentID)
+	if err != nil {
+		return Category{}, err
+	}
+	return category, s.save(before)
+}
+
+func (s *FileCategoryStore) RenameCategory(ctx context.Context, id, name string) (Category, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryCategoryStore.listAll()
+
+	category, err := s.InMemoryCategoryStore.RenameCategory(ctx, id, name)
+	if err != nil {
+		return Category{}, err
+	}
+	return category, s.save(before)
+}
+
+func (s *FileCategoryStore) MoveCategory(ctx context.Context, id, parentID string) (Category, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryCategoryStore.listAll()
+
+	category, err := s.InMemoryCategoryStore.MoveCategory(ctx, id, parentID)
+	if err != nil {
+		return Category{}, err
+	}
+	return category, s.save(before)
+}
+
+func (s *FileCategoryStore) DeleteCategory(ctx context.Context, id string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryCategoryStore.listAll()
+
+	if err := s.InMemoryCategoryStore.DeleteCategory(ctx, id); err != nil {
+		return err
+	}
+	return s.save(before)
+}
+
+func (s *FileCategoryStore) DeleteCategories(ctx context.Context, ids []string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryCategoryStore.listAll()
+
+	if err := s.InMemoryCategoryStore.DeleteCategories(ctx, ids); err != nil {
+		return err
+	}
+	return s.save(before)
+}
+
+// save snapshots every user's categories
+// if it fails the change is undone by restoring them to before it,
+// so a change reported as failed is never seen, nor saved along with the next one
+func (s *FileCategoryStore) save(before CategoryList) error {
+	if err := writeJSONFileAtomic(s.path, s.InMemoryCategoryStore.listAll()); err != nil {
+		s.InMemoryCategoryStore.restore(before)
+		return fmt.Errorf("could not save categories to %s: %v", s.path, err)
+	}
+	return nil
+}
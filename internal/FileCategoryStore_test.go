@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewFileCategoryStore(t *testing.T) {
+	t.Run("file doesn't exist", func(t *testing.T) {
+		path, cleanup := newTempFilePath(t, "categories.json")
+		defer cleanup()
+
+		store, err := NewFileCategoryStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		categoryList, err := store.ListCategories(ctx)
+		assertNoError(t, err)
+		got := len(categoryList.Categories)
+		want := 0
+		assertNumbersEqual(t, got, want)
+	})
+
+	t.Run("file is not valid json", func(t *testing.T) {
+		path, cleanup := newTempFilePath(t, "categories.json")
+		defer cleanup()
+
+		if err := ioutil.WriteFile(path, []byte(`{"foo":`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := NewFileCategoryStore(path)
+		if err == nil {
+			t.Fatal("expected an error loading invalid json")
+		}
+	})
+}
+
+func TestFileCategoryStore_Persistence(t *testing.T) {
+	path, cleanup := newTempFilePath(t, "categories.json")
+	defer cleanup()
+
+	store, err := NewFileCategoryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accommodation := addCategory(t, ctx, store, "accommodation", "")
+	hostel := addCategory(t, ctx, store, "hostel", accommodation.ID)
+	addCategory(t, ctx, store, "food and drink", "")
+	_, err = store.RenameCategory(ctx, hostel.ID, "hotel")
+	assertNoError(t, err)
+	assertNoError(t, store.DeleteCategory(ctx, accommodation.ID))
+	alicesCategory := addCategory(t, alice, store, "accommodation", "")
+
+	reloaded, err := NewFileCategoryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := reloaded.ListCategories(ctx)
+	assertNoError(t, err)
+	want, err := store.ListCategories(ctx)
+	assertNoError(t, err)
+	assertDeepEqual(t, got, want)
+	assertNumbersEqual(t, len(got.Categories), 2)
+	assertStringsEqual(t, got.Categories[0].Name, "hotel")
+
+	// every user's categories are saved
+	alicesList, err := reloaded.ListCategories(alice)
+	assertNoError(t, err)
+	assertDeepEqual(t, alicesList, CategoryList{Categories: []Category{alicesCategory}})
+
+	// only the snapshot should remain, no temporary files
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNumbersEqual(t, len(files), 1)
+}
+
+func TestFileCategoryStore_SaveFailure(t *testing.T) {
+	path, cleanup := newTempFilePath(t, "categories.json")
+	defer cleanup()
+
+	store, err := NewFileCategoryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accommodation := addCategory(t, ctx, store, "accommodation", "")
+	hostel := addCategory(t, ctx, store, "hostel", accommodation.ID)
+	before, err := store.ListCategories(ctx)
+	assertNoError(t, err)
+
+	// a directory that doesn't exist can't be saved to
+	store.path = filepath.Join(path+".missing", "categories.json")
+
+	if _, err := store.AddCategory(ctx, "food and drink", ""); err == nil {
+		t.Error("expected an error adding a category that can't be saved")
+	}
+	if _, err := store.RenameCategory(ctx, accommodation.ID, "hotels"); err == nil {
+		t.Error("expected an error renaming a category that can't be saved")
+	}
+	if err := store.DeleteCategory(ctx, accommodation.ID); err == nil {
+		t.Error("expected an error removing a category that can't be saved")
+	}
+	if err := store.DeleteCategories(ctx, []string{hostel.ID, accommodation.ID}); err == nil {
+		t.Error("expected an error removing categories that can't be saved")
+	}
+
+	// none of the failed changes are held in memory
+	got, err := store.ListCategories(ctx)
+	assertNoError(t, err)
+	assertDeepEqual(t, got, before)
+}
+
+func TestFileCategoryStore(t *testing.T) {
+	dir, cleanup := newTempFilePath(t, "")
+	defer cleanup()
+
+	n := 0
+	testCategoryStore(t, func() CategoryStore {
+		n++
+		store, err := NewFileCategoryStore(filepath.Join(dir, fmt.Sprintf("categories%d.json", n)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return store
+	})
+}
@@ -0,0 +1,193 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FileQuestionStore is an InMemoryQuestionStore
+// which snapshots its questions to a JSON file after every change
+// It is safe for concurrent use
+type FileQuestionStore struct {
+	*InMemoryQuestionStore
+	path string
+
+	// held across each change and its snapshot,
+	// so snapshots are written in the same order as the changes
+	writeMu sync.Mutex
+}
+
+// NewFileQuestionStore returns a FileQuestionStore pointer,
+// loaded with any questions previously saved to path
+func NewFileQuestionStore(path string) (*FileQuestionStore, error) {
+	var questionList QuestionList
+	if err := readJSONFile(path, &questionList); err != nil {
+		return nil, err
+	}
+	return &FileQuestionStore{InMemoryQuestionStore: NewInMemoryQuestionStore(&questionList), path: path}, nil
+}
+
+func (s *FileQuestionStore) AddQuestion(ctx context.Context, categoryID string, q QuestionPostRequest) (Question, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	question, err := s.InMemoryQuestionStore.AddQuestion(ctx, categoryID, q)
+	if err != nil {
+		return Question{}, err
+	}
+	return question, s.save(before)
+}
+
+func (s *FileQuestionStore) RenameQuestion(ctx context.Context, questionID, questionTitle string) (Question, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	question, err := s.InMemoryQuestionStore.RenameQuestion(ctx, questionID, questionTitle)
+	if err != nil {
+		return Question{}, err
+	}
+	return question, s.save(before)
+}
+
+func (s *FileQuestionStore) SetQuestionConstraints(ctx context.Context, questionID string, constraints *Constraints) (Question, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	question, err := s.InMemoryQuestionStore.SetQuestionConstraints(ctx, questionID, constraints)
+	if err != nil {
+		return Question{}, err
+	}
+	return question, s.save(before)
+}
+
+func (s *FileQuestionStore) SetQuestionDependency(ctx context.Context, questionID string, dependsOn *Dependency) (Question, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	question, err := s.InMemoryQuestionStore.SetQuestionDependency(ctx, questionID, dependsOn)
+	if err != nil {
+		return Question{}, err
+	}
+	return question, s.save(before)
+}
+
+func (s *FileQuestionStore) UpdateQuestion(ctx context.Context, questionID string, update QuestionPatchRequest) (Question, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	question, err := s.InMemoryQuestionStore.UpdateQuestion(ctx, questionID, update)
+	if err != nil {
+		return Question{}, err
+	}
+	return question, s.save(before)
+}
+
+func (s *FileQuestionStore) DeleteQuestion(ctx context.Context, questionID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	if err := s.InMemoryQuestionStore.DeleteQuestion(ctx, questionID); err != nil {
+		return err
+	}
+	return s.save(before)
+}
+
+func (s *FileQuestionStore) DeleteQuestionsForCategory(ctx context.Context, categoryID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	if err := s.InMemoryQuestionStore.DeleteQuestionsForCategory(ctx, categoryID); err != nil {
+		return err
+	}
+	return s.save(before)
+}
+
+func (s *FileQuestionStore) HideQuestion(ctx context.Context, questionID, categoryID string) (Question, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	question, err := s.InMemoryQuestionStore.HideQuestion(ctx, questionID, categoryID)
+	if err != nil {
+		return Question{}, err
+	}
+	return question, s.save(before)
+}
+
+func (s *FileQuestionStore) UnhideQuestion(ctx context.Context, questionID, categoryID string) (Question, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	question, err := s.InMemoryQuestionStore.UnhideQuestion(ctx, questionID, categoryID)
+	if err != nil {
+		return Question{}, err
+	}
+	return question, s.save(before)
+}
+
+func (s *FileQuestionStore) AddOption(ctx context.Context, questionID, optionTitle string) (Option, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	option, err := s.InMemoryQuestionStore.AddOption(ctx, questionID, optionTitle)
+	if err != nil {
+		return Option{}, err
+	}
+	return option, s.save(before)
+}
+
+func (s *FileQuestionStore) RenameOption(ctx context.Context, questionID, optionID, optionTitle string) (Option, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	option, err := s.InMemoryQuestionStore.RenameOption(ctx, questionID, optionID, optionTitle)
+	if err != nil {
+		return Option{}, err
+	}
+	return option, s.save(before)
+}
+
+func (s *FileQuestionStore) DeleteOption(ctx context.Context, questionID, optionID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryQuestionStore.listAll()
+
+	if err := s.InMemoryQuestionStore.DeleteOption(ctx, questionID, optionID); err != nil {
+		return err
+	}
+	return s.save(before)
+}
+
+// save snapshots every user's questions
+// if it fails the change is undone by restoring them to before it,
+// so a change reported as failed is never seen, nor saved along with the next one
+func (s *FileQuestionStore) save(before QuestionList) error {
+	if err := writeJSONFileAtomic(s.path, s.InMemoryQuestionStore.listAll()); err != nil {
+		s.InMemoryQuestionStore.restore(before)
+		return fmt.Errorf("could not save questions to %s: %v", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewFileQuestionStore(t *testing.T) {
+	t.Run("file doesn't exist", func(t *testing.T) {
+		path, cleanup := newTempFilePath(t, "questions.json")
+		defer cleanup()
+
+		store, err := NewFileQuestionStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		questionList, err := store.ListQuestions(ctx)
+		assertNoError(t, err)
+		got := len(questionList.Questions)
+		want := 0
+		assertNumbersEqual(t, got, want)
+	})
+
+	t.Run("file is not valid json", func(t *testing.T) {
+		path, cleanup := newTempFilePath(t, "questions.json")
+		defer cleanup()
+
+		if err := ioutil.WriteFile(path, []byte(`{"foo":`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := NewFileQuestionStore(path)
+		if err == nil {
+			t.Fatal("expected an error loading invalid json")
+		}
+	})
+}
+
+func TestFileQuestionStore_Persistence(t *testing.T) {
+	path, cleanup := newTempFilePath(t, "questions.json")
+	defer cleanup()
+
+	store, err := NewFileQuestionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nights := addQuestion(t, ctx, store, "1234", QuestionPostRequest{Title: "how many nights?", Type: "number"})
+	addQuestion(t, ctx, store, "1234", QuestionPostRequest{Title: "which meal?", Type: "string", Options: &[]string{"brekkie", "lunch"}})
+	_, err = store.RenameQuestion(ctx, nights.ID, "how many days?")
+	assertNoError(t, err)
+
+	reloaded, err := NewFileQuestionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := reloaded.ListQuestions(ctx)
+	assertNoError(t, err)
+	want, err := store.ListQuestions(ctx)
+	assertNoError(t, err)
+	assertDeepEqual(t, got, want)
+	assertStringsEqual(t, got.Questions[0].Title, "how many days?")
+	assertNumbersEqual(t, len(got.Questions[1].Options), 2)
+
+	assertNoError(t, store.DeleteQuestion(ctx, nights.ID))
+
+	reloaded, err = NewFileQuestionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = reloaded.ListQuestions(ctx)
+	assertNoError(t, err)
+	assertNumbersEqual(t, len(got.Questions), 1)
+}
+
+func TestFileQuestionStore_SaveFailure(t *testing.T) {
+	path, cleanup := newTempFilePath(t, "questions.json")
+	defer cleanup()
+
+	store, err := NewFileQuestionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meal := addQuestion(t, ctx, store, "1234", QuestionPostRequest{Title: "which meal?", Type: "string", Options: &[]string{"brekkie", "lunch"}})
+	before, err := store.ListQuestions(ctx)
+	assertNoError(t, err)
+
+	// a directory that doesn't exist can't be saved to
+	store.path = filepath.Join(path+".missing", "questions.json")
+
+	if _, err := store.AddQuestion(ctx, "1234", QuestionPostRequest{Title: "how many nights?", Type: "number"}); err == nil {
+		t.Error("expected an error adding a question that can't be saved")
+	}
+	if _, err := store.RenameOption(ctx, meal.ID, meal.Options[0].ID, "breakfast"); err == nil {
+		t.Error("expected an error renaming an option that can't be saved")
+	}
+	if err := store.DeleteQuestion(ctx, meal.ID); err == nil {
+		t.Error("expected an error removing a question that can't be saved")
+	}
+
+	// none of the failed changes are held in memory
+	got, err := store.ListQuestions(ctx)
+	assertNoError(t, err)
+	assertDeepEqual(t, got, before)
+}
+
+func TestFileQuestionStore(t *testing.T) {
+	dir, cleanup := newTempFilePath(t, "")
+	defer cleanup()
+
+	n := 0
+	testQuestionStore(t, func() (CategoryStore, QuestionStore) {
+		n++
+		categoryStore, err := NewFileCategoryStore(filepath.Join(dir, fmt.Sprintf("categories%d.json", n)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		questionStore, err := NewFileQuestionStore(filepath.Join(dir, fmt.Sprintf("questions%d.json", n)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return categoryStore, questionStore
+	})
+}
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FileTransactionStore is an InMemoryTransactionStore
+// which snapshots its transactions to a JSON file after every change
+// It is safe for concurrent use
+type FileTransactionStore struct {
+	*InMemoryTransactionStore
+	path string
+
+	// held across each change and its snapshot,
+	// so snapshots are written in the same order as the changes
+	writeMu sync.Mutex
+}
+
+// NewFileTransactionStore returns a FileTransactionStore pointer,
+// loaded with any transactions previously saved to path
+func NewFileTransactionStore(path string) (*FileTransactionStore, error) {
+	var transactionList TransactionList
+	if err := readJSONFile(path, &transactionList); err != nil {
+		return nil, err
+	}
+	return &FileTransactionStore{InMemoryTransactionStore: NewInMemoryTransactionStore(&transactionList), path: path}, nil
+}
+
+func (s *FileTransactionStore) AddTransaction(ctx context.Context, transaction Transaction) (Transaction, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryTransactionStore.listAll()
+
+	transaction, err := s.InMemoryTransactionStore.AddTransaction(ctx, transaction)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return transaction, s.save(before)
+}
+
+func (s *FileTransactionStore) ImportTransaction(ctx context.Context, transaction Transaction) (Transaction, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryTransactionStore.listAll()
+
+	// a conflict returns the transaction already imported, and there's nothing new to save
+	transaction, err := s.InMemoryTransactionStore.ImportTransaction(ctx, transaction)
+	if err != nil {
+		return transaction, err
+	}
+	return transaction, s.save(before)
+}
+
+func (s *FileTransactionStore) CategoriseTransaction(ctx context.Context, transactionID, categoryID string, answers []Answer) (Transaction, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryTransactionStore.listAll()
+
+	transaction, err := s.InMemoryTransactionStore.CategoriseTransaction(ctx, transactionID, categoryID, answers)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return transaction, s.save(before)
+}
+
+func (s *FileTransactionStore) DeleteTransaction(ctx context.Context, transactionID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryTransactionStore.listAll()
+
+	if err := s.InMemoryTransactionStore.DeleteTransaction(ctx, transactionID); err != nil {
+		return err
+	}
+	return s.save(before)
+}
+
+func (s *FileTransactionStore) ReassignTransactions(ctx context.Context, fromCategoryID, toCategoryID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryTransactionStore.listAll()
+
+	if err := s.InMemoryTransactionStore.ReassignTransactions(ctx, fromCategoryID, toCategoryID); err != nil {
+		return err
+	}
+	return s.save(before)
+}
+
+func (s *FileTransactionStore) DeleteAnswersForQuestion(ctx context.Context, questionID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryTransactionStore.listAll()
+
+	if err := s.InMemoryTransactionStore.DeleteAnswersForQuestion(ctx, questionID); err != nil {
+		return err
+	}
+	return s.save(before)
+}
+
+func (s *FileTransactionStore) DeleteAnswersForOption(ctx context.Context, questionID, optionID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	before := s.InMemoryTransactionStore.listAll()
+
+	if err := s.InMemoryTransactionStore.DeleteAnswersForOption(ctx, questionID, optionID); err != nil {
+		return err
+	}
+	return s.save(before)
+}
+
+// save snapshots every user's transactions
+// if it fails the change is undone by restoring them to before it,
+// so a change reported as failed is never seen, nor saved along with the next one
+func (s *FileTransactionStore) save(before TransactionList) error {
+	if err := writeJSONFileAtomic(s.path, s.InMemoryTransactionStore.listAll()); err != nil {
+		s.InMemoryTransactionStore.restore(before)
+		return fmt.Errorf("could not save transactions to %s: %v", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileTransactionStore_Persistence(t *testing.T) {
+	path, cleanup := newTempFilePath(t, "transactions.json")
+	defer cleanup()
+
+	store, err := NewFileTransactionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := time.Date(2019, time.March, 1, 12, 30, 0, 0, time.UTC)
+
+	pret := addTransaction(t, ctx, store, Transaction{Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp})
+	addTransaction(t, ctx, store, Transaction{Amount: -1200, Currency: "GBP", Merchant: "Hostel", Timestamp: timestamp})
+	_, err = store.CategoriseTransaction(ctx, pret.ID, "1234", []Answer{{QuestionID: "1", Value: float64(2)}})
+	assertNoError(t, err)
+
+	reloaded, err := NewFileTransactionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := reloaded.ListTransactions(ctx)
+	assertNoError(t, err)
+	want, err := store.ListTransactions(ctx)
+	assertNoError(t, err)
+	assertDeepEqual(t, got, want)
+
+	assertNoError(t, store.DeleteTransaction(ctx, pret.ID))
+
+	reloaded, err = NewFileTransactionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = reloaded.ListTransactions(ctx)
+	assertNoError(t, err)
+	assertNumbersEqual(t, len(got.Transactions), 1)
+}
+
+func TestFileTransactionStore_SaveFailure(t *testing.T) {
+	path, cleanup := newTempFilePath(t, "transactions.json")
+	defer cleanup()
+
+	store, err := NewFileTransactionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := time.Date(2019, time.March, 1, 12, 30, 0, 0, time.UTC)
+	pret := addTransaction(t, ctx, store, Transaction{Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp})
+	before, err := store.ListTransactions(ctx)
+	assertNoError(t, err)
+
+	// a directory that doesn't exist can't be saved to
+	store.path = filepath.Join(path+".missing", "transactions.json")
+
+	if _, err := store.CategoriseTransaction(ctx, pret.ID, "1234", []Answer{{QuestionID: "1", Value: float64(2)}}); err == nil {
+		t.Error("expected an error categorising a transaction that can't be saved")
+	}
+	if err := store.DeleteTransaction(ctx, pret.ID); err == nil {
+		t.Error("expected an error removing a transaction that can't be saved")
+	}
+
+	// none of the failed changes are held in memory
+	got, err := store.ListTransactions(ctx)
+	assertNoError(t, err)
+	assertDeepEqual(t, got, before)
+}
+
+func TestFileTransactionStore(t *testing.T) {
+	dir, cleanup := newTempFilePath(t, "")
+	defer cleanup()
+
+	n := 0
+	testTransactionStore(t, func() (CategoryStore, QuestionStore, TransactionStore) {
+		n++
+		transactionStore, err := NewFileTransactionStore(filepath.Join(dir, fmt.Sprintf("transactions%d.json", n)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewInMemoryCategoryStore(nil), NewInMemoryQuestionStore(nil), transactionStore
+	})
+}
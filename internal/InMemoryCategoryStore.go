@@ -1,10 +1,17 @@
 package internal
 
-import "github.com/rs/xid"
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/xid"
+)
 
 // InMemoryCategoryStore is a list of categories
 // with methods for querying and manipluating those categories
+// It is safe for concurrent use
 type InMemoryCategoryStore struct {
+	mu         sync.RWMutex
 	categories CategoryList
 }
 
@@ -13,111 +20,271 @@ func NewInMemoryCategoryStore(c *CategoryList) *InMemoryCategoryStore {
 	if c == nil {
 		return &InMemoryCategoryStore{}
 	}
-	return &InMemoryCategoryStore{*c}
+	return &InMemoryCategoryStore{categories: *c}
 }
 
-func (s *InMemoryCategoryStore) ListCategories() CategoryList {
-	return s.categories
-}
+func (s *InMemoryCategoryStore) ListCategories(ctx context.Context) (CategoryList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func (s *InMemoryCategoryStore) GetCategory(id string) Category {
-	category := Category{}
+	owner := UserFromContext(ctx)
 
+	// copied so callers never share the slice being mutated
+	var categories []Category
 	for _, c := range s.categories.Categories {
-		if c.ID == id {
-			category = c
+		if c.Owner == owner {
+			categories = append(categories, c)
 		}
 	}
 
-	return category
+	return CategoryList{
+		Categories: categories,
+	}, nil
 }
 
-func (s *InMemoryCategoryStore) GetChildCategories(id string) []Category {
-	children := []Category{}
+func (s *InMemoryCategoryStore) GetCategory(ctx context.Context, id string) (Category, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	for _, c := range s.categories.Categories {
-		if c.ParentID == id {
-			children = append(children, c)
+	i := s.indexOf(ctx, id)
+	if i == -1 {
+		return Category{}, notFound(ErrorCategoryNotFound)
+	}
+
+	return s.categories.Categories[i], nil
+}
+
+func (s *InMemoryCategoryStore) GetChildCategories(ctx context.Context, id string) ([]Category, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.getChildCategories(UserFromContext(ctx), id), nil
+}
+
+// GetDescendantCategories returns every category beneath id,
+// ordered so that parents always come before their children
+func (s *InMemoryCategoryStore) GetDescendantCategories(ctx context.Context, id string) ([]Category, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	owner := UserFromContext(ctx)
+	descendants := []Category{}
+
+	// seen stops a loop of ParentIDs being walked forever
+	seen := map[string]bool{id: true}
+
+	parentIDs := []string{id}
+	for len(parentIDs) > 0 {
+		children := s.getChildCategories(owner, parentIDs[0])
+		parentIDs = parentIDs[1:]
+
+		for _, c := range children {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			descendants = append(descendants, c)
+			parentIDs = append(parentIDs, c.ID)
 		}
 	}
 
-	return children
+	return descendants, nil
 }
 
-func (s *InMemoryCategoryStore) AddCategory(categoryName, parentID string) Category {
+func (s *InMemoryCategoryStore) AddCategory(ctx context.Context, categoryName, parentID string) (Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if parentID != "" && s.indexOf(ctx, parentID) == -1 {
+		return Category{}, notFound(ErrorParentIDNotFound)
+	}
+
+	if s.nameExists(ctx, categoryName, "") {
+		return Category{}, conflict(ErrorDuplicateCategoryName)
+	}
+
 	newCat := Category{
 		ID:       xid.New().String(),
 		Name:     categoryName,
 		ParentID: parentID,
+		Owner:    UserFromContext(ctx),
 	}
 
 	s.categories.Categories = append(s.categories.Categories, newCat)
 
-	return newCat
+	return newCat, nil
 }
 
-func (s *InMemoryCategoryStore) RenameCategory(id, name string) Category {
-	index := 0
+func (s *InMemoryCategoryStore) RenameCategory(ctx context.Context, id, name string) (Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	for i, c := range s.categories.Categories {
-		if c.ID == id {
-			index = i
-			s.categories.Categories[index].Name = name
-			break
+	i := s.indexOf(ctx, id)
+	if i == -1 {
+		return Category{}, notFound(ErrorCategoryNotFound)
+	}
+
+	if s.nameExists(ctx, name, id) {
+		return Category{}, conflict(ErrorDuplicateCategoryName)
+	}
+
+	s.categories.Categories[i].Name = name
+
+	return s.categories.Categories[i], nil
+}
+
+// MoveCategory changes the category's parent, "" making it top level
+func (s *InMemoryCategoryStore) MoveCategory(ctx context.Context, id, parentID string) (Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, id)
+	if i == -1 {
+		return Category{}, notFound(ErrorCategoryNotFound)
+	}
+
+	if parentID != "" && s.indexOf(ctx, parentID) == -1 {
+		return Category{}, notFound(ErrorParentIDNotFound)
+	}
+
+	s.categories.Categories[i].ParentID = parentID
+
+	return s.categories.Categories[i], nil
+}
+
+func (s *InMemoryCategoryStore) DeleteCategory(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, id)
+	if i == -1 {
+		return notFound(ErrorCategoryNotFound)
+	}
+
+	s.categories.Categories = append(s.categories.Categories[:i], s.categories.Categories[i+1:]...)
+
+	return nil
+}
+
+func (s *InMemoryCategoryStore) DeleteCategories(ctx context.Context, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleting := map[string]bool{}
+	for _, id := range ids {
+		if s.indexOf(ctx, id) == -1 {
+			return notFound(ErrorCategoryNotFound)
 		}
+		deleting[id] = true
 	}
 
-	return s.categories.Categories[index]
+	owner := UserFromContext(ctx)
+
+	var kept []Category
+	for _, c := range s.categories.Categories {
+		if c.Owner != owner || !deleting[c.ID] {
+			kept = append(kept, c)
+		}
+	}
+	s.categories.Categories = kept
+
+	return nil
 }
 
-func (s *InMemoryCategoryStore) DeleteCategory(id string) {
-	index := 0
+func (s *InMemoryCategoryStore) CategoryIDExists(ctx context.Context, categoryID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	for i, c := range s.categories.Categories {
-		if c.ID == id {
-			index = i
-			break
+	return s.indexOf(ctx, categoryID) != -1, nil
+}
+
+// CategoryNameExists only checks the user's own categories,
+// different users may each have a category with the same name
+func (s *InMemoryCategoryStore) CategoryNameExists(ctx context.Context, categoryName string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.nameExists(ctx, categoryName, ""), nil
+}
+
+// GetCategoryDepth follows the category's ParentIDs up to the top level
+func (s *InMemoryCategoryStore) GetCategoryDepth(ctx context.Context, categoryID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	depth := 0
+	seen := map[string]bool{}
+
+	i := s.indexOf(ctx, categoryID)
+	for i != -1 && s.categories.Categories[i].ParentID != "" {
+		if seen[categoryID] {
+			return 0, conflict(ErrorCategoryCycle)
 		}
+		seen[categoryID] = true
+
+		categoryID = s.categories.Categories[i].ParentID
+		i = s.indexOf(ctx, categoryID)
+		depth++
 	}
 
-	s.categories.Categories = append(s.categories.Categories[:index], s.categories.Categories[index+1:]...)
+	return depth, nil
 }
 
-func (s *InMemoryCategoryStore) CategoryIDExists(categoryID string) bool {
-	alreadyExists := false
+// nameExists reports whether the user has a category named categoryName, other than exceptID
+// It expects the caller to hold the lock
+func (s *InMemoryCategoryStore) nameExists(ctx context.Context, categoryName, exceptID string) bool {
+	owner := UserFromContext(ctx)
 
 	for _, c := range s.categories.Categories {
-		if c.ID == categoryID {
-			alreadyExists = true
+		if c.Owner == owner && c.Name == categoryName && c.ID != exceptID {
+			return true
 		}
 	}
 
-	return alreadyExists
+	return false
 }
 
-func (s *InMemoryCategoryStore) CategoryNameExists(categoryName string) bool {
-	alreadyExists := false
+// indexOf returns the index of the user's category, or -1 if they have no such category
+// It expects the caller to hold the lock
+func (s *InMemoryCategoryStore) indexOf(ctx context.Context, id string) int {
+	owner := UserFromContext(ctx)
 
-	for _, c := range s.categories.Categories {
-		if c.Name == categoryName {
-			alreadyExists = true
+	for i, c := range s.categories.Categories {
+		if c.ID == id && c.Owner == owner {
+			return i
 		}
 	}
 
-	return alreadyExists
+	return -1
 }
 
-func (s *InMemoryCategoryStore) GetCategoryDepth(categoryID string) int {
-	depth := 0
+// getChildCategories expects the caller to hold the lock
+func (s *InMemoryCategoryStore) getChildCategories(owner, id string) []Category {
+	children := []Category{}
 
 	for _, c := range s.categories.Categories {
-		if c.ID == categoryID {
-			// if already a subcategory
-			if c.ParentID != "" {
-				depth = 1
-			}
+		if c.ParentID == id && c.Owner == owner {
+			children = append(children, c)
 		}
 	}
 
-	return depth
+	return children
+}
+
+// listAll returns every user's categories, for snapshotting the whole store
+func (s *InMemoryCategoryStore) listAll() CategoryList {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return CategoryList{
+		Categories: append([]Category(nil), s.categories.Categories...),
+	}
+}
+
+// restore replaces every user's categories with a snapshot taken by listAll
+func (s *InMemoryCategoryStore) restore(categories CategoryList) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.categories = categories
 }
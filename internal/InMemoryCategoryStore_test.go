@@ -16,112 +16,33 @@ func TestInMemoryCategoryStore_ListCategories(t *testing.T) {
 	}
 	store := NewInMemoryCategoryStore(&categoryList)
 
-	got := store.ListCategories()
+	got, err := store.ListCategories(ctx)
+	assertNoError(t, err)
 	want := categoryList
 	assertDeepEqual(t, got, want)
 }
 
-func TestInMemoryCategoryStore_GetChildCategories(t *testing.T) {
-	categoryList := CategoryList{
-		Categories: []Category{
-			Category{ID: "1234", Name: "accommodation", ParentID: ""},
-			Category{ID: "1235", Name: "foo", ParentID: "1234"},
-			Category{ID: "1236", Name: "bar", ParentID: "1235"},
-		},
-	}
-	store := NewInMemoryCategoryStore(&categoryList)
-
-	t.Run("has children", func(t *testing.T) {
-		got := store.GetChildCategories("1234")
-		want := []Category{
-			categoryList.Categories[1],
-		}
-		assertDeepEqual(t, got, want)
-	})
-
-	t.Run("no children", func(t *testing.T) {
-		got := store.GetChildCategories("1236")
-		want := []Category{}
-		assertDeepEqual(t, got, want)
-	})
-}
-
-func TestInMemoryCategoryStore_GetCategory(t *testing.T) {
-	category := Category{ID: "1234", Name: "accommodation"}
-	categoryList := CategoryList{
-		Categories: []Category{
-			category,
-		},
-	}
-	store := NewInMemoryCategoryStore(&categoryList)
-
-	t.Run("ID doesn't exist", func(t *testing.T) {
-		got := store.GetCategory("abcd")
-		want := Category{}
-		assertDeepEqual(t, got, want)
-	})
-
-	t.Run("ID exists", func(t *testing.T) {
-		got := store.GetCategory("1234")
-		want := category
-		assertDeepEqual(t, got, want)
+func TestInMemoryCategoryStore(t *testing.T) {
+	testCategoryStore(t, func() CategoryStore {
+		return NewInMemoryCategoryStore(nil)
 	})
 }
 
-func TestInMemoryCategoryStore_AddCategory(t *testing.T) {
-	store := NewInMemoryCategoryStore(nil)
-
-	categoryName := "accomodation"
-	parentID := "1234"
-
-	got := store.AddCategory(categoryName, parentID)
-
-	// assert response
-	assertIsXid(t, got.ID)
-	assertStringsEqual(t, got.Name, categoryName)
-	assertStringsEqual(t, got.ParentID, parentID)
-
-	// assert store
-	got = store.categories.Categories[0]
-	assertIsXid(t, got.ID)
-	assertStringsEqual(t, got.Name, categoryName)
-	assertStringsEqual(t, got.ParentID, parentID)
-}
-
-func TestInMemoryCategoryStore_RenameCategory(t *testing.T) {
-	category := Category{ID: "1234", Name: "accommodation"}
-	categoryList := CategoryList{
-		Categories: []Category{
-			category,
-		},
-	}
-	store := NewInMemoryCategoryStore(&categoryList)
-
-	newName := "new name"
-
-	got := store.RenameCategory("1234", newName)
-
-	// assert response
-	assertStringsEqual(t, got.Name, newName)
-
-	// assert store
-	got = store.categories.Categories[0]
-	assertStringsEqual(t, got.ID, "1234")
-	assertStringsEqual(t, got.Name, newName)
-}
-
-func TestInMemoryCategoryStore_DeleteCategory(t *testing.T) {
-	category := Category{ID: "1234", Name: "accommodation"}
+func TestInMemoryCategoryStore_Cycle(t *testing.T) {
+	// can't be made through the store, but could be loaded from a corrupt file
 	categoryList := CategoryList{
 		Categories: []Category{
-			category,
+			Category{ID: "1234", Name: "accommodation", ParentID: "5678"},
+			Category{ID: "5678", Name: "hostel", ParentID: "1234"},
+			Category{ID: "abcd", Name: "dorm", ParentID: "5678"},
 		},
 	}
 	store := NewInMemoryCategoryStore(&categoryList)
 
-	store.DeleteCategory("1234")
+	_, err := store.GetCategoryDepth(ctx, "abcd")
+	assertErrorIs(t, err, ErrConflict)
 
-	got := len(store.categories.Categories)
-	want := 0
-	assertNumbersEqual(t, got, want)
+	descendants, err := store.GetDescendantCategories(ctx, "1234")
+	assertNoError(t, err)
+	assertDeepEqual(t, descendants, []Category{categoryList.Categories[1], categoryList.Categories[2]})
 }
@@ -1,12 +1,17 @@
 package internal
 
 import (
+	"context"
+	"sync"
+
 	"github.com/rs/xid"
 )
 
 // InMemoryQuestionStore is a list of questions
 // with methods for querying and manipluating those questions
+// It is safe for concurrent use
 type InMemoryQuestionStore struct {
+	mu           sync.RWMutex
 	questionList QuestionList
 }
 
@@ -15,114 +20,371 @@ func NewInMemoryQuestionStore(q *QuestionList) *InMemoryQuestionStore {
 	if q == nil {
 		return &InMemoryQuestionStore{}
 	}
-	return &InMemoryQuestionStore{*q}
+	return &InMemoryQuestionStore{questionList: *q}
 }
 
-func (s *InMemoryQuestionStore) ListQuestions() QuestionList {
-	return s.questionList
+func (s *InMemoryQuestionStore) ListQuestions(ctx context.Context) (QuestionList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	owner := UserFromContext(ctx)
+
+	// copied so callers never share the slice being mutated
+	var questions []Question
+	for _, q := range s.questionList.Questions {
+		if q.Owner == owner {
+			questions = append(questions, q)
+		}
+	}
+
+	return QuestionList{
+		Questions: questions,
+	}, nil
 }
 
-func (s *InMemoryQuestionStore) ListQuestionsForCategory(categoryID string) QuestionList {
+func (s *InMemoryQuestionStore) ListQuestionsForCategory(ctx context.Context, categoryID string) (QuestionList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	owner := UserFromContext(ctx)
+
 	var questionList QuestionList
 	for _, q := range s.questionList.Questions {
-		if q.CategoryID == categoryID {
+		if q.CategoryID == categoryID && q.Owner == owner {
 			questionList.Questions = append(questionList.Questions, q)
 		}
 	}
-	return questionList
+	return questionList, nil
 }
 
-func (s *InMemoryQuestionStore) GetQuestion(questionID string) Question {
-	var question = Question{}
+func (s *InMemoryQuestionStore) GetQuestion(ctx context.Context, questionID string) (Question, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	for _, q := range s.questionList.Questions {
-		if q.ID == questionID {
-			question = q
-		}
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return Question{}, notFound(ErrorQuestionNotFound)
 	}
 
-	return question
+	return s.questionList.Questions[i], nil
 }
 
-func (s *InMemoryQuestionStore) AddQuestion(categoryID string, q QuestionPostRequest) Question {
+func (s *InMemoryQuestionStore) AddQuestion(ctx context.Context, categoryID string, q QuestionPostRequest) (Question, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.titleExists(ctx, categoryID, q.Title, "") {
+		return Question{}, conflict(ErrorDuplicateTitle)
+	}
+
 	question := Question{
-		ID:         xid.New().String(),
-		Title:      q.Title,
-		CategoryID: categoryID,
-		Type:       q.Type,
-	}
-
-	if q.Type == "string" {
-		question.Options = OptionList{}
-		for _, title := range *q.Options {
-			option := Option{
-				ID:    xid.New().String(),
-				Title: title,
-			}
-			question.Options = append(question.Options, option)
-		}
+		ID:          xid.New().String(),
+		Title:       q.Title,
+		CategoryID:  categoryID,
+		Type:        q.Type,
+		Constraints: q.Constraints,
+		DependsOn:   q.DependsOn,
+		Overrides:   q.Overrides,
+		Owner:       UserFromContext(ctx),
+	}
+
+	if typeHasOptions(q.Type) {
+		question.Options = newOptionList(q.Options)
 	}
 
 	s.questionList.Questions = append(s.questionList.Questions, question)
 
-	return question
+	return question, nil
 }
 
-func (s *InMemoryQuestionStore) RenameQuestion(questionID, questionTitle string) Question {
-	index := 0
+func (s *InMemoryQuestionStore) RenameQuestion(ctx context.Context, questionID, questionTitle string) (Question, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	for i, q := range s.questionList.Questions {
-		if q.ID == questionID {
-			index = i
-			s.questionList.Questions[index].Title = questionTitle
-			break
-		}
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return Question{}, notFound(ErrorQuestionNotFound)
 	}
 
-	return s.questionList.Questions[index]
+	if s.titleExists(ctx, s.questionList.Questions[i].CategoryID, questionTitle, questionID) {
+		return Question{}, conflict(ErrorDuplicateTitle)
+	}
+
+	s.questionList.Questions[i].Title = questionTitle
+
+	return s.questionList.Questions[i], nil
 }
 
-func (s *InMemoryQuestionStore) DeleteQuestion(questionID string) {
-	index := 0
-	for i, q := range s.questionList.Questions {
-		if q.ID == questionID {
-			index = i
-			break
+func (s *InMemoryQuestionStore) SetQuestionConstraints(ctx context.Context, questionID string, constraints *Constraints) (Question, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return Question{}, notFound(ErrorQuestionNotFound)
+	}
+
+	s.questionList.Questions[i].Constraints = constraints
+
+	return s.questionList.Questions[i], nil
+}
+
+func (s *InMemoryQuestionStore) SetQuestionDependency(ctx context.Context, questionID string, dependsOn *Dependency) (Question, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return Question{}, notFound(ErrorQuestionNotFound)
+	}
+
+	s.questionList.Questions[i].DependsOn = dependsOn
+
+	return s.questionList.Questions[i], nil
+}
+
+func (s *InMemoryQuestionStore) UpdateQuestion(ctx context.Context, questionID string, update QuestionPatchRequest) (Question, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return Question{}, notFound(ErrorQuestionNotFound)
+	}
+
+	question := s.questionList.Questions[i]
+
+	if update.Title != nil {
+		if s.titleExists(ctx, question.CategoryID, *update.Title, questionID) {
+			return Question{}, conflict(ErrorDuplicateTitle)
 		}
+		question.Title = *update.Title
+	}
+
+	if update.Constraints != nil {
+		question.Constraints = withoutEmptyConstraints(update.Constraints)
+	}
+
+	if update.DependsOn != nil {
+		question.DependsOn = withoutEmptyDependency(update.DependsOn)
+	}
+
+	s.questionList.Questions[i] = question
+
+	return question, nil
+}
+
+func (s *InMemoryQuestionStore) DeleteQuestion(ctx context.Context, questionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return notFound(ErrorQuestionNotFound)
 	}
-	s.questionList.Questions = append(s.questionList.Questions[:index], s.questionList.Questions[index+1:]...)
+
+	s.questionList.Questions = append(s.questionList.Questions[:i], s.questionList.Questions[i+1:]...)
+
+	return nil
 }
 
-func (s *InMemoryQuestionStore) QuestionIDExists(questionID string) bool {
-	exists := false
+func (s *InMemoryQuestionStore) DeleteQuestionsForCategory(ctx context.Context, categoryID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner := UserFromContext(ctx)
+
+	var remaining []Question
 	for _, q := range s.questionList.Questions {
-		if q.ID == questionID {
-			exists = true
+		if q.CategoryID != categoryID || q.Owner != owner {
+			remaining = append(remaining, q)
 		}
 	}
-	return exists
+	s.questionList.Questions = remaining
+
+	return nil
 }
 
-func (s *InMemoryQuestionStore) QuestionTitleExists(categoryID, questionTitle string) bool {
-	alreadyExists := false
-	for _, q := range s.questionList.Questions {
-		if q.CategoryID == categoryID {
-			if q.Title == questionTitle {
-				alreadyExists = true
-			}
+// HideQuestion & UnhideQuestion replace the categories the question is hidden in rather than changing them in place,
+// as callers may still hold the question they were given before
+func (s *InMemoryQuestionStore) HideQuestion(ctx context.Context, questionID, categoryID string) (Question, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return Question{}, notFound(ErrorQuestionNotFound)
+	}
+
+	question := s.questionList.Questions[i]
+	if !question.isHiddenIn(categoryID) {
+		s.questionList.Questions[i].HiddenIn = append(append([]string{}, question.HiddenIn...), categoryID)
+	}
+
+	return s.questionList.Questions[i], nil
+}
+
+func (s *InMemoryQuestionStore) UnhideQuestion(ctx context.Context, questionID, categoryID string) (Question, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return Question{}, notFound(ErrorQuestionNotFound)
+	}
+
+	question := s.questionList.Questions[i]
+	if !question.isHiddenIn(categoryID) {
+		return Question{}, notFound(ErrorQuestionNotHidden)
+	}
+
+	var hiddenIn []string
+	for _, id := range question.HiddenIn {
+		if id != categoryID {
+			hiddenIn = append(hiddenIn, id)
 		}
 	}
-	return alreadyExists
+	s.questionList.Questions[i].HiddenIn = hiddenIn
+
+	return s.questionList.Questions[i], nil
+}
+
+// AddOption, RenameOption & DeleteOption replace the question's options rather than changing them in place,
+// as callers may still hold the question they were given before
+func (s *InMemoryQuestionStore) AddOption(ctx context.Context, questionID, optionTitle string) (Option, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return Option{}, notFound(ErrorQuestionNotFound)
+	}
+
+	options := s.questionList.Questions[i].Options
+	if optionTitleExists(options, optionTitle, "") {
+		return Option{}, conflict(ErrorDuplicateOption)
+	}
+
+	option := Option{
+		ID:    xid.New().String(),
+		Title: optionTitle,
+	}
+	s.questionList.Questions[i].Options = append(append(OptionList{}, options...), option)
+
+	return option, nil
+}
+
+func (s *InMemoryQuestionStore) RenameOption(ctx context.Context, questionID, optionID, optionTitle string) (Option, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return Option{}, notFound(ErrorQuestionNotFound)
+	}
+
+	options := append(OptionList{}, s.questionList.Questions[i].Options...)
+	j := options.indexOf(optionID)
+	if j == -1 {
+		return Option{}, notFound(ErrorOptionNotFound)
+	}
+
+	if optionTitleExists(options, optionTitle, optionID) {
+		return Option{}, conflict(ErrorDuplicateOption)
+	}
+
+	options[j].Title = optionTitle
+	s.questionList.Questions[i].Options = options
+
+	return options[j], nil
+}
+
+func (s *InMemoryQuestionStore) DeleteOption(ctx context.Context, questionID, optionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, questionID)
+	if i == -1 {
+		return notFound(ErrorQuestionNotFound)
+	}
+
+	options := s.questionList.Questions[i].Options
+	j := options.indexOf(optionID)
+	if j == -1 {
+		return notFound(ErrorOptionNotFound)
+	}
+
+	s.questionList.Questions[i].Options = append(append(OptionList{}, options[:j]...), options[j+1:]...)
+
+	return nil
+}
+
+func (s *InMemoryQuestionStore) QuestionIDExists(ctx context.Context, questionID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.indexOf(ctx, questionID) != -1, nil
 }
 
-func (s *InMemoryQuestionStore) QuestionBelongsToCategory(questionID, categoryID string) bool {
+func (s *InMemoryQuestionStore) QuestionTitleExists(ctx context.Context, categoryID, questionTitle string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.titleExists(ctx, categoryID, questionTitle, ""), nil
+}
+
+func (s *InMemoryQuestionStore) QuestionBelongsToCategory(ctx context.Context, questionID, categoryID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	belongsToCategory := true
+	if i := s.indexOf(ctx, questionID); i != -1 && s.questionList.Questions[i].CategoryID != categoryID {
+		belongsToCategory = false
+	}
+	return belongsToCategory, nil
+}
+
+// titleExists reports whether the user has a question titled questionTitle in the category, other than exceptID
+// It expects the caller to hold the lock
+func (s *InMemoryQuestionStore) titleExists(ctx context.Context, categoryID, questionTitle, exceptID string) bool {
+	owner := UserFromContext(ctx)
+
 	for _, q := range s.questionList.Questions {
-		if q.ID == questionID {
-			if q.CategoryID != categoryID {
-				belongsToCategory = false
-			}
+		if q.CategoryID == categoryID && q.Owner == owner && q.Title == questionTitle && q.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// indexOf returns the index of the user's question, or -1 if they have no such question
+// It expects the caller to hold the lock
+func (s *InMemoryQuestionStore) indexOf(ctx context.Context, questionID string) int {
+	owner := UserFromContext(ctx)
+
+	for i, q := range s.questionList.Questions {
+		if q.ID == questionID && q.Owner == owner {
+			return i
 		}
 	}
-	return belongsToCategory
+
+	return -1
+}
+
+// listAll returns every user's questions, for snapshotting the whole store
+func (s *InMemoryQuestionStore) listAll() QuestionList {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return QuestionList{
+		Questions: append([]Question(nil), s.questionList.Questions...),
+	}
+}
+
+// restore replaces every user's questions with a snapshot taken by listAll
+func (s *InMemoryQuestionStore) restore(questionList QuestionList) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.questionList = questionList
 }
@@ -0,0 +1,297 @@
+package internal
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/xid"
+)
+
+// InMemoryTransactionStore is a list of transactions
+// with methods for querying and manipluating those transactions
+// It is safe for concurrent use
+type InMemoryTransactionStore struct {
+	mu              sync.RWMutex
+	transactionList TransactionList
+}
+
+// NewInMemoryTransactionStore returns an initialised InMemoryTransactionStore pointer
+func NewInMemoryTransactionStore(t *TransactionList) *InMemoryTransactionStore {
+	if t == nil {
+		return &InMemoryTransactionStore{}
+	}
+	return &InMemoryTransactionStore{transactionList: *t}
+}
+
+func (s *InMemoryTransactionStore) ListTransactions(ctx context.Context) (TransactionList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	owner := UserFromContext(ctx)
+
+	// copied so callers never share the slice being mutated
+	var transactions []Transaction
+	for _, t := range s.transactionList.Transactions {
+		if t.Owner == owner {
+			transactions = append(transactions, t)
+		}
+	}
+
+	return TransactionList{
+		Transactions: transactions,
+	}, nil
+}
+
+func (s *InMemoryTransactionStore) GetTransaction(ctx context.Context, transactionID string) (Transaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := s.indexOf(ctx, transactionID)
+	if i == -1 {
+		return Transaction{}, notFound(ErrorTransactionNotFound)
+	}
+
+	return s.transactionList.Transactions[i], nil
+}
+
+func (s *InMemoryTransactionStore) AddTransaction(ctx context.Context, transaction Transaction) (Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transaction.ID = xid.New().String()
+	transaction.Owner = UserFromContext(ctx)
+
+	s.transactionList.Transactions = append(s.transactionList.Transactions, transaction)
+
+	return transaction, nil
+}
+
+// ImportTransaction adds the transaction unless the user has already imported one with the same MonzoID,
+// in which case that one is returned along with an error wrapping ErrConflict
+func (s *InMemoryTransactionStore) ImportTransaction(ctx context.Context, transaction Transaction) (Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner := UserFromContext(ctx)
+
+	for _, t := range s.transactionList.Transactions {
+		if transaction.MonzoID != "" && t.MonzoID == transaction.MonzoID && t.Owner == owner {
+			return t, conflict(ErrorDuplicateMonzoID)
+		}
+	}
+
+	transaction.ID = xid.New().String()
+	transaction.Owner = owner
+
+	s.transactionList.Transactions = append(s.transactionList.Transactions, transaction)
+
+	return transaction, nil
+}
+
+func (s *InMemoryTransactionStore) CategoriseTransaction(ctx context.Context, transactionID, categoryID string, answers []Answer) (Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, transactionID)
+	if i == -1 {
+		return Transaction{}, notFound(ErrorTransactionNotFound)
+	}
+
+	s.transactionList.Transactions[i].CategoryID = categoryID
+	s.transactionList.Transactions[i].Answers = answers
+
+	return s.transactionList.Transactions[i], nil
+}
+
+func (s *InMemoryTransactionStore) DeleteTransaction(ctx context.Context, transactionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(ctx, transactionID)
+	if i == -1 {
+		return notFound(ErrorTransactionNotFound)
+	}
+
+	s.transactionList.Transactions = append(s.transactionList.Transactions[:i], s.transactionList.Transactions[i+1:]...)
+
+	return nil
+}
+
+func (s *InMemoryTransactionStore) TransactionIDExists(ctx context.Context, transactionID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.indexOf(ctx, transactionID) != -1, nil
+}
+
+// ReassignTransactions moves every transaction in one category to another,
+// dropping their answers as those responded to the old category's questions
+// toCategoryID may be "" to uncategorise the transactions
+func (s *InMemoryTransactionStore) ReassignTransactions(ctx context.Context, fromCategoryID, toCategoryID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner := UserFromContext(ctx)
+
+	for i, t := range s.transactionList.Transactions {
+		if t.CategoryID == fromCategoryID && t.Owner == owner {
+			s.transactionList.Transactions[i].CategoryID = toCategoryID
+			s.transactionList.Transactions[i].Answers = nil
+		}
+	}
+
+	return nil
+}
+
+func (s *InMemoryTransactionStore) DeleteAnswersForQuestion(ctx context.Context, questionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner := UserFromContext(ctx)
+
+	for i, t := range s.transactionList.Transactions {
+		if t.Owner != owner {
+			continue
+		}
+
+		var answers []Answer
+		for _, a := range t.Answers {
+			if a.QuestionID != questionID {
+				answers = append(answers, a)
+			}
+		}
+		if len(answers) != len(t.Answers) {
+			s.transactionList.Transactions[i].Answers = answers
+		}
+	}
+
+	return nil
+}
+
+// DeleteAnswersForOption unchooses the option from the answers choosing it,
+// removing those left choosing nothing, and leaving other answers to the question
+func (s *InMemoryTransactionStore) DeleteAnswersForOption(ctx context.Context, questionID, optionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owner := UserFromContext(ctx)
+
+	for i, t := range s.transactionList.Transactions {
+		if t.Owner != owner {
+			continue
+		}
+
+		var answers []Answer
+		changed := false
+		for _, a := range t.Answers {
+			if a.QuestionID != questionID || !isOptionChosen(a.Value, optionID) {
+				answers = append(answers, a)
+				continue
+			}
+
+			changed = true
+			if value, keep := unchooseOption(a.Value, optionID); keep {
+				answers = append(answers, Answer{QuestionID: a.QuestionID, Value: value})
+			}
+		}
+		if changed {
+			s.transactionList.Transactions[i].Answers = answers
+		}
+	}
+
+	return nil
+}
+
+func (s *InMemoryTransactionStore) CountTransactionsForCategory(ctx context.Context, categoryID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	owner := UserFromContext(ctx)
+	count := 0
+
+	for _, t := range s.transactionList.Transactions {
+		if t.CategoryID == categoryID && t.Owner == owner {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *InMemoryTransactionStore) CountTransactionsAnsweringQuestion(ctx context.Context, questionID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	owner := UserFromContext(ctx)
+	count := 0
+
+	for _, t := range s.transactionList.Transactions {
+		if t.Owner != owner {
+			continue
+		}
+
+		for _, a := range t.Answers {
+			if a.QuestionID == questionID {
+				count++
+				break
+			}
+		}
+	}
+
+	return count, nil
+}
+
+func (s *InMemoryTransactionStore) CountTransactionsAnsweringOption(ctx context.Context, questionID, optionID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	owner := UserFromContext(ctx)
+	count := 0
+
+	for _, t := range s.transactionList.Transactions {
+		if t.Owner != owner {
+			continue
+		}
+
+		for _, a := range t.Answers {
+			if a.QuestionID == questionID && isOptionChosen(a.Value, optionID) {
+				count++
+				break
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// indexOf returns the index of the user's transaction, or -1 if they have no such transaction
+// It expects the caller to hold the lock
+func (s *InMemoryTransactionStore) indexOf(ctx context.Context, transactionID string) int {
+	owner := UserFromContext(ctx)
+
+	for i, t := range s.transactionList.Transactions {
+		if t.ID == transactionID && t.Owner == owner {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// listAll returns every user's transactions, for snapshotting the whole store
+func (s *InMemoryTransactionStore) listAll() TransactionList {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return TransactionList{
+		Transactions: append([]Transaction(nil), s.transactionList.Transactions...),
+	}
+}
+
+// restore replaces every user's transactions with a snapshot taken by listAll
+func (s *InMemoryTransactionStore) restore(transactionList TransactionList) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transactionList = transactionList
+}
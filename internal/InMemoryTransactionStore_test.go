@@ -0,0 +1,15 @@
+package internal
+
+import "testing"
+
+func TestNewInMemoryTransactionStore(t *testing.T) {
+	got := NewInMemoryTransactionStore(nil)
+	want := &InMemoryTransactionStore{}
+	assertDeepEqual(t, got, want)
+}
+
+func TestInMemoryTransactionStore(t *testing.T) {
+	testTransactionStore(t, func() (CategoryStore, QuestionStore, TransactionStore) {
+		return NewInMemoryCategoryStore(nil), NewInMemoryQuestionStore(nil), NewInMemoryTransactionStore(nil)
+	})
+}
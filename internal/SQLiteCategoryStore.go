@@ -0,0 +1,260 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rs/xid"
+)
+
+// SQLiteCategoryStore is a CategoryStore backed by the categories table
+// a category's ParentID is a foreign key, "" is stored as NULL for top-level categories
+type SQLiteCategoryStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteCategoryStore returns a SQLiteCategoryStore pointer
+// db is expected to have come from NewSQLiteDB so it is already migrated
+func NewSQLiteCategoryStore(db *sql.DB) *SQLiteCategoryStore {
+	return &SQLiteCategoryStore{db}
+}
+
+func (s *SQLiteCategoryStore) ListCategories(ctx context.Context) (CategoryList, error) {
+	categories, err := s.queryCategories(ctx, `SELECT id, name, COALESCE(parent_id, ''), owner FROM categories WHERE owner = ? ORDER BY rowid`,
+		UserFromContext(ctx))
+	if err != nil {
+		return CategoryList{}, err
+	}
+
+	return CategoryList{Categories: categories}, nil
+}
+
+func (s *SQLiteCategoryStore) GetCategory(ctx context.Context, id string) (Category, error) {
+	var category Category
+
+	row := s.db.QueryRowContext(ctx, `SELECT id, name, COALESCE(parent_id, ''), owner FROM categories WHERE id = ? AND owner = ?`,
+		id, UserFromContext(ctx))
+	err := row.Scan(&category.ID, &category.Name, &category.ParentID, &category.Owner)
+	if err == sql.ErrNoRows {
+		return Category{}, notFound(ErrorCategoryNotFound)
+	}
+	if err != nil {
+		return Category{}, err
+	}
+
+	return category, nil
+}
+
+func (s *SQLiteCategoryStore) GetChildCategories(ctx context.Context, id string) ([]Category, error) {
+	children, err := s.queryCategories(ctx, `SELECT id, name, parent_id, owner FROM categories WHERE parent_id = ? AND owner = ? ORDER BY rowid`,
+		id, UserFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if children == nil {
+		children = []Category{}
+	}
+	return children, nil
+}
+
+// GetDescendantCategories returns every category beneath id,
+// ordered so that parents always come before their children
+// children always share their parent's owner, so only the first level is filtered
+// a loop of ParentIDs is only followed until each category in it has been returned once
+func (s *SQLiteCategoryStore) GetDescendantCategories(ctx context.Context, id string) ([]Category, error) {
+	descendants, err := s.queryCategories(ctx, `
+		WITH RECURSIVE descendants (id, depth) AS (
+			SELECT id, 1 FROM categories WHERE parent_id = ? AND owner = ?
+			UNION ALL
+			SELECT c.id, d.depth + 1 FROM categories c JOIN descendants d ON c.parent_id = d.id
+			WHERE d.depth < (SELECT COUNT(*) FROM categories)
+		)
+		SELECT c.id, c.name, c.parent_id, c.owner FROM descendants d JOIN categories c ON c.id = d.id
+		WHERE c.id != ?
+		GROUP BY c.id
+		ORDER BY MIN(d.depth), c.rowid`, id, UserFromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if descendants == nil {
+		descendants = []Category{}
+	}
+	return descendants, nil
+}
+
+// AddCategory checks the parent exists & the name is free in the same transaction as the insert,
+// so concurrent adds can't both pass the checks
+func (s *SQLiteCategoryStore) AddCategory(ctx context.Context, categoryName, parentID string) (Category, error) {
+	newCat := Category{
+		ID:       xid.New().String(),
+		Name:     categoryName,
+		ParentID: parentID,
+		Owner:    UserFromContext(ctx),
+	}
+
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		if parentID != "" {
+			parentExists, err := exists(ctx, tx, `SELECT EXISTS (SELECT 1 FROM categories WHERE id = ? AND owner = ?)`, parentID, newCat.Owner)
+			if err != nil {
+				return err
+			}
+			if !parentExists {
+				return notFound(ErrorParentIDNotFound)
+			}
+		}
+
+		if err := s.ensureNameFree(ctx, tx, categoryName, ""); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `INSERT INTO categories (id, name, parent_id, owner) VALUES (?, ?, ?, ?)`,
+			newCat.ID, newCat.Name, nullString(newCat.ParentID), newCat.Owner)
+		return err
+	})
+	if err != nil {
+		return Category{}, err
+	}
+
+	return newCat, nil
+}
+
+func (s *SQLiteCategoryStore) RenameCategory(ctx context.Context, id, name string) (Category, error) {
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.ensureNameFree(ctx, tx, name, id); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `UPDATE categories SET name = ? WHERE id = ? AND owner = ?`, name, id, UserFromContext(ctx))
+		if err != nil {
+			return err
+		}
+		return ensureRowAffected(result, ErrorCategoryNotFound)
+	})
+	if err != nil {
+		return Category{}, err
+	}
+
+	return s.GetCategory(ctx, id)
+}
+
+// MoveCategory checks the parent exists in the same transaction as the update
+func (s *SQLiteCategoryStore) MoveCategory(ctx context.Context, id, parentID string) (Category, error) {
+	owner := UserFromContext(ctx)
+
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		if parentID != "" {
+			parentExists, err := exists(ctx, tx, `SELECT EXISTS (SELECT 1 FROM categories WHERE id = ? AND owner = ?)`, parentID, owner)
+			if err != nil {
+				return err
+			}
+			if !parentExists {
+				return notFound(ErrorParentIDNotFound)
+			}
+		}
+
+		result, err := tx.ExecContext(ctx, `UPDATE categories SET parent_id = ? WHERE id = ? AND owner = ?`, nullString(parentID), id, owner)
+		if err != nil {
+			return err
+		}
+		return ensureRowAffected(result, ErrorCategoryNotFound)
+	})
+	if err != nil {
+		return Category{}, err
+	}
+
+	return s.GetCategory(ctx, id)
+}
+
+func (s *SQLiteCategoryStore) DeleteCategory(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM categories WHERE id = ? AND owner = ?`, id, UserFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	return ensureRowAffected(result, ErrorCategoryNotFound)
+}
+
+// DeleteCategories deletes the categories in the order given, so children can go before their parents
+func (s *SQLiteCategoryStore) DeleteCategories(ctx context.Context, ids []string) error {
+	owner := UserFromContext(ctx)
+
+	return inTx(ctx, s.db, func(tx *sql.Tx) error {
+		for _, id := range ids {
+			result, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE id = ? AND owner = ?`, id, owner)
+			if err != nil {
+				return err
+			}
+			if err := ensureRowAffected(result, ErrorCategoryNotFound); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLiteCategoryStore) CategoryIDExists(ctx context.Context, categoryID string) (bool, error) {
+	return exists(ctx, s.db, `SELECT EXISTS (SELECT 1 FROM categories WHERE id = ? AND owner = ?)`, categoryID, UserFromContext(ctx))
+}
+
+func (s *SQLiteCategoryStore) CategoryNameExists(ctx context.Context, categoryName string) (bool, error) {
+	return exists(ctx, s.db, `SELECT EXISTS (SELECT 1 FROM categories WHERE name = ? AND owner = ?)`, categoryName, UserFromContext(ctx))
+}
+
+// GetCategoryDepth follows the category's parent_ids up to the top level
+// no chain can be longer than there are categories, so one that is must loop
+func (s *SQLiteCategoryStore) GetCategoryDepth(ctx context.Context, categoryID string) (int, error) {
+	var depth, count int
+
+	row := s.db.QueryRowContext(ctx, `
+		WITH RECURSIVE ancestors (parent_id, depth) AS (
+			SELECT parent_id, 0 FROM categories WHERE id = ? AND owner = ?
+			UNION ALL
+			SELECT c.parent_id, a.depth + 1 FROM categories c JOIN ancestors a ON c.id = a.parent_id
+			WHERE a.depth <= (SELECT COUNT(*) FROM categories)
+		)
+		SELECT COALESCE(MAX(depth), 0), (SELECT COUNT(*) FROM categories) FROM ancestors`,
+		categoryID, UserFromContext(ctx))
+	if err := row.Scan(&depth, &count); err != nil {
+		return 0, err
+	}
+
+	if depth > count {
+		return 0, conflict(ErrorCategoryCycle)
+	}
+	return depth, nil
+}
+
+// ensureNameFree returns a conflict if the user has a category named categoryName, other than exceptID
+func (s *SQLiteCategoryStore) ensureNameFree(ctx context.Context, tx *sql.Tx, categoryName, exceptID string) error {
+	taken, err := exists(ctx, tx, `SELECT EXISTS (SELECT 1 FROM categories WHERE name = ? AND owner = ? AND id != ?)`,
+		categoryName, UserFromContext(ctx), exceptID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return conflict(ErrorDuplicateCategoryName)
+	}
+	return nil
+}
+
+func (s *SQLiteCategoryStore) queryCategories(ctx context.Context, query string, args ...interface{}) ([]Category, error) {
+	var categories []Category
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c Category
+		if err := rows.Scan(&c.ID, &c.Name, &c.ParentID, &c.Owner); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+
+	return categories, rows.Err()
+}
@@ -0,0 +1,30 @@
+package internal
+
+import "testing"
+
+func TestSQLiteCategoryStore(t *testing.T) {
+	testCategoryStore(t, func() CategoryStore {
+		return NewSQLiteCategoryStore(newTestSQLiteDB(t))
+	})
+}
+
+func TestSQLiteCategoryStore_Cycle(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	store := NewSQLiteCategoryStore(db)
+
+	accommodation := addCategory(t, ctx, store, "accommodation", "")
+	hostel := addCategory(t, ctx, store, "hostel", accommodation.ID)
+	dorm := addCategory(t, ctx, store, "dorm", hostel.ID)
+
+	// can't be made through the store, only by changing the table directly
+	_, err := db.Exec(`UPDATE categories SET parent_id = ? WHERE id = ?`, hostel.ID, accommodation.ID)
+	assertNoError(t, err)
+
+	_, err = store.GetCategoryDepth(ctx, dorm.ID)
+	assertErrorIs(t, err, ErrConflict)
+
+	descendants, err := store.GetDescendantCategories(ctx, accommodation.ID)
+	assertNoError(t, err)
+	hostel.ParentID = accommodation.ID
+	assertDeepEqual(t, descendants, []Category{hostel, dorm})
+}
@@ -0,0 +1,498 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/rs/xid"
+)
+
+// SQLiteQuestionStore is a QuestionStore backed by the questions, options & hidden_questions tables
+// a question's CategoryID is a foreign key, so its category must already exist,
+// as must the categories it's hidden in, and its Constraints & DependsOn are stored as JSON
+type SQLiteQuestionStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteQuestionStore returns a SQLiteQuestionStore pointer
+// db is expected to have come from NewSQLiteDB so it is already migrated
+func NewSQLiteQuestionStore(db *sql.DB) *SQLiteQuestionStore {
+	return &SQLiteQuestionStore{db}
+}
+
+func (s *SQLiteQuestionStore) ListQuestions(ctx context.Context) (QuestionList, error) {
+	return s.queryQuestions(ctx, `SELECT id, title, category_id, type, constraints, depends_on, overrides, owner FROM questions WHERE owner = ? ORDER BY rowid`,
+		UserFromContext(ctx))
+}
+
+func (s *SQLiteQuestionStore) ListQuestionsForCategory(ctx context.Context, categoryID string) (QuestionList, error) {
+	return s.queryQuestions(ctx, `SELECT id, title, category_id, type, constraints, depends_on, overrides, owner FROM questions WHERE category_id = ? AND owner = ? ORDER BY rowid`,
+		categoryID, UserFromContext(ctx))
+}
+
+func (s *SQLiteQuestionStore) GetQuestion(ctx context.Context, questionID string) (Question, error) {
+	questionList, err := s.queryQuestions(ctx, `SELECT id, title, category_id, type, constraints, depends_on, overrides, owner FROM questions WHERE id = ? AND owner = ?`,
+		questionID, UserFromContext(ctx))
+	if err != nil {
+		return Question{}, err
+	}
+	if len(questionList.Questions) == 0 {
+		return Question{}, notFound(ErrorQuestionNotFound)
+	}
+	return questionList.Questions[0], nil
+}
+
+func (s *SQLiteQuestionStore) AddQuestion(ctx context.Context, categoryID string, q QuestionPostRequest) (Question, error) {
+	question := Question{
+		ID:          xid.New().String(),
+		Title:       q.Title,
+		CategoryID:  categoryID,
+		Type:        q.Type,
+		Constraints: q.Constraints,
+		DependsOn:   q.DependsOn,
+		Overrides:   q.Overrides,
+		Owner:       UserFromContext(ctx),
+	}
+
+	if typeHasOptions(q.Type) {
+		question.Options = newOptionList(q.Options)
+	}
+
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.ensureTitleFree(ctx, tx, categoryID, question.Title, ""); err != nil {
+			return err
+		}
+
+		constraints, err := marshalConstraints(question.Constraints)
+		if err != nil {
+			return err
+		}
+
+		dependsOn, err := marshalDependency(question.DependsOn)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO questions (id, title, category_id, type, constraints, depends_on, overrides, owner) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			question.ID, question.Title, question.CategoryID, question.Type, constraints, dependsOn, question.Overrides, question.Owner)
+		if err != nil {
+			return err
+		}
+
+		for _, o := range question.Options {
+			_, err = tx.ExecContext(ctx, `INSERT INTO options (id, question_id, title) VALUES (?, ?, ?)`, o.ID, question.ID, o.Title)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Question{}, err
+	}
+
+	return question, nil
+}
+
+func (s *SQLiteQuestionStore) RenameQuestion(ctx context.Context, questionID, questionTitle string) (Question, error) {
+	owner := UserFromContext(ctx)
+
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		var categoryID string
+		err := tx.QueryRowContext(ctx, `SELECT category_id FROM questions WHERE id = ? AND owner = ?`, questionID, owner).Scan(&categoryID)
+		if err == sql.ErrNoRows {
+			return notFound(ErrorQuestionNotFound)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.ensureTitleFree(ctx, tx, categoryID, questionTitle, questionID); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE questions SET title = ? WHERE id = ? AND owner = ?`, questionTitle, questionID, owner)
+		return err
+	})
+	if err != nil {
+		return Question{}, err
+	}
+
+	return s.GetQuestion(ctx, questionID)
+}
+
+func (s *SQLiteQuestionStore) SetQuestionConstraints(ctx context.Context, questionID string, constraints *Constraints) (Question, error) {
+	value, err := marshalConstraints(constraints)
+	if err != nil {
+		return Question{}, err
+	}
+
+	result, err := s.db.ExecContext(ctx, `UPDATE questions SET constraints = ? WHERE id = ? AND owner = ?`, value, questionID, UserFromContext(ctx))
+	if err != nil {
+		return Question{}, err
+	}
+	if err := ensureRowAffected(result, ErrorQuestionNotFound); err != nil {
+		return Question{}, err
+	}
+
+	return s.GetQuestion(ctx, questionID)
+}
+
+func (s *SQLiteQuestionStore) SetQuestionDependency(ctx context.Context, questionID string, dependsOn *Dependency) (Question, error) {
+	value, err := marshalDependency(dependsOn)
+	if err != nil {
+		return Question{}, err
+	}
+
+	result, err := s.db.ExecContext(ctx, `UPDATE questions SET depends_on = ? WHERE id = ? AND owner = ?`, value, questionID, UserFromContext(ctx))
+	if err != nil {
+		return Question{}, err
+	}
+	if err := ensureRowAffected(result, ErrorQuestionNotFound); err != nil {
+		return Question{}, err
+	}
+
+	return s.GetQuestion(ctx, questionID)
+}
+
+func (s *SQLiteQuestionStore) UpdateQuestion(ctx context.Context, questionID string, update QuestionPatchRequest) (Question, error) {
+	owner := UserFromContext(ctx)
+
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		var categoryID string
+		err := tx.QueryRowContext(ctx, `SELECT category_id FROM questions WHERE id = ? AND owner = ?`, questionID, owner).Scan(&categoryID)
+		if err == sql.ErrNoRows {
+			return notFound(ErrorQuestionNotFound)
+		}
+		if err != nil {
+			return err
+		}
+
+		if update.Title != nil {
+			if err := s.ensureTitleFree(ctx, tx, categoryID, *update.Title, questionID); err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, `UPDATE questions SET title = ? WHERE id = ? AND owner = ?`, *update.Title, questionID, owner); err != nil {
+				return err
+			}
+		}
+
+		if update.Constraints != nil {
+			value, err := marshalConstraints(withoutEmptyConstraints(update.Constraints))
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, `UPDATE questions SET constraints = ? WHERE id = ? AND owner = ?`, value, questionID, owner); err != nil {
+				return err
+			}
+		}
+
+		if update.DependsOn != nil {
+			value, err := marshalDependency(withoutEmptyDependency(update.DependsOn))
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, `UPDATE questions SET depends_on = ? WHERE id = ? AND owner = ?`, value, questionID, owner); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Question{}, err
+	}
+
+	return s.GetQuestion(ctx, questionID)
+}
+
+func (s *SQLiteQuestionStore) DeleteQuestion(ctx context.Context, questionID string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM questions WHERE id = ? AND owner = ?`, questionID, UserFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	return ensureRowAffected(result, ErrorQuestionNotFound)
+}
+
+func (s *SQLiteQuestionStore) DeleteQuestionsForCategory(ctx context.Context, categoryID string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM questions WHERE category_id = ? AND owner = ?`, categoryID, UserFromContext(ctx))
+	return err
+}
+
+func (s *SQLiteQuestionStore) HideQuestion(ctx context.Context, questionID, categoryID string) (Question, error) {
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.ensureQuestionExists(ctx, tx, questionID); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO hidden_questions (question_id, category_id) VALUES (?, ?)`, questionID, categoryID)
+		return err
+	})
+	if err != nil {
+		return Question{}, err
+	}
+
+	return s.GetQuestion(ctx, questionID)
+}
+
+func (s *SQLiteQuestionStore) UnhideQuestion(ctx context.Context, questionID, categoryID string) (Question, error) {
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.ensureQuestionExists(ctx, tx, questionID); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM hidden_questions WHERE question_id = ? AND category_id = ?`, questionID, categoryID)
+		if err != nil {
+			return err
+		}
+		return ensureRowAffected(result, ErrorQuestionNotHidden)
+	})
+	if err != nil {
+		return Question{}, err
+	}
+
+	return s.GetQuestion(ctx, questionID)
+}
+
+// AddOption checks the question exists & the title is free in the same transaction as the insert
+func (s *SQLiteQuestionStore) AddOption(ctx context.Context, questionID, optionTitle string) (Option, error) {
+	option := Option{
+		ID:    xid.New().String(),
+		Title: optionTitle,
+	}
+
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.ensureQuestionExists(ctx, tx, questionID); err != nil {
+			return err
+		}
+
+		if err := s.ensureOptionTitleFree(ctx, tx, questionID, optionTitle, ""); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `INSERT INTO options (id, question_id, title) VALUES (?, ?, ?)`, option.ID, questionID, option.Title)
+		return err
+	})
+	if err != nil {
+		return Option{}, err
+	}
+
+	return option, nil
+}
+
+func (s *SQLiteQuestionStore) RenameOption(ctx context.Context, questionID, optionID, optionTitle string) (Option, error) {
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.ensureQuestionExists(ctx, tx, questionID); err != nil {
+			return err
+		}
+
+		if err := s.ensureOptionTitleFree(ctx, tx, questionID, optionTitle, optionID); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `UPDATE options SET title = ? WHERE id = ? AND question_id = ?`, optionTitle, optionID, questionID)
+		if err != nil {
+			return err
+		}
+		return ensureRowAffected(result, ErrorOptionNotFound)
+	})
+	if err != nil {
+		return Option{}, err
+	}
+
+	return Option{ID: optionID, Title: optionTitle}, nil
+}
+
+func (s *SQLiteQuestionStore) DeleteOption(ctx context.Context, questionID, optionID string) error {
+	return inTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := s.ensureQuestionExists(ctx, tx, questionID); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM options WHERE id = ? AND question_id = ?`, optionID, questionID)
+		if err != nil {
+			return err
+		}
+		return ensureRowAffected(result, ErrorOptionNotFound)
+	})
+}
+
+func (s *SQLiteQuestionStore) QuestionIDExists(ctx context.Context, questionID string) (bool, error) {
+	return exists(ctx, s.db, `SELECT EXISTS (SELECT 1 FROM questions WHERE id = ? AND owner = ?)`, questionID, UserFromContext(ctx))
+}
+
+func (s *SQLiteQuestionStore) QuestionTitleExists(ctx context.Context, categoryID, questionTitle string) (bool, error) {
+	return exists(ctx, s.db, `SELECT EXISTS (SELECT 1 FROM questions WHERE category_id = ? AND title = ? AND owner = ?)`,
+		categoryID, questionTitle, UserFromContext(ctx))
+}
+
+func (s *SQLiteQuestionStore) QuestionBelongsToCategory(ctx context.Context, questionID, categoryID string) (bool, error) {
+	// matches InMemoryQuestionStore, a question that doesn't exist isn't in another category
+	inAnother, err := exists(ctx, s.db, `SELECT EXISTS (SELECT 1 FROM questions WHERE id = ? AND owner = ? AND category_id != ?)`,
+		questionID, UserFromContext(ctx), categoryID)
+	return !inAnother, err
+}
+
+// ensureTitleFree returns a conflict if the user has a question titled questionTitle in the category, other than exceptID
+func (s *SQLiteQuestionStore) ensureTitleFree(ctx context.Context, tx *sql.Tx, categoryID, questionTitle, exceptID string) error {
+	taken, err := exists(ctx, tx, `SELECT EXISTS (SELECT 1 FROM questions WHERE category_id = ? AND title = ? AND owner = ? AND id != ?)`,
+		categoryID, questionTitle, UserFromContext(ctx), exceptID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return conflict(ErrorDuplicateTitle)
+	}
+	return nil
+}
+
+// ensureQuestionExists returns not found unless the user has the question,
+// options belong to the question so only it needs checking
+func (s *SQLiteQuestionStore) ensureQuestionExists(ctx context.Context, tx *sql.Tx, questionID string) error {
+	questionExists, err := exists(ctx, tx, `SELECT EXISTS (SELECT 1 FROM questions WHERE id = ? AND owner = ?)`, questionID, UserFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if !questionExists {
+		return notFound(ErrorQuestionNotFound)
+	}
+	return nil
+}
+
+// ensureOptionTitleFree returns a conflict if the question has an option titled optionTitle, other than exceptID
+func (s *SQLiteQuestionStore) ensureOptionTitleFree(ctx context.Context, tx *sql.Tx, questionID, optionTitle, exceptID string) error {
+	taken, err := exists(ctx, tx, `SELECT EXISTS (SELECT 1 FROM options WHERE question_id = ? AND title = ? AND id != ?)`,
+		questionID, optionTitle, exceptID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return conflict(ErrorDuplicateOption)
+	}
+	return nil
+}
+
+func (s *SQLiteQuestionStore) queryQuestions(ctx context.Context, query string, args ...interface{}) (QuestionList, error) {
+	var questionList QuestionList
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return QuestionList{}, err
+	}
+
+	for rows.Next() {
+		var q Question
+		var constraints, dependsOn sql.NullString
+		if err := rows.Scan(&q.ID, &q.Title, &q.CategoryID, &q.Type, &constraints, &dependsOn, &q.Overrides, &q.Owner); err != nil {
+			rows.Close()
+			return QuestionList{}, err
+		}
+		if constraints.Valid {
+			if err := json.Unmarshal([]byte(constraints.String), &q.Constraints); err != nil {
+				rows.Close()
+				return QuestionList{}, err
+			}
+		}
+		if dependsOn.Valid {
+			if err := json.Unmarshal([]byte(dependsOn.String), &q.DependsOn); err != nil {
+				rows.Close()
+				return QuestionList{}, err
+			}
+		}
+		questionList.Questions = append(questionList.Questions, q)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return QuestionList{}, err
+	}
+	// the single connection must be released before options & hidden categories are queried
+	rows.Close()
+
+	for i, q := range questionList.Questions {
+		if typeHasOptions(q.Type) {
+			questionList.Questions[i].Options, err = s.listOptions(ctx, q.ID)
+			if err != nil {
+				return QuestionList{}, err
+			}
+		}
+
+		questionList.Questions[i].HiddenIn, err = s.listHiddenIn(ctx, q.ID)
+		if err != nil {
+			return QuestionList{}, err
+		}
+	}
+
+	return questionList, nil
+}
+
+// marshalConstraints returns constraints as the JSON they're stored as, NULL if there are none
+func marshalConstraints(constraints *Constraints) (sql.NullString, error) {
+	if constraints == nil {
+		return sql.NullString{}, nil
+	}
+
+	value, err := json.Marshal(constraints)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(value), Valid: true}, nil
+}
+
+// marshalDependency returns a dependency as the JSON it's stored as, NULL if there isn't one
+func marshalDependency(dependsOn *Dependency) (sql.NullString, error) {
+	if dependsOn == nil {
+		return sql.NullString{}, nil
+	}
+
+	value, err := json.Marshal(dependsOn)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(value), Valid: true}, nil
+}
+
+func (s *SQLiteQuestionStore) listOptions(ctx context.Context, questionID string) (OptionList, error) {
+	options := OptionList{}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, title FROM options WHERE question_id = ? ORDER BY rowid`, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var o Option
+		if err := rows.Scan(&o.ID, &o.Title); err != nil {
+			return nil, err
+		}
+		options = append(options, o)
+	}
+
+	return options, rows.Err()
+}
+
+// listHiddenIn returns the categories the question is hidden in, nil if it's hidden in none
+func (s *SQLiteQuestionStore) listHiddenIn(ctx context.Context, questionID string) ([]string, error) {
+	var categoryIDs []string
+
+	rows, err := s.db.QueryContext(ctx, `SELECT category_id FROM hidden_questions WHERE question_id = ? ORDER BY rowid`, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var categoryID string
+		if err := rows.Scan(&categoryID); err != nil {
+			return nil, err
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+
+	return categoryIDs, rows.Err()
+}
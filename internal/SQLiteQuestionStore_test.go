@@ -0,0 +1,10 @@
+package internal
+
+import "testing"
+
+func TestSQLiteQuestionStore(t *testing.T) {
+	testQuestionStore(t, func() (CategoryStore, QuestionStore) {
+		db := newTestSQLiteDB(t)
+		return NewSQLiteCategoryStore(db), NewSQLiteQuestionStore(db)
+	})
+}
@@ -0,0 +1,350 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// SQLiteTransactionStore is a TransactionStore backed by the transactions & answers tables
+// Timestamps are stored as RFC3339 text in UTC, and answer values as JSON
+type SQLiteTransactionStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteTransactionStore returns a SQLiteTransactionStore pointer
+// db is expected to have come from NewSQLiteDB so it is already migrated
+func NewSQLiteTransactionStore(db *sql.DB) *SQLiteTransactionStore {
+	return &SQLiteTransactionStore{db}
+}
+
+const transactionColumns = `id, COALESCE(monzo_id, ''), amount, currency, merchant, timestamp, COALESCE(category_id, ''), owner`
+
+func (s *SQLiteTransactionStore) ListTransactions(ctx context.Context) (TransactionList, error) {
+	return s.queryTransactions(ctx, `SELECT `+transactionColumns+` FROM transactions WHERE owner = ? ORDER BY rowid`, UserFromContext(ctx))
+}
+
+func (s *SQLiteTransactionStore) GetTransaction(ctx context.Context, transactionID string) (Transaction, error) {
+	transactionList, err := s.queryTransactions(ctx, `SELECT `+transactionColumns+` FROM transactions WHERE id = ? AND owner = ?`,
+		transactionID, UserFromContext(ctx))
+	if err != nil {
+		return Transaction{}, err
+	}
+	if len(transactionList.Transactions) == 0 {
+		return Transaction{}, notFound(ErrorTransactionNotFound)
+	}
+	return transactionList.Transactions[0], nil
+}
+
+func (s *SQLiteTransactionStore) AddTransaction(ctx context.Context, transaction Transaction) (Transaction, error) {
+	transaction.Owner = UserFromContext(ctx)
+	transaction, _, err := s.insertTransaction(ctx, `INSERT`, transaction)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return transaction, nil
+}
+
+// ImportTransaction adds the transaction unless the user has already imported one with the same MonzoID,
+// in which case that one is returned along with an error wrapping ErrConflict
+// the unique index on owner & monzo_id makes this safe against concurrent imports
+func (s *SQLiteTransactionStore) ImportTransaction(ctx context.Context, transaction Transaction) (Transaction, error) {
+	transaction.Owner = UserFromContext(ctx)
+	transaction, added, err := s.insertTransaction(ctx, `INSERT OR IGNORE`, transaction)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	if !added {
+		transactionList, err := s.queryTransactions(ctx, `SELECT `+transactionColumns+` FROM transactions WHERE monzo_id = ? AND owner = ?`,
+			transaction.MonzoID, transaction.Owner)
+		if err != nil {
+			return Transaction{}, err
+		}
+		return transactionList.Transactions[0], conflict(ErrorDuplicateMonzoID)
+	}
+
+	return transaction, nil
+}
+
+func (s *SQLiteTransactionStore) insertTransaction(ctx context.Context, insert string, transaction Transaction) (Transaction, bool, error) {
+	transaction.ID = xid.New().String()
+
+	// rolls back the ignored insert, and stops there
+	errIgnored := errors.New("insert ignored")
+
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, insert+` INTO transactions (id, monzo_id, amount, currency, merchant, timestamp, category_id, owner) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			transaction.ID, nullString(transaction.MonzoID), transaction.Amount, transaction.Currency, transaction.Merchant,
+			transaction.Timestamp.UTC().Format(time.RFC3339Nano), nullString(transaction.CategoryID), transaction.Owner)
+		if err != nil {
+			return err
+		}
+
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if inserted == 0 {
+			return errIgnored
+		}
+
+		return insertAnswers(ctx, tx, transaction.ID, transaction.Answers)
+	})
+	if err == errIgnored {
+		return transaction, false, nil
+	}
+	if err != nil {
+		return Transaction{}, false, err
+	}
+
+	return transaction, true, nil
+}
+
+func (s *SQLiteTransactionStore) CategoriseTransaction(ctx context.Context, transactionID, categoryID string, answers []Answer) (Transaction, error) {
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE transactions SET category_id = ? WHERE id = ? AND owner = ?`,
+			nullString(categoryID), transactionID, UserFromContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		if err := ensureRowAffected(result, ErrorTransactionNotFound); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM answers WHERE transaction_id = ?`, transactionID)
+		if err != nil {
+			return err
+		}
+
+		return insertAnswers(ctx, tx, transactionID, answers)
+	})
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	return s.GetTransaction(ctx, transactionID)
+}
+
+func (s *SQLiteTransactionStore) DeleteTransaction(ctx context.Context, transactionID string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM transactions WHERE id = ? AND owner = ?`, transactionID, UserFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	return ensureRowAffected(result, ErrorTransactionNotFound)
+}
+
+// ReassignTransactions moves every transaction in one category to another,
+// dropping their answers as those responded to the old category's questions
+// toCategoryID may be "" to uncategorise the transactions
+func (s *SQLiteTransactionStore) ReassignTransactions(ctx context.Context, fromCategoryID, toCategoryID string) error {
+	owner := UserFromContext(ctx)
+
+	return inTx(ctx, s.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `DELETE FROM answers WHERE transaction_id IN (SELECT id FROM transactions WHERE category_id = ? AND owner = ?)`,
+			fromCategoryID, owner)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE transactions SET category_id = ? WHERE category_id = ? AND owner = ?`,
+			nullString(toCategoryID), fromCategoryID, owner)
+		return err
+	})
+}
+
+func (s *SQLiteTransactionStore) DeleteAnswersForQuestion(ctx context.Context, questionID string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM answers WHERE question_id = ? AND transaction_id IN (SELECT id FROM transactions WHERE owner = ?)`,
+		questionID, UserFromContext(ctx))
+	return err
+}
+
+// DeleteAnswersForOption unchooses the option from the answers choosing it, removing those left choosing nothing
+// Values are JSON, so are decoded & rewritten here rather than matched in SQL
+func (s *SQLiteTransactionStore) DeleteAnswersForOption(ctx context.Context, questionID, optionID string) error {
+	return inTx(ctx, s.db, func(tx *sql.Tx) error {
+		answers, err := queryAnswersToQuestion(ctx, tx, questionID, UserFromContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		for _, a := range answers {
+			if !isOptionChosen(a.value, optionID) {
+				continue
+			}
+
+			remaining, keep := unchooseOption(a.value, optionID)
+			if !keep {
+				if _, err := tx.ExecContext(ctx, `DELETE FROM answers WHERE rowid = ?`, a.rowID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			value, err := json.Marshal(remaining)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, `UPDATE answers SET value = ? WHERE rowid = ?`, string(value), a.rowID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLiteTransactionStore) TransactionIDExists(ctx context.Context, transactionID string) (bool, error) {
+	return exists(ctx, s.db, `SELECT EXISTS (SELECT 1 FROM transactions WHERE id = ? AND owner = ?)`, transactionID, UserFromContext(ctx))
+}
+
+func (s *SQLiteTransactionStore) CountTransactionsForCategory(ctx context.Context, categoryID string) (int, error) {
+	return s.count(ctx, `SELECT COUNT(*) FROM transactions WHERE category_id = ? AND owner = ?`, categoryID, UserFromContext(ctx))
+}
+
+func (s *SQLiteTransactionStore) CountTransactionsAnsweringQuestion(ctx context.Context, questionID string) (int, error) {
+	return s.count(ctx, `SELECT COUNT(*) FROM answers a JOIN transactions t ON t.id = a.transaction_id WHERE a.question_id = ? AND t.owner = ?`,
+		questionID, UserFromContext(ctx))
+}
+
+func (s *SQLiteTransactionStore) CountTransactionsAnsweringOption(ctx context.Context, questionID, optionID string) (int, error) {
+	answers, err := queryAnswersToQuestion(ctx, s.db, questionID, UserFromContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	// a transaction answers each question at most once
+	count := 0
+	for _, a := range answers {
+		if isOptionChosen(a.value, optionID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *SQLiteTransactionStore) count(ctx context.Context, query string, args ...interface{}) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+func (s *SQLiteTransactionStore) queryTransactions(ctx context.Context, query string, args ...interface{}) (TransactionList, error) {
+	var transactionList TransactionList
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return TransactionList{}, err
+	}
+
+	for rows.Next() {
+		var t Transaction
+		var timestamp string
+		if err := rows.Scan(&t.ID, &t.MonzoID, &t.Amount, &t.Currency, &t.Merchant, &timestamp, &t.CategoryID, &t.Owner); err != nil {
+			rows.Close()
+			return TransactionList{}, err
+		}
+		t.Timestamp, err = time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			rows.Close()
+			return TransactionList{}, err
+		}
+		transactionList.Transactions = append(transactionList.Transactions, t)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return TransactionList{}, err
+	}
+	// the single connection must be released before answers are queried
+	rows.Close()
+
+	for i, t := range transactionList.Transactions {
+		transactionList.Transactions[i].Answers, err = s.listAnswers(ctx, t.ID)
+		if err != nil {
+			return TransactionList{}, err
+		}
+	}
+
+	return transactionList, nil
+}
+
+func (s *SQLiteTransactionStore) listAnswers(ctx context.Context, transactionID string) ([]Answer, error) {
+	var answers []Answer
+
+	rows, err := s.db.QueryContext(ctx, `SELECT question_id, value FROM answers WHERE transaction_id = ? ORDER BY rowid`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a Answer
+		var value string
+		if err := rows.Scan(&a.QuestionID, &value); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(value), &a.Value); err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+
+	return answers, rows.Err()
+}
+
+// storedAnswer is the decoded value of an answer, along with the row holding it
+type storedAnswer struct {
+	rowID int64
+	value interface{}
+}
+
+// rowsQueryer is satisfied by both *sql.DB & *sql.Tx
+type rowsQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// queryAnswersToQuestion returns the owner's answers to the question
+func queryAnswersToQuestion(ctx context.Context, q rowsQueryer, questionID, owner string) ([]storedAnswer, error) {
+	var answers []storedAnswer
+
+	rows, err := q.QueryContext(ctx, `SELECT a.rowid, a.value FROM answers a JOIN transactions t ON t.id = a.transaction_id WHERE a.question_id = ? AND t.owner = ?`,
+		questionID, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a storedAnswer
+		var value string
+		if err := rows.Scan(&a.rowID, &value); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(value), &a.value); err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+
+	return answers, rows.Err()
+}
+
+func insertAnswers(ctx context.Context, tx *sql.Tx, transactionID string, answers []Answer) error {
+	for _, a := range answers {
+		value, err := json.Marshal(a.Value)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO answers (transaction_id, question_id, value) VALUES (?, ?, ?)`, transactionID, a.QuestionID, string(value))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
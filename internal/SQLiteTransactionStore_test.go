@@ -0,0 +1,10 @@
+package internal
+
+import "testing"
+
+func TestSQLiteTransactionStore(t *testing.T) {
+	testTransactionStore(t, func() (CategoryStore, QuestionStore, TransactionStore) {
+		db := newTestSQLiteDB(t)
+		return NewSQLiteCategoryStore(db), NewSQLiteQuestionStore(db), NewSQLiteTransactionStore(db)
+	})
+}
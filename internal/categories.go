@@ -1,21 +1,34 @@
 package internal
 
-import "regexp"
+import (
+	"context"
+	"regexp"
+)
 
 // CategoryStore is an interface that when implemented,
 // provides methods for manipulating a store of categories,
 // including some helper functions for querying the store
+// Every method acts only on the categories owned by the user in ctx (see WithUser)
+// Errors wrap ErrNotFound when the category (or parent) doesn't exist,
+// and ErrConflict when the user already has a category with the name
+// DeleteCategories deletes every one of the categories or, if any doesn't exist, none of them
+// MoveCategory doesn't check the move leaves ParentIDs looping, see Service.MoveCategory
+// GetCategoryDepth is how many ancestors a category has (0 for a top level or unknown category),
+// and wraps ErrConflict if following its ParentIDs loops back on itself
 type CategoryStore interface {
-	ListCategories() CategoryList
-	GetCategory(categoryID string) Category
-	GetChildCategories(categoryID string) []Category
-	AddCategory(categoryName, parentID string) Category
-	RenameCategory(categoryID, categoryName string) Category
-	DeleteCategory(categoryID string)
-
-	CategoryIDExists(categoryID string) bool
-	CategoryNameExists(categoryName string) bool
-	GetCategoryDepth(categoryID string) int
+	ListCategories(ctx context.Context) (CategoryList, error)
+	GetCategory(ctx context.Context, categoryID string) (Category, error)
+	GetChildCategories(ctx context.Context, categoryID string) ([]Category, error)
+	GetDescendantCategories(ctx context.Context, categoryID string) ([]Category, error)
+	AddCategory(ctx context.Context, categoryName, parentID string) (Category, error)
+	RenameCategory(ctx context.Context, categoryID, categoryName string) (Category, error)
+	MoveCategory(ctx context.Context, categoryID, parentID string) (Category, error)
+	DeleteCategory(ctx context.Context, categoryID string) error
+	DeleteCategories(ctx context.Context, categoryIDs []string) error
+
+	CategoryIDExists(ctx context.Context, categoryID string) (bool, error)
+	CategoryNameExists(ctx context.Context, categoryName string) (bool, error)
+	GetCategoryDepth(ctx context.Context, categoryID string) (int, error)
 }
 
 // CategoryList stores multiple categories
@@ -25,10 +38,38 @@ type CategoryList struct {
 
 // Category stores all expected category attributes
 // The structure implements the adjacency list pattern
+// Owner is the user the category belongs to, see WithUser
 type Category struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	ParentID string `json:"parentID"`
+	Owner    string `json:"owner,omitempty"`
+}
+
+// CategoryTree is a category with its subcategories, and theirs, nested beneath it
+type CategoryTree struct {
+	Category
+	Children []CategoryTree `json:"children"`
+}
+
+// newCategoryTree nests the descendants beneath root,
+// relying on them being ordered parents first, as GetDescendantCategories returns them
+func newCategoryTree(root Category, descendants []Category) CategoryTree {
+	children := map[string][]Category{}
+	for _, c := range descendants {
+		children[c.ParentID] = append(children[c.ParentID], c)
+	}
+
+	var build func(c Category) CategoryTree
+	build = func(c Category) CategoryTree {
+		tree := CategoryTree{Category: c, Children: []CategoryTree{}}
+		for _, child := range children[c.ID] {
+			tree.Children = append(tree.Children, build(child))
+		}
+		return tree
+	}
+
+	return build(root)
 }
 
 const categoryNameRegex = `^[a-zA-Z]+[a-zA-Z ]+?[a-zA-Z]+$`
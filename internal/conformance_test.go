@@ -0,0 +1,1406 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// concurrency is how many goroutines the "concurrent use" tests run at once
+// run with -race to have the detector check them
+const concurrency = 50
+
+// ctx is the default shared user's context, see WithUser
+var ctx = context.Background()
+
+// alice & bob are separate users, for checking nothing leaks between them
+var (
+	alice = WithUser(ctx, "alice")
+	bob   = WithUser(ctx, "bob")
+)
+
+// addCategory, addQuestion & addTransaction set up the stores for a test,
+// failing it straight away if they can't
+func addCategory(t *testing.T, ctx context.Context, store CategoryStore, categoryName, parentID string) Category {
+	t.Helper()
+	category, err := store.AddCategory(ctx, categoryName, parentID)
+	assertNoError(t, err)
+	return category
+}
+
+func addQuestion(t *testing.T, ctx context.Context, store QuestionStore, categoryID string, question QuestionPostRequest) Question {
+	t.Helper()
+	added, err := store.AddQuestion(ctx, categoryID, question)
+	assertNoError(t, err)
+	return added
+}
+
+func addTransaction(t *testing.T, ctx context.Context, store TransactionStore, transaction Transaction) Transaction {
+	t.Helper()
+	added, err := store.AddTransaction(ctx, transaction)
+	assertNoError(t, err)
+	return added
+}
+
+// testCategoryStore is the conformance suite every CategoryStore backend must pass
+// newStore must return an empty store each time it is called
+func testCategoryStore(t *testing.T, newStore func() CategoryStore) {
+	t.Run("ListCategories", func(t *testing.T) {
+		store := newStore()
+
+		got, err := store.ListCategories(ctx)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(got.Categories), 0)
+
+		accommodation := addCategory(t, ctx, store, "accommodation", "")
+		hostel := addCategory(t, ctx, store, "hostel", accommodation.ID)
+
+		got, err = store.ListCategories(ctx)
+		assertNoError(t, err)
+		want := CategoryList{
+			Categories: []Category{accommodation, hostel},
+		}
+		assertDeepEqual(t, got, want)
+	})
+
+	t.Run("GetChildCategories", func(t *testing.T) {
+		store := newStore()
+
+		accommodation := addCategory(t, ctx, store, "accommodation", "")
+		foo := addCategory(t, ctx, store, "foo", accommodation.ID)
+		bar := addCategory(t, ctx, store, "bar", foo.ID)
+
+		t.Run("has children", func(t *testing.T) {
+			got, err := store.GetChildCategories(ctx, accommodation.ID)
+			assertNoError(t, err)
+			want := []Category{foo}
+			assertDeepEqual(t, got, want)
+		})
+
+		t.Run("no children", func(t *testing.T) {
+			got, err := store.GetChildCategories(ctx, bar.ID)
+			assertNoError(t, err)
+			want := []Category{}
+			assertDeepEqual(t, got, want)
+		})
+	})
+
+	t.Run("GetDescendantCategories", func(t *testing.T) {
+		store := newStore()
+
+		accommodation := addCategory(t, ctx, store, "accommodation", "")
+		hostel := addCategory(t, ctx, store, "hostel", accommodation.ID)
+		hotel := addCategory(t, ctx, store, "hotel", accommodation.ID)
+		dorm := addCategory(t, ctx, store, "dorm", hostel.ID)
+		addCategory(t, ctx, store, "food", "")
+
+		t.Run("has descendants", func(t *testing.T) {
+			got, err := store.GetDescendantCategories(ctx, accommodation.ID)
+			assertNoError(t, err)
+			want := []Category{hostel, hotel, dorm}
+			assertDeepEqual(t, got, want)
+		})
+
+		t.Run("no descendants", func(t *testing.T) {
+			got, err := store.GetDescendantCategories(ctx, dorm.ID)
+			assertNoError(t, err)
+			want := []Category{}
+			assertDeepEqual(t, got, want)
+		})
+	})
+
+	t.Run("GetCategory", func(t *testing.T) {
+		store := newStore()
+
+		category := addCategory(t, ctx, store, "accommodation", "")
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.GetCategory(ctx, "abcd")
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("ID exists", func(t *testing.T) {
+			got, err := store.GetCategory(ctx, category.ID)
+			assertNoError(t, err)
+			want := category
+			assertDeepEqual(t, got, want)
+		})
+	})
+
+	t.Run("AddCategory", func(t *testing.T) {
+		store := newStore()
+
+		parent := addCategory(t, ctx, store, "accommodation", "")
+
+		categoryName := "hostel"
+		parentID := parent.ID
+
+		got, err := store.AddCategory(ctx, categoryName, parentID)
+		assertNoError(t, err)
+
+		// assert response
+		assertIsXid(t, got.ID)
+		assertStringsEqual(t, got.Name, categoryName)
+		assertStringsEqual(t, got.ParentID, parentID)
+
+		// assert store
+		got, err = store.GetCategory(ctx, got.ID)
+		assertNoError(t, err)
+		assertIsXid(t, got.ID)
+		assertStringsEqual(t, got.Name, categoryName)
+		assertStringsEqual(t, got.ParentID, parentID)
+
+		t.Run("name already exists", func(t *testing.T) {
+			_, err := store.AddCategory(ctx, categoryName, "")
+			assertErrorIs(t, err, ErrConflict)
+		})
+
+		t.Run("parent doesn't exist", func(t *testing.T) {
+			_, err := store.AddCategory(ctx, "dorm", "abcd")
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		list, err := store.ListCategories(ctx)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(list.Categories), 2)
+	})
+
+	t.Run("RenameCategory", func(t *testing.T) {
+		store := newStore()
+
+		category := addCategory(t, ctx, store, "accommodation", "")
+		addCategory(t, ctx, store, "food", "")
+
+		newName := "new name"
+
+		got, err := store.RenameCategory(ctx, category.ID, newName)
+		assertNoError(t, err)
+
+		// assert response
+		assertStringsEqual(t, got.ID, category.ID)
+		assertStringsEqual(t, got.Name, newName)
+
+		// assert store
+		got, err = store.GetCategory(ctx, category.ID)
+		assertNoError(t, err)
+		assertStringsEqual(t, got.ID, category.ID)
+		assertStringsEqual(t, got.Name, newName)
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.RenameCategory(ctx, "abcd", "other name")
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("name already exists", func(t *testing.T) {
+			_, err := store.RenameCategory(ctx, category.ID, "food")
+			assertErrorIs(t, err, ErrConflict)
+		})
+
+		t.Run("renaming to its own name", func(t *testing.T) {
+			_, err := store.RenameCategory(ctx, category.ID, newName)
+			assertNoError(t, err)
+		})
+	})
+
+	t.Run("MoveCategory", func(t *testing.T) {
+		store := newStore()
+
+		accommodation := addCategory(t, ctx, store, "accommodation", "")
+		food := addCategory(t, ctx, store, "food", "")
+		hostel := addCategory(t, ctx, store, "hostel", accommodation.ID)
+
+		got, err := store.MoveCategory(ctx, hostel.ID, food.ID)
+		assertNoError(t, err)
+		assertStringsEqual(t, got.ParentID, food.ID)
+
+		children, err := store.GetChildCategories(ctx, food.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, children, []Category{got})
+
+		t.Run("to the top level", func(t *testing.T) {
+			got, err := store.MoveCategory(ctx, hostel.ID, "")
+			assertNoError(t, err)
+			assertStringsEqual(t, got.ParentID, "")
+
+			got, err = store.GetCategory(ctx, hostel.ID)
+			assertNoError(t, err)
+			assertStringsEqual(t, got.ParentID, "")
+		})
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.MoveCategory(ctx, "abcd", food.ID)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("parent doesn't exist", func(t *testing.T) {
+			_, err := store.MoveCategory(ctx, hostel.ID, "abcd")
+			assertErrorIs(t, err, ErrNotFound)
+
+			got, err := store.GetCategory(ctx, hostel.ID)
+			assertNoError(t, err)
+			assertStringsEqual(t, got.ParentID, "")
+		})
+
+		t.Run("another user's category", func(t *testing.T) {
+			_, err := store.MoveCategory(bob, hostel.ID, "")
+			assertErrorIs(t, err, ErrNotFound)
+
+			_, err = store.MoveCategory(ctx, hostel.ID, addCategory(t, bob, store, "hostel", "").ID)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("DeleteCategory", func(t *testing.T) {
+		store := newStore()
+
+		category := addCategory(t, ctx, store, "accommodation", "")
+
+		err := store.DeleteCategory(ctx, category.ID)
+		assertNoError(t, err)
+
+		list, err := store.ListCategories(ctx)
+		assertNoError(t, err)
+		got := len(list.Categories)
+		want := 0
+		assertNumbersEqual(t, got, want)
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			err := store.DeleteCategory(ctx, category.ID)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("DeleteCategories", func(t *testing.T) {
+		store := newStore()
+
+		accommodation := addCategory(t, ctx, store, "accommodation", "")
+		hostel := addCategory(t, ctx, store, "hostel", accommodation.ID)
+		food := addCategory(t, ctx, store, "food and drink", "")
+
+		t.Run("nothing is deleted if a category doesn't exist", func(t *testing.T) {
+			err := store.DeleteCategories(ctx, []string{hostel.ID, "missing"})
+			assertErrorIs(t, err, ErrNotFound)
+
+			list, err := store.ListCategories(ctx)
+			assertNoError(t, err)
+			assertDeepEqual(t, list.Categories, []Category{accommodation, hostel, food})
+		})
+
+		err := store.DeleteCategories(ctx, []string{hostel.ID, accommodation.ID})
+		assertNoError(t, err)
+
+		list, err := store.ListCategories(ctx)
+		assertNoError(t, err)
+		assertDeepEqual(t, list.Categories, []Category{food})
+	})
+
+	t.Run("CategoryIDExists & CategoryNameExists", func(t *testing.T) {
+		store := newStore()
+
+		category := addCategory(t, ctx, store, "accommodation", "")
+
+		cases := map[string]struct {
+			exists func() (bool, error)
+			want   bool
+		}{
+			"ID exists":           {func() (bool, error) { return store.CategoryIDExists(ctx, category.ID) }, true},
+			"ID doesn't exist":    {func() (bool, error) { return store.CategoryIDExists(ctx, "abcd") }, false},
+			"name exists":         {func() (bool, error) { return store.CategoryNameExists(ctx, "accommodation") }, true},
+			"name doesn't exist":  {func() (bool, error) { return store.CategoryNameExists(ctx, "hostel") }, false},
+			"another user's ID":   {func() (bool, error) { return store.CategoryIDExists(bob, category.ID) }, false},
+			"another user's name": {func() (bool, error) { return store.CategoryNameExists(bob, "accommodation") }, false},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				got, err := c.exists()
+				assertNoError(t, err)
+				assertBool(t, got, c.want)
+			})
+		}
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		store := newStore()
+
+		parent := addCategory(t, ctx, store, "accommodation", "")
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				category, err := store.AddCategory(ctx, fmt.Sprintf("category %d", i), parent.ID)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				store.RenameCategory(ctx, category.ID, fmt.Sprintf("renamed %d", i))
+				store.ListCategories(ctx)
+				store.GetChildCategories(ctx, parent.ID)
+				store.GetDescendantCategories(ctx, parent.ID)
+				store.CategoryNameExists(ctx, "accommodation")
+			}(i)
+		}
+		wg.Wait()
+
+		children, err := store.GetChildCategories(ctx, parent.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(children), concurrency)
+
+		for _, c := range children {
+			assertNoError(t, store.DeleteCategory(ctx, c.ID))
+		}
+
+		list, err := store.ListCategories(ctx)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(list.Categories), 1)
+	})
+
+	t.Run("concurrent adds with the same name", func(t *testing.T) {
+		store := newStore()
+
+		conflicts := make(chan error, concurrency)
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := store.AddCategory(ctx, "accommodation", ""); err != nil {
+					conflicts <- err
+				}
+			}()
+		}
+		wg.Wait()
+		close(conflicts)
+
+		for err := range conflicts {
+			assertErrorIs(t, err, ErrConflict)
+		}
+
+		list, err := store.ListCategories(ctx)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(list.Categories), 1)
+	})
+
+	t.Run("GetCategoryDepth", func(t *testing.T) {
+		store := newStore()
+
+		accommodation := addCategory(t, ctx, store, "accommodation", "")
+		hostel := addCategory(t, ctx, store, "hostel", accommodation.ID)
+
+		depth, err := store.GetCategoryDepth(ctx, accommodation.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, depth, 0)
+
+		depth, err = store.GetCategoryDepth(ctx, hostel.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, depth, 1)
+
+		dorm := addCategory(t, ctx, store, "dorm", hostel.ID)
+		bunk := addCategory(t, ctx, store, "bunk", dorm.ID)
+
+		depth, err = store.GetCategoryDepth(ctx, bunk.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, depth, 3)
+
+		depth, err = store.GetCategoryDepth(ctx, "abcd")
+		assertNoError(t, err)
+		assertNumbersEqual(t, depth, 0)
+	})
+
+	t.Run("users only see their own categories", func(t *testing.T) {
+		store := newStore()
+
+		accommodation := addCategory(t, alice, store, "accommodation", "")
+		hostel := addCategory(t, alice, store, "hostel", accommodation.ID)
+		assertStringsEqual(t, accommodation.Owner, "alice")
+
+		list, err := store.ListCategories(bob)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(list.Categories), 0)
+
+		_, err = store.GetCategory(bob, accommodation.ID)
+		assertErrorIs(t, err, ErrNotFound)
+
+		children, err := store.GetChildCategories(bob, accommodation.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(children), 0)
+
+		descendants, err := store.GetDescendantCategories(bob, accommodation.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(descendants), 0)
+
+		depth, err := store.GetCategoryDepth(bob, hostel.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, depth, 0)
+
+		// another user's category can't be a parent
+		_, err = store.AddCategory(bob, "dorm", accommodation.ID)
+		assertErrorIs(t, err, ErrNotFound)
+
+		// names only need to be unique per user
+		bobsAccommodation := addCategory(t, bob, store, "accommodation", "")
+
+		// changes can't reach another user's categories
+		_, err = store.RenameCategory(bob, accommodation.ID, "hotels")
+		assertErrorIs(t, err, ErrNotFound)
+		err = store.DeleteCategory(bob, hostel.ID)
+		assertErrorIs(t, err, ErrNotFound)
+
+		list, err = store.ListCategories(alice)
+		assertNoError(t, err)
+		assertDeepEqual(t, list, CategoryList{Categories: []Category{accommodation, hostel}})
+
+		list, err = store.ListCategories(bob)
+		assertNoError(t, err)
+		assertDeepEqual(t, list, CategoryList{Categories: []Category{bobsAccommodation}})
+	})
+}
+
+// testQuestionStore is the conformance suite every QuestionStore backend must pass
+// newStores must return an empty QuestionStore each time it is called,
+// along with the CategoryStore its questions' categories should be added to
+func testQuestionStore(t *testing.T, newStores func() (CategoryStore, QuestionStore)) {
+	t.Run("ListQuestions", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		accommodation := addCategory(t, ctx, categoryStore, "accommodation", "")
+		food := addCategory(t, ctx, categoryStore, "food", "")
+
+		nights := addQuestion(t, ctx, store, accommodation.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+		meal := addQuestion(t, ctx, store, food.ID, QuestionPostRequest{Title: "which meal?", Type: "string", Options: &[]string{"brekkie"}})
+
+		got, err := store.ListQuestions(ctx)
+		assertNoError(t, err)
+		want := QuestionList{
+			Questions: []Question{nights, meal},
+		}
+		assertDeepEqual(t, got, want)
+	})
+
+	t.Run("ListQuestionsForCategory", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		accommodation := addCategory(t, ctx, categoryStore, "accommodation", "")
+		food := addCategory(t, ctx, categoryStore, "food", "")
+
+		nights := addQuestion(t, ctx, store, accommodation.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+		addQuestion(t, ctx, store, food.ID, QuestionPostRequest{Title: "which meal?", Type: "string", Options: &[]string{"brekkie"}})
+
+		got, err := store.ListQuestionsForCategory(ctx, accommodation.ID)
+		assertNoError(t, err)
+		want := QuestionList{
+			Questions: []Question{nights},
+		}
+		assertDeepEqual(t, got, want)
+	})
+
+	t.Run("GetQuestion", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "food", "")
+		question := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "which meal?", Type: "string", Options: &[]string{"brekkie"}})
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.GetQuestion(ctx, "abcd")
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("ID exists", func(t *testing.T) {
+			got, err := store.GetQuestion(ctx, question.ID)
+			assertNoError(t, err)
+			want := question
+			assertDeepEqual(t, got, want)
+		})
+	})
+
+	t.Run("AddQuestion", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		categoryID := addCategory(t, ctx, categoryStore, "food", "").ID
+
+		t.Run("question with options", func(t *testing.T) {
+			question := QuestionPostRequest{
+				Title:   "foo",
+				Type:    "string",
+				Options: &[]string{"bar"},
+			}
+
+			got, err := store.AddQuestion(ctx, categoryID, question)
+			assertNoError(t, err)
+
+			// assert response
+			assertIsXid(t, got.ID)
+			assertStringsEqual(t, got.Title, question.Title)
+			assertStringsEqual(t, got.CategoryID, categoryID)
+			assertStringsEqual(t, got.Type, question.Type)
+			assertIsXid(t, got.Options[0].ID)
+			assertStringsEqual(t, got.Options[0].Title, (*question.Options)[0])
+
+			// assert store
+			got, err = store.GetQuestion(ctx, got.ID)
+			assertNoError(t, err)
+			assertIsXid(t, got.ID)
+			assertStringsEqual(t, got.Title, question.Title)
+			assertStringsEqual(t, got.CategoryID, categoryID)
+			assertStringsEqual(t, got.Type, question.Type)
+			assertIsXid(t, got.Options[0].ID)
+			assertStringsEqual(t, got.Options[0].Title, (*question.Options)[0])
+		})
+
+		t.Run("question without options", func(t *testing.T) {
+			question := QuestionPostRequest{
+				Title:   "bar",
+				Type:    "number",
+				Options: nil,
+			}
+
+			got, err := store.AddQuestion(ctx, categoryID, question)
+			assertNoError(t, err)
+
+			// assert response
+			assertIsXid(t, got.ID)
+			assertStringsEqual(t, got.Title, question.Title)
+			assertStringsEqual(t, got.CategoryID, categoryID)
+			assertStringsEqual(t, got.Type, question.Type)
+			if got.Options != nil {
+				t.Fatal("options should not be present")
+			}
+
+			// assert store
+			got, err = store.GetQuestion(ctx, got.ID)
+			assertNoError(t, err)
+			assertIsXid(t, got.ID)
+			assertStringsEqual(t, got.Title, question.Title)
+			assertStringsEqual(t, got.CategoryID, categoryID)
+			assertStringsEqual(t, got.Type, question.Type)
+			if got.Options != nil {
+				t.Fatal("options should not be present")
+			}
+		})
+
+		t.Run("title already exists in the category", func(t *testing.T) {
+			_, err := store.AddQuestion(ctx, categoryID, QuestionPostRequest{Title: "foo", Type: "number"})
+			assertErrorIs(t, err, ErrConflict)
+		})
+
+		t.Run("title exists in another category", func(t *testing.T) {
+			otherID := addCategory(t, ctx, categoryStore, "drink", "").ID
+			_, err := store.AddQuestion(ctx, otherID, QuestionPostRequest{Title: "foo", Type: "number"})
+			assertNoError(t, err)
+		})
+	})
+
+	t.Run("RenameQuestion", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		question := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+		addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "how many guests?", Type: "number"})
+
+		newTitle := "foobar"
+
+		got, err := store.RenameQuestion(ctx, question.ID, newTitle)
+		assertNoError(t, err)
+
+		// assert response
+		assertStringsEqual(t, got.ID, question.ID)
+		assertStringsEqual(t, got.Title, newTitle)
+
+		// assert store
+		got, err = store.GetQuestion(ctx, question.ID)
+		assertNoError(t, err)
+		assertStringsEqual(t, got.ID, question.ID)
+		assertStringsEqual(t, got.Title, newTitle)
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.RenameQuestion(ctx, "abcd", "barfoo")
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("title already exists in the category", func(t *testing.T) {
+			_, err := store.RenameQuestion(ctx, question.ID, "how many guests?")
+			assertErrorIs(t, err, ErrConflict)
+		})
+	})
+
+	t.Run("UpdateQuestion", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		guests := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "how many guests?", Type: "number"})
+		question := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "how many nights?", Type: "number", Constraints: &Constraints{Required: true}})
+
+		newTitle := "foobar"
+		dependsOn := &Dependency{QuestionID: guests.ID, Min: new(float64)}
+
+		got, err := store.UpdateQuestion(ctx, question.ID, QuestionPatchRequest{Title: &newTitle, Constraints: &Constraints{}, DependsOn: dependsOn})
+		assertNoError(t, err)
+
+		// an empty object removes the constraints
+		want := Question{ID: question.ID, Title: newTitle, CategoryID: category.ID, Type: "number", DependsOn: dependsOn}
+		assertDeepEqual(t, got, want)
+
+		got, err = store.GetQuestion(ctx, question.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got, want)
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.UpdateQuestion(ctx, "abcd", QuestionPatchRequest{Title: &newTitle})
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("nothing is changed when the title already exists in the category", func(t *testing.T) {
+			taken := guests.Title
+			_, err := store.UpdateQuestion(ctx, question.ID, QuestionPatchRequest{Title: &taken, DependsOn: &Dependency{}})
+			assertErrorIs(t, err, ErrConflict)
+
+			got, err := store.GetQuestion(ctx, question.ID)
+			assertNoError(t, err)
+			assertDeepEqual(t, got, want)
+		})
+	})
+
+	t.Run("SetQuestionConstraints", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		min, step := float64(1), float64(0.5)
+		constraints := &Constraints{Required: true, Min: &min, Step: &step, Unit: "nights", Default: float64(2)}
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		question := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "how many nights?", Type: "number", Constraints: constraints})
+		assertDeepEqual(t, question.Constraints, constraints)
+
+		changed := &Constraints{Default: float64(3)}
+		got, err := store.SetQuestionConstraints(ctx, question.ID, changed)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.Constraints, changed)
+
+		got, err = store.GetQuestion(ctx, question.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.Constraints, changed)
+
+		t.Run("removing them", func(t *testing.T) {
+			_, err := store.SetQuestionConstraints(ctx, question.ID, nil)
+			assertNoError(t, err)
+
+			got, err := store.GetQuestion(ctx, question.ID)
+			assertNoError(t, err)
+			if got.Constraints != nil {
+				t.Errorf("got constraints %v wanted none", got.Constraints)
+			}
+		})
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.SetQuestionConstraints(ctx, "abcd", changed)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("SetQuestionDependency", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		kind := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "what kind?", Type: "string", Options: &[]string{"hotel", "hostel"}})
+		nights := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+
+		dependsOn := &Dependency{QuestionID: kind.ID, OptionID: kind.Options[0].ID}
+		question := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "star rating?", Type: "number", DependsOn: dependsOn})
+		assertDeepEqual(t, question.DependsOn, dependsOn)
+
+		min := float64(2)
+		changed := &Dependency{QuestionID: nights.ID, Min: &min}
+		got, err := store.SetQuestionDependency(ctx, question.ID, changed)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.DependsOn, changed)
+
+		got, err = store.GetQuestion(ctx, question.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.DependsOn, changed)
+
+		t.Run("removing it", func(t *testing.T) {
+			_, err := store.SetQuestionDependency(ctx, question.ID, nil)
+			assertNoError(t, err)
+
+			got, err := store.GetQuestion(ctx, question.ID)
+			assertNoError(t, err)
+			if got.DependsOn != nil {
+				t.Errorf("got dependency %v wanted none", got.DependsOn)
+			}
+		})
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.SetQuestionDependency(ctx, "abcd", changed)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("HideQuestion & UnhideQuestion", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		food := addCategory(t, ctx, categoryStore, "food", "")
+		lunch := addCategory(t, ctx, categoryStore, "lunch", food.ID)
+		dinner := addCategory(t, ctx, categoryStore, "dinner", food.ID)
+		question := addQuestion(t, ctx, store, food.ID, QuestionPostRequest{Title: "which meal?", Type: "number"})
+
+		got, err := store.HideQuestion(ctx, question.ID, lunch.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.HiddenIn, []string{lunch.ID})
+
+		t.Run("hiding again changes nothing", func(t *testing.T) {
+			_, err := store.HideQuestion(ctx, question.ID, lunch.ID)
+			assertNoError(t, err)
+
+			got, err = store.HideQuestion(ctx, question.ID, dinner.ID)
+			assertNoError(t, err)
+			assertDeepEqual(t, got.HiddenIn, []string{lunch.ID, dinner.ID})
+		})
+
+		got, err = store.UnhideQuestion(ctx, question.ID, lunch.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.HiddenIn, []string{dinner.ID})
+
+		got, err = store.GetQuestion(ctx, question.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.HiddenIn, []string{dinner.ID})
+
+		t.Run("question isn't hidden", func(t *testing.T) {
+			_, err := store.UnhideQuestion(ctx, question.ID, lunch.ID)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.HideQuestion(ctx, "abcd", lunch.ID)
+			assertErrorIs(t, err, ErrNotFound)
+
+			_, err = store.UnhideQuestion(ctx, "abcd", dinner.ID)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("AddQuestion overriding another", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		food := addCategory(t, ctx, categoryStore, "food", "")
+		lunch := addCategory(t, ctx, categoryStore, "lunch", food.ID)
+		question := addQuestion(t, ctx, store, food.ID, QuestionPostRequest{Title: "which meal?", Type: "number"})
+
+		override := addQuestion(t, ctx, store, lunch.ID, QuestionPostRequest{Title: "which meal?", Type: "boolean", Overrides: question.ID})
+		assertStringsEqual(t, override.Overrides, question.ID)
+
+		got, err := store.GetQuestion(ctx, override.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got, override)
+	})
+
+	t.Run("DeleteQuestion", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		question := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+
+		err := store.DeleteQuestion(ctx, question.ID)
+		assertNoError(t, err)
+
+		list, err := store.ListQuestionsForCategory(ctx, category.ID)
+		assertNoError(t, err)
+		got := len(list.Questions)
+		want := 0
+		assertNumbersEqual(t, got, want)
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			err := store.DeleteQuestion(ctx, question.ID)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("DeleteQuestionsForCategory", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		accommodation := addCategory(t, ctx, categoryStore, "accommodation", "")
+		food := addCategory(t, ctx, categoryStore, "food", "")
+		addQuestion(t, ctx, store, accommodation.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+		addQuestion(t, ctx, store, accommodation.ID, QuestionPostRequest{Title: "how many guests?", Type: "number"})
+		addQuestion(t, ctx, store, food.ID, QuestionPostRequest{Title: "which meal?", Type: "string", Options: &[]string{"brekkie"}})
+
+		err := store.DeleteQuestionsForCategory(ctx, accommodation.ID)
+		assertNoError(t, err)
+
+		list, err := store.ListQuestionsForCategory(ctx, accommodation.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(list.Questions), 0)
+
+		list, err = store.ListQuestionsForCategory(ctx, food.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(list.Questions), 1)
+	})
+
+	t.Run("AddOption", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		question := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "what kind?", Type: "string", Options: &[]string{"hotel"}})
+
+		got, err := store.AddOption(ctx, question.ID, "hostel")
+		assertNoError(t, err)
+
+		// assert response
+		assertIsXid(t, got.ID)
+		assertStringsEqual(t, got.Title, "hostel")
+
+		// assert store, with the option added last
+		stored, err := store.GetQuestion(ctx, question.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, stored.Options, OptionList{question.Options[0], got})
+
+		t.Run("question doesn't exist", func(t *testing.T) {
+			_, err := store.AddOption(ctx, "abcd", "motel")
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("title already exists on the question", func(t *testing.T) {
+			_, err := store.AddOption(ctx, question.ID, "hotel")
+			assertErrorIs(t, err, ErrConflict)
+		})
+
+		t.Run("string question added without options", func(t *testing.T) {
+			empty := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "which room?", Type: "string"})
+			assertDeepEqual(t, empty.Options, OptionList{})
+
+			_, err := store.AddOption(ctx, empty.ID, "double")
+			assertNoError(t, err)
+		})
+	})
+
+	t.Run("RenameOption", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		question := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "what kind?", Type: "string", Options: &[]string{"hotel", "hostel"}})
+		hotel := question.Options[0]
+
+		got, err := store.RenameOption(ctx, question.ID, hotel.ID, "motel")
+		assertNoError(t, err)
+
+		// assert response
+		assertDeepEqual(t, got, Option{ID: hotel.ID, Title: "motel"})
+
+		// assert store
+		stored, err := store.GetQuestion(ctx, question.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, stored.Options, OptionList{got, question.Options[1]})
+
+		t.Run("option doesn't exist", func(t *testing.T) {
+			_, err := store.RenameOption(ctx, question.ID, "abcd", "inn")
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("question doesn't exist", func(t *testing.T) {
+			_, err := store.RenameOption(ctx, "abcd", hotel.ID, "inn")
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("title already exists on the question", func(t *testing.T) {
+			_, err := store.RenameOption(ctx, question.ID, hotel.ID, "hostel")
+			assertErrorIs(t, err, ErrConflict)
+		})
+
+		t.Run("renaming to its own title", func(t *testing.T) {
+			_, err := store.RenameOption(ctx, question.ID, hotel.ID, "motel")
+			assertNoError(t, err)
+		})
+	})
+
+	t.Run("DeleteOption", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		question := addQuestion(t, ctx, store, category.ID, QuestionPostRequest{Title: "what kind?", Type: "string", Options: &[]string{"hotel", "hostel"}})
+		hotel := question.Options[0]
+
+		err := store.DeleteOption(ctx, question.ID, hotel.ID)
+		assertNoError(t, err)
+
+		stored, err := store.GetQuestion(ctx, question.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, stored.Options, OptionList{question.Options[1]})
+
+		t.Run("option doesn't exist", func(t *testing.T) {
+			err := store.DeleteOption(ctx, question.ID, hotel.ID)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("question doesn't exist", func(t *testing.T) {
+			err := store.DeleteOption(ctx, "abcd", question.Options[1].ID)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				question, err := store.AddQuestion(ctx, category.ID, QuestionPostRequest{Title: fmt.Sprintf("question %d", i), Type: "string", Options: &[]string{"foo"}})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				store.RenameQuestion(ctx, question.ID, fmt.Sprintf("renamed %d", i))
+				store.GetQuestion(ctx, question.ID)
+				store.ListQuestionsForCategory(ctx, category.ID)
+				store.QuestionTitleExists(ctx, category.ID, "foo")
+			}(i)
+		}
+		wg.Wait()
+
+		list, err := store.ListQuestionsForCategory(ctx, category.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(list.Questions), concurrency)
+	})
+
+	t.Run("QuestionIDExists, QuestionTitleExists & QuestionBelongsToCategory", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		accommodation := addCategory(t, ctx, categoryStore, "accommodation", "")
+		food := addCategory(t, ctx, categoryStore, "food", "")
+		question := addQuestion(t, ctx, store, accommodation.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+
+		cases := map[string]struct {
+			exists func() (bool, error)
+			want   bool
+		}{
+			"ID exists":                 {func() (bool, error) { return store.QuestionIDExists(ctx, question.ID) }, true},
+			"ID doesn't exist":          {func() (bool, error) { return store.QuestionIDExists(ctx, "abcd") }, false},
+			"title exists":              {func() (bool, error) { return store.QuestionTitleExists(ctx, accommodation.ID, "how many nights?") }, true},
+			"title in another category": {func() (bool, error) { return store.QuestionTitleExists(ctx, food.ID, "how many nights?") }, false},
+			"belongs to category":       {func() (bool, error) { return store.QuestionBelongsToCategory(ctx, question.ID, accommodation.ID) }, true},
+			"belongs to another":        {func() (bool, error) { return store.QuestionBelongsToCategory(ctx, question.ID, food.ID) }, false},
+			"another user's ID":         {func() (bool, error) { return store.QuestionIDExists(bob, question.ID) }, false},
+			"another user's title":      {func() (bool, error) { return store.QuestionTitleExists(bob, accommodation.ID, "how many nights?") }, false},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				got, err := c.exists()
+				assertNoError(t, err)
+				assertBool(t, got, c.want)
+			})
+		}
+	})
+
+	t.Run("users only see their own questions", func(t *testing.T) {
+		categoryStore, store := newStores()
+
+		accommodation := addCategory(t, alice, categoryStore, "accommodation", "")
+		question := addQuestion(t, alice, store, accommodation.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+		assertStringsEqual(t, question.Owner, "alice")
+
+		list, err := store.ListQuestionsForCategory(bob, accommodation.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(list.Questions), 0)
+
+		_, err = store.GetQuestion(bob, question.ID)
+		assertErrorIs(t, err, ErrNotFound)
+
+		// changes can't reach another user's questions
+		_, err = store.RenameQuestion(bob, question.ID, "how many guests?")
+		assertErrorIs(t, err, ErrNotFound)
+		err = store.DeleteQuestion(bob, question.ID)
+		assertErrorIs(t, err, ErrNotFound)
+		err = store.DeleteQuestionsForCategory(bob, accommodation.ID)
+		assertNoError(t, err)
+		_, err = store.AddOption(bob, question.ID, "hotel")
+		assertErrorIs(t, err, ErrNotFound)
+
+		got, err := store.GetQuestion(alice, question.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got, question)
+	})
+}
+
+// testTransactionStore is the conformance suite every TransactionStore backend must pass
+// newStores must return an empty TransactionStore each time it is called,
+// along with the stores its transactions' categories & questions should be added to
+func testTransactionStore(t *testing.T, newStores func() (CategoryStore, QuestionStore, TransactionStore)) {
+	timestamp := time.Date(2019, time.March, 1, 12, 30, 0, 0, time.UTC)
+
+	t.Run("ListTransactions", func(t *testing.T) {
+		_, _, store := newStores()
+
+		got, err := store.ListTransactions(ctx)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(got.Transactions), 0)
+
+		first := addTransaction(t, ctx, store, Transaction{Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp})
+		second := addTransaction(t, ctx, store, Transaction{Amount: -1200, Currency: "GBP", Merchant: "Hostel", Timestamp: timestamp})
+
+		got, err = store.ListTransactions(ctx)
+		assertNoError(t, err)
+		want := TransactionList{
+			Transactions: []Transaction{first, second},
+		}
+		assertDeepEqual(t, got, want)
+	})
+
+	t.Run("GetTransaction", func(t *testing.T) {
+		_, _, store := newStores()
+
+		transaction := addTransaction(t, ctx, store, Transaction{Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp})
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.GetTransaction(ctx, "abcd")
+			assertErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("ID exists", func(t *testing.T) {
+			got, err := store.GetTransaction(ctx, transaction.ID)
+			assertNoError(t, err)
+			want := transaction
+			assertDeepEqual(t, got, want)
+		})
+	})
+
+	t.Run("AddTransaction", func(t *testing.T) {
+		categoryStore, questionStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		question := addQuestion(t, ctx, questionStore, category.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+
+		transaction := Transaction{
+			Amount:     -1200,
+			Currency:   "GBP",
+			Merchant:   "Hostel",
+			Timestamp:  timestamp,
+			CategoryID: category.ID,
+			Answers:    []Answer{{QuestionID: question.ID, Value: float64(2)}},
+		}
+
+		got, err := store.AddTransaction(ctx, transaction)
+		assertNoError(t, err)
+
+		// assert response
+		assertIsXid(t, got.ID)
+		transaction.ID = got.ID
+		assertDeepEqual(t, got, transaction)
+
+		// assert store
+		got, err = store.GetTransaction(ctx, got.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got, transaction)
+	})
+
+	t.Run("ImportTransaction", func(t *testing.T) {
+		_, _, store := newStores()
+
+		transaction := Transaction{MonzoID: "tx_0001", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp}
+
+		first, err := store.ImportTransaction(ctx, transaction)
+		assertNoError(t, err)
+		assertIsXid(t, first.ID)
+		assertStringsEqual(t, first.MonzoID, transaction.MonzoID)
+
+		// re-importing is a conflict, and returns the existing transaction
+		again, err := store.ImportTransaction(ctx, transaction)
+		assertErrorIs(t, err, ErrConflict)
+		assertDeepEqual(t, again, first)
+
+		list, err := store.ListTransactions(ctx)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(list.Transactions), 1)
+
+		got, err := store.GetTransaction(ctx, first.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got, first)
+	})
+
+	t.Run("CategoriseTransaction", func(t *testing.T) {
+		categoryStore, questionStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "food", "")
+		question := addQuestion(t, ctx, questionStore, category.ID, QuestionPostRequest{Title: "which meal?", Type: "string", Options: &[]string{"brekkie"}})
+		answers := []Answer{{QuestionID: question.ID, Value: question.Options[0].ID}}
+
+		transaction := addTransaction(t, ctx, store, Transaction{Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp})
+
+		got, err := store.CategoriseTransaction(ctx, transaction.ID, category.ID, answers)
+		assertNoError(t, err)
+
+		// assert response
+		assertStringsEqual(t, got.ID, transaction.ID)
+		assertStringsEqual(t, got.CategoryID, category.ID)
+		assertDeepEqual(t, got.Answers, answers)
+
+		// assert store
+		got, err = store.GetTransaction(ctx, transaction.ID)
+		assertNoError(t, err)
+		assertStringsEqual(t, got.CategoryID, category.ID)
+		assertDeepEqual(t, got.Answers, answers)
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			_, err := store.CategoriseTransaction(ctx, "abcd", category.ID, nil)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("DeleteTransaction", func(t *testing.T) {
+		_, _, store := newStores()
+
+		transaction := addTransaction(t, ctx, store, Transaction{Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp})
+
+		err := store.DeleteTransaction(ctx, transaction.ID)
+		assertNoError(t, err)
+
+		list, err := store.ListTransactions(ctx)
+		assertNoError(t, err)
+		got := len(list.Transactions)
+		want := 0
+		assertNumbersEqual(t, got, want)
+
+		t.Run("ID doesn't exist", func(t *testing.T) {
+			err := store.DeleteTransaction(ctx, transaction.ID)
+			assertErrorIs(t, err, ErrNotFound)
+		})
+	})
+
+	t.Run("ReassignTransactions", func(t *testing.T) {
+		categoryStore, questionStore, store := newStores()
+
+		accommodation := addCategory(t, ctx, categoryStore, "accommodation", "")
+		lodging := addCategory(t, ctx, categoryStore, "lodging", "")
+		food := addCategory(t, ctx, categoryStore, "food", "")
+		question := addQuestion(t, ctx, questionStore, accommodation.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+
+		hostel := addTransaction(t, ctx, store, Transaction{Amount: -1200, Currency: "GBP", Merchant: "Hostel", Timestamp: timestamp, CategoryID: accommodation.ID,
+			Answers: []Answer{{QuestionID: question.ID, Value: float64(2)}}})
+		pret := addTransaction(t, ctx, store, Transaction{Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp, CategoryID: food.ID})
+
+		t.Run("to another category", func(t *testing.T) {
+			err := store.ReassignTransactions(ctx, accommodation.ID, lodging.ID)
+			assertNoError(t, err)
+
+			got, err := store.GetTransaction(ctx, hostel.ID)
+			assertNoError(t, err)
+			assertStringsEqual(t, got.CategoryID, lodging.ID)
+			assertNumbersEqual(t, len(got.Answers), 0)
+
+			// other categories are untouched
+			got, err = store.GetTransaction(ctx, pret.ID)
+			assertNoError(t, err)
+			assertStringsEqual(t, got.CategoryID, food.ID)
+		})
+
+		t.Run("to uncategorised", func(t *testing.T) {
+			err := store.ReassignTransactions(ctx, lodging.ID, "")
+			assertNoError(t, err)
+
+			got, err := store.GetTransaction(ctx, hostel.ID)
+			assertNoError(t, err)
+			assertStringsEqual(t, got.CategoryID, "")
+		})
+	})
+
+	t.Run("DeleteAnswersForQuestion", func(t *testing.T) {
+		categoryStore, questionStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		nights := addQuestion(t, ctx, questionStore, category.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+		guests := addQuestion(t, ctx, questionStore, category.ID, QuestionPostRequest{Title: "how many guests?", Type: "number"})
+
+		transaction := addTransaction(t, ctx, store, Transaction{Amount: -1200, Currency: "GBP", Merchant: "Hostel", Timestamp: timestamp, CategoryID: category.ID,
+			Answers: []Answer{{QuestionID: nights.ID, Value: float64(2)}, {QuestionID: guests.ID, Value: float64(1)}}})
+
+		err := store.DeleteAnswersForQuestion(ctx, nights.ID)
+		assertNoError(t, err)
+
+		got, err := store.GetTransaction(ctx, transaction.ID)
+		assertNoError(t, err)
+		want := []Answer{{QuestionID: guests.ID, Value: float64(1)}}
+		assertDeepEqual(t, got.Answers, want)
+	})
+
+	t.Run("DeleteAnswersForOption", func(t *testing.T) {
+		categoryStore, questionStore, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+		kind := addQuestion(t, ctx, questionStore, category.ID, QuestionPostRequest{Title: "what kind?", Type: "string", Options: &[]string{"hotel", "hostel"}})
+		nights := addQuestion(t, ctx, questionStore, category.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+		hotel, hostel := kind.Options[0], kind.Options[1]
+
+		hotelStay := addTransaction(t, ctx, store, Transaction{Amount: -4000, Currency: "GBP", Merchant: "Hotel", Timestamp: timestamp, CategoryID: category.ID,
+			Answers: []Answer{{QuestionID: kind.ID, Value: hotel.ID}, {QuestionID: nights.ID, Value: float64(2)}}})
+		hostelStay := addTransaction(t, ctx, store, Transaction{Amount: -1200, Currency: "GBP", Merchant: "Hostel", Timestamp: timestamp, CategoryID: category.ID,
+			Answers: []Answer{{QuestionID: kind.ID, Value: hostel.ID}}})
+
+		err := store.DeleteAnswersForOption(ctx, kind.ID, hotel.ID)
+		assertNoError(t, err)
+
+		got, err := store.GetTransaction(ctx, hotelStay.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.Answers, []Answer{{QuestionID: nights.ID, Value: float64(2)}})
+
+		// answers choosing other options are untouched
+		got, err = store.GetTransaction(ctx, hostelStay.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got.Answers, hostelStay.Answers)
+
+		t.Run("multiselect answers keep their other options", func(t *testing.T) {
+			meals := addQuestion(t, ctx, questionStore, category.ID, QuestionPostRequest{Title: "which meals?", Type: "multiselect", Options: &[]string{"brekkie", "dinner"}})
+			brekkie, dinner := meals.Options[0].ID, meals.Options[1].ID
+
+			both := addTransaction(t, ctx, store, Transaction{Amount: -6000, Currency: "GBP", Merchant: "Inn", Timestamp: timestamp, CategoryID: category.ID,
+				Answers: []Answer{{QuestionID: meals.ID, Value: []interface{}{brekkie, dinner}}}})
+			brekkieOnly := addTransaction(t, ctx, store, Transaction{Amount: -5000, Currency: "GBP", Merchant: "B&B", Timestamp: timestamp, CategoryID: category.ID,
+				Answers: []Answer{{QuestionID: meals.ID, Value: []interface{}{brekkie}}}})
+
+			count, err := store.CountTransactionsAnsweringOption(ctx, meals.ID, brekkie)
+			assertNoError(t, err)
+			assertNumbersEqual(t, count, 2)
+
+			err = store.DeleteAnswersForOption(ctx, meals.ID, brekkie)
+			assertNoError(t, err)
+
+			got, err := store.GetTransaction(ctx, both.ID)
+			assertNoError(t, err)
+			assertDeepEqual(t, got.Answers, []Answer{{QuestionID: meals.ID, Value: []interface{}{dinner}}})
+
+			got, err = store.GetTransaction(ctx, brekkieOnly.ID)
+			assertNoError(t, err)
+			assertNumbersEqual(t, len(got.Answers), 0)
+		})
+	})
+
+	t.Run("CountTransactionsForCategory & CountTransactionsAnsweringQuestion & CountTransactionsAnsweringOption", func(t *testing.T) {
+		categoryStore, questionStore, store := newStores()
+
+		accommodation := addCategory(t, ctx, categoryStore, "accommodation", "")
+		food := addCategory(t, ctx, categoryStore, "food", "")
+		nights := addQuestion(t, ctx, questionStore, accommodation.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+		guests := addQuestion(t, ctx, questionStore, accommodation.ID, QuestionPostRequest{Title: "how many guests?", Type: "number"})
+		kind := addQuestion(t, ctx, questionStore, accommodation.ID, QuestionPostRequest{Title: "what kind?", Type: "string", Options: &[]string{"hotel", "hostel"}})
+
+		addTransaction(t, ctx, store, Transaction{Amount: -1200, Currency: "GBP", Merchant: "Hostel", Timestamp: timestamp, CategoryID: accommodation.ID,
+			Answers: []Answer{{QuestionID: nights.ID, Value: float64(2)}, {QuestionID: kind.ID, Value: kind.Options[1].ID}}})
+		addTransaction(t, ctx, store, Transaction{Amount: -4000, Currency: "GBP", Merchant: "Hotel", Timestamp: timestamp, CategoryID: accommodation.ID})
+
+		cases := map[string]struct {
+			count func() (int, error)
+			want  int
+		}{
+			"category with transactions":    {func() (int, error) { return store.CountTransactionsForCategory(ctx, accommodation.ID) }, 2},
+			"category without transactions": {func() (int, error) { return store.CountTransactionsForCategory(ctx, food.ID) }, 0},
+			"answered question":             {func() (int, error) { return store.CountTransactionsAnsweringQuestion(ctx, nights.ID) }, 1},
+			"unanswered question":           {func() (int, error) { return store.CountTransactionsAnsweringQuestion(ctx, guests.ID) }, 0},
+			"chosen option":                 {func() (int, error) { return store.CountTransactionsAnsweringOption(ctx, kind.ID, kind.Options[1].ID) }, 1},
+			"unchosen option":               {func() (int, error) { return store.CountTransactionsAnsweringOption(ctx, kind.ID, kind.Options[0].ID) }, 0},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				got, err := c.count()
+				assertNoError(t, err)
+				assertNumbersEqual(t, got, c.want)
+			})
+		}
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		categoryStore, _, store := newStores()
+
+		category := addCategory(t, ctx, categoryStore, "accommodation", "")
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				transaction, err := store.AddTransaction(ctx, Transaction{Amount: int64(-i), Currency: "GBP", Merchant: "Pret", Timestamp: timestamp})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				store.CategoriseTransaction(ctx, transaction.ID, category.ID, nil)
+				store.GetTransaction(ctx, transaction.ID)
+				store.ListTransactions(ctx)
+				store.CountTransactionsForCategory(ctx, category.ID)
+			}(i)
+		}
+		wg.Wait()
+
+		count, err := store.CountTransactionsForCategory(ctx, category.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, count, concurrency)
+	})
+
+	t.Run("TransactionIDExists", func(t *testing.T) {
+		_, _, store := newStores()
+
+		transaction := addTransaction(t, ctx, store, Transaction{Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp})
+
+		exists, err := store.TransactionIDExists(ctx, transaction.ID)
+		assertNoError(t, err)
+		assertBool(t, exists, true)
+
+		exists, err = store.TransactionIDExists(ctx, "abcd")
+		assertNoError(t, err)
+		assertBool(t, exists, false)
+	})
+
+	t.Run("users only see their own transactions", func(t *testing.T) {
+		categoryStore, questionStore, store := newStores()
+
+		accommodation := addCategory(t, alice, categoryStore, "accommodation", "")
+		nights := addQuestion(t, alice, questionStore, accommodation.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+
+		transaction := addTransaction(t, alice, store, Transaction{Amount: -1200, Currency: "GBP", Merchant: "Hostel", Timestamp: timestamp,
+			CategoryID: accommodation.ID, Answers: []Answer{{QuestionID: nights.ID, Value: float64(2)}}})
+		imported, err := store.ImportTransaction(alice, Transaction{MonzoID: "tx_0001", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp})
+		assertNoError(t, err)
+		assertStringsEqual(t, transaction.Owner, "alice")
+		assertStringsEqual(t, imported.Owner, "alice")
+
+		list, err := store.ListTransactions(bob)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(list.Transactions), 0)
+
+		_, err = store.GetTransaction(bob, transaction.ID)
+		assertErrorIs(t, err, ErrNotFound)
+
+		exists, err := store.TransactionIDExists(bob, transaction.ID)
+		assertNoError(t, err)
+		assertBool(t, exists, false)
+
+		count, err := store.CountTransactionsForCategory(bob, accommodation.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, count, 0)
+
+		count, err = store.CountTransactionsAnsweringQuestion(bob, nights.ID)
+		assertNoError(t, err)
+		assertNumbersEqual(t, count, 0)
+
+		// Monzo IDs only need to be unique per user
+		_, err = store.ImportTransaction(bob, Transaction{MonzoID: "tx_0001", Amount: -350, Currency: "GBP", Merchant: "Pret", Timestamp: timestamp})
+		assertNoError(t, err)
+
+		// changes can't reach another user's transactions
+		_, err = store.CategoriseTransaction(bob, transaction.ID, "", nil)
+		assertErrorIs(t, err, ErrNotFound)
+		err = store.DeleteTransaction(bob, imported.ID)
+		assertErrorIs(t, err, ErrNotFound)
+		assertNoError(t, store.ReassignTransactions(bob, accommodation.ID, ""))
+		assertNoError(t, store.DeleteAnswersForQuestion(bob, nights.ID))
+
+		list, err = store.ListTransactions(alice)
+		assertNoError(t, err)
+		assertDeepEqual(t, list, TransactionList{Transactions: []Transaction{transaction, imported}})
+	})
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"math"
+	"unicode/utf8"
+)
+
+// Constraints narrow down the answers a Question accepts, beyond what its type allows
+// Required questions must be answered whenever a transaction is categorised, unless they have a Default,
+// which is the answer given to a question left unanswered
+// Min, Max, Step & Unit are for "number" questions, Step counting from Min (or 0),
+// and MaxLength, in characters, is for "text" questions
+type Constraints struct {
+	Required  bool        `json:"required,omitempty"`
+	Min       *float64    `json:"min,omitempty"`
+	Max       *float64    `json:"max,omitempty"`
+	Step      *float64    `json:"step,omitempty"`
+	Unit      string      `json:"unit,omitempty"`
+	MaxLength *int        `json:"maxLength,omitempty"`
+	Default   interface{} `json:"default,omitempty"`
+}
+
+// withoutEmptyConstraints returns nil rather than constraints that constrain nothing,
+// so questions without any are all stored the same way
+func withoutEmptyConstraints(c *Constraints) *Constraints {
+	if c == nil || (!c.Required && c.Min == nil && c.Max == nil && c.Step == nil && c.Unit == "" && c.MaxLength == nil && c.Default == nil) {
+		return nil
+	}
+	return c
+}
+
+// stepTolerance allows for numbers like 0.1 not being exact in floating point
+const stepTolerance = 1e-9
+
+// ValidateConstraints checks constraints make sense for the question they're on,
+// returning FieldErrors with every problem found, pointing into the request's constraints
+// The default is only checked once the rest are valid, as it's checked against them,
+// and for "string" & "multiselect" questions it must choose from options that already exist
+func ValidateConstraints(question Question, constraints Constraints) error {
+	var problems FieldErrors
+
+	notAllowed := func(field string) {
+		problems = append(problems, FieldError{ErrorConstraintNotAllowed, "/constraints/" + field, ""})
+	}
+
+	if question.Type != QuestionTypeNumber {
+		if constraints.Min != nil {
+			notAllowed("min")
+		}
+		if constraints.Max != nil {
+			notAllowed("max")
+		}
+		if constraints.Step != nil {
+			notAllowed("step")
+		}
+		if constraints.Unit != "" {
+			notAllowed("unit")
+		}
+	}
+
+	if question.Type != QuestionTypeText && constraints.MaxLength != nil {
+		notAllowed("maxLength")
+	}
+
+	if constraints.Min != nil && constraints.Max != nil && *constraints.Min > *constraints.Max {
+		problems = append(problems, FieldError{ErrorMinAboveMax, "/constraints/max", ""})
+	}
+
+	if constraints.Step != nil && *constraints.Step <= 0 {
+		problems = append(problems, FieldError{ErrorInvalidStep, "/constraints/step", ""})
+	}
+
+	if constraints.MaxLength != nil && *constraints.MaxLength < 1 {
+		problems = append(problems, FieldError{ErrorInvalidMaxLength, "/constraints/maxLength", ""})
+	}
+
+	if len(problems) == 0 && constraints.Default != nil {
+		question.Constraints = &constraints
+		if answerProblem(question, constraints.Default) != "" {
+			problems = append(problems, FieldError{ErrorInvalidDefault, "/constraints/default", ""})
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// answerProblem returns the title of what is wrong with value as an answer to the question,
+// or "" if it's a fine answer
+func answerProblem(question Question, value interface{}) string {
+	if !isValidAnswerValue(question, value) {
+		return ErrorInvalidAnswer
+	}
+
+	c := question.Constraints
+	if c == nil {
+		return ""
+	}
+
+	switch question.Type {
+	case QuestionTypeNumber:
+		number := value.(float64)
+		if (c.Min != nil && number < *c.Min) || (c.Max != nil && number > *c.Max) {
+			return ErrorAnswerOutOfRange
+		}
+		if c.Step != nil {
+			from := 0.0
+			if c.Min != nil {
+				from = *c.Min
+			}
+			steps := (number - from) / *c.Step
+			if math.Abs(steps-math.Round(steps)) > stepTolerance {
+				return ErrorAnswerOffStep
+			}
+		}
+	case QuestionTypeText:
+		if c.MaxLength != nil && utf8.RuneCountInString(value.(string)) > *c.MaxLength {
+			return ErrorAnswerTooLong
+		}
+	}
+
+	return ""
+}
+
+// WithDefaultAnswers returns answers along with the default answer of every question they leave unanswered,
+// for use once they've been validated, see ValidateAnswers
+// Only questions that are asked get their default, which may in turn lead to follow-ups being asked
+func WithDefaultAnswers(questions QuestionList, answers []Answer) []Answer {
+	answered := map[string]bool{}
+	for _, a := range answers {
+		answered[a.QuestionID] = true
+	}
+
+	withDefaults := append([]Answer{}, answers...)
+	for added := true; added; {
+		added = false
+		asked := askedQuestions(questions, withDefaults)
+
+		for _, q := range questions.Questions {
+			if !answered[q.ID] && asked[q.ID] && q.Constraints != nil && q.Constraints.Default != nil {
+				withDefaults = append(withDefaults, Answer{QuestionID: q.ID, Value: q.Constraints.Default})
+				answered[q.ID] = true
+				added = true
+			}
+		}
+	}
+	return withDefaults
+}
@@ -0,0 +1,122 @@
+package internal
+
+import "testing"
+
+func TestValidateConstraints(t *testing.T) {
+	number := func(f float64) *float64 { return &f }
+	length := func(n int) *int { return &n }
+
+	nights := Question{ID: "1", Title: "how many nights?", Type: "number"}
+	notes := Question{ID: "2", Title: "any notes?", Type: "text"}
+	kind := Question{ID: "3", Title: "what kind?", Type: "string", Options: OptionList{{ID: "a", Title: "hotel"}}}
+
+	t.Run("valid constraints", func(t *testing.T) {
+		cases := map[string]struct {
+			question    Question
+			constraints Constraints
+		}{
+			"number range, step & unit": {nights, Constraints{Required: true, Min: number(1), Max: number(30), Step: number(1), Unit: "nights"}},
+			"text max length":           {notes, Constraints{MaxLength: length(140)}},
+			"default within range":      {nights, Constraints{Min: number(1), Max: number(30), Default: float64(1)}},
+			"default choosing option":   {kind, Constraints{Default: "a"}},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				assertNoError(t, ValidateConstraints(c.question, c.constraints))
+			})
+		}
+	})
+
+	cases := map[string]struct {
+		question    Question
+		constraints Constraints
+		want        FieldErrors
+	}{
+		"range on a text question": {
+			question:    notes,
+			constraints: Constraints{Min: number(1), Unit: "words"},
+			want:        FieldErrors{{ErrorConstraintNotAllowed, "/constraints/min", ""}, {ErrorConstraintNotAllowed, "/constraints/unit", ""}},
+		},
+		"max length on a number question": {
+			question:    nights,
+			constraints: Constraints{MaxLength: length(3)},
+			want:        FieldErrors{{ErrorConstraintNotAllowed, "/constraints/maxLength", ""}},
+		},
+		"min above max": {
+			question:    nights,
+			constraints: Constraints{Min: number(30), Max: number(1)},
+			want:        FieldErrors{{ErrorMinAboveMax, "/constraints/max", ""}},
+		},
+		"step isn't above zero": {
+			question:    nights,
+			constraints: Constraints{Step: number(0)},
+			want:        FieldErrors{{ErrorInvalidStep, "/constraints/step", ""}},
+		},
+		"max length below one": {
+			question:    notes,
+			constraints: Constraints{MaxLength: length(0)},
+			want:        FieldErrors{{ErrorInvalidMaxLength, "/constraints/maxLength", ""}},
+		},
+		"default outside range": {
+			question:    nights,
+			constraints: Constraints{Min: number(1), Max: number(30), Default: float64(31)},
+			want:        FieldErrors{{ErrorInvalidDefault, "/constraints/default", ""}},
+		},
+		"default of the wrong type": {
+			question:    nights,
+			constraints: Constraints{Default: "one"},
+			want:        FieldErrors{{ErrorInvalidDefault, "/constraints/default", ""}},
+		},
+		"default choosing an unknown option": {
+			question:    kind,
+			constraints: Constraints{Default: "b"},
+			want:        FieldErrors{{ErrorInvalidDefault, "/constraints/default", ""}},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assertFieldErrors(t, ValidateConstraints(c.question, c.constraints), c.want)
+		})
+	}
+}
+
+func TestWithDefaultAnswers(t *testing.T) {
+	questions := QuestionList{
+		Questions: []Question{
+			Question{ID: "1", Title: "how many nights?", Type: "number", Constraints: &Constraints{Default: float64(1)}},
+			Question{ID: "2", Title: "how many guests?", Type: "number", Constraints: &Constraints{Default: float64(2)}},
+			Question{ID: "3", Title: "any notes?", Type: "text"},
+		},
+	}
+
+	got := WithDefaultAnswers(questions, []Answer{{QuestionID: "1", Value: float64(3)}})
+
+	want := []Answer{
+		{QuestionID: "1", Value: float64(3)},
+		{QuestionID: "2", Value: float64(2)},
+	}
+	assertDeepEqual(t, got, want)
+}
+
+func TestWithDefaultAnswersFollowUps(t *testing.T) {
+	questions := QuestionList{
+		Questions: []Question{
+			Question{ID: "1", Title: "star rating?", Type: "number", DependsOn: &Dependency{QuestionID: "2", OptionID: "a"},
+				Constraints: &Constraints{Default: float64(3)}},
+			Question{ID: "2", Title: "what kind?", Type: "string", Options: OptionList{{ID: "a", Title: "hotel"}},
+				Constraints: &Constraints{Default: "a"}},
+		},
+	}
+
+	t.Run("defaults of follow-ups asked due to other defaults are given", func(t *testing.T) {
+		got := WithDefaultAnswers(questions, nil)
+
+		want := []Answer{
+			{QuestionID: "2", Value: "a"},
+			{QuestionID: "1", Value: float64(3)},
+		}
+		assertDeepEqual(t, got, want)
+	})
+}
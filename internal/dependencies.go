@@ -0,0 +1,102 @@
+package internal
+
+// Dependency makes a question a follow-up, only asked once the question it depends on has an answer meeting it:
+// choosing OptionID, for "string" & "multiselect" questions,
+// a number no less than Min and no more than Max (either may be left out), for "number" questions,
+// or Equals, for "boolean" questions
+// A follow-up isn't asked when the question it depends on isn't, e.g. as it's been removed or isn't asked itself
+type Dependency struct {
+	QuestionID string   `json:"questionID"`
+	OptionID   string   `json:"optionID,omitempty"`
+	Min        *float64 `json:"min,omitempty"`
+	Max        *float64 `json:"max,omitempty"`
+	Equals     *bool    `json:"equals,omitempty"`
+}
+
+// withoutEmptyDependency returns nil rather than a dependency on nothing,
+// so an empty object can be sent to stop a question being a follow-up
+func withoutEmptyDependency(d *Dependency) *Dependency {
+	if d == nil || (d.QuestionID == "" && d.OptionID == "" && d.Min == nil && d.Max == nil && d.Equals == nil) {
+		return nil
+	}
+	return d
+}
+
+// isMetBy reports whether an answer to the question depended on meets the dependency
+func (d Dependency) isMetBy(value interface{}) bool {
+	if d.OptionID != "" {
+		return isOptionChosen(value, d.OptionID)
+	}
+
+	if d.Equals != nil {
+		boolean, isBoolean := value.(bool)
+		return isBoolean && boolean == *d.Equals
+	}
+
+	number, isNumber := value.(float64)
+	return isNumber && (d.Min == nil || number >= *d.Min) && (d.Max == nil || number <= *d.Max)
+}
+
+// validateDependency checks a dependency on a question makes sense for its type,
+// returning FieldErrors with every problem found, pointing into the request's dependsOn
+func validateDependency(dependsOn Question, d Dependency) error {
+	var problems FieldErrors
+
+	hasOption, hasRange, hasEquals := d.OptionID != "", d.Min != nil || d.Max != nil, d.Equals != nil
+
+	switch {
+	case typeHasOptions(dependsOn.Type) && hasOption && !hasRange && !hasEquals:
+		if dependsOn.Options.indexOf(d.OptionID) == -1 {
+			problems = append(problems, FieldError{ErrorDependencyOptionNotFound, "/dependsOn/optionID", ""})
+		}
+	case dependsOn.Type == QuestionTypeBoolean && hasEquals && !hasOption && !hasRange:
+		// either answer can meet it
+	case dependsOn.Type == QuestionTypeNumber && hasRange && !hasOption && !hasEquals:
+		if d.Min != nil && d.Max != nil && *d.Min > *d.Max {
+			problems = append(problems, FieldError{ErrorMinAboveMax, "/dependsOn/max", ""})
+		}
+	default:
+		problems = append(problems, FieldError{ErrorInvalidDependency, "/dependsOn", ""})
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// askedQuestions returns which of the questions are asked given the answers:
+// those that aren't follow-ups, and follow-ups whose dependency is met by the answer to a question that is itself asked
+func askedQuestions(questions QuestionList, answers []Answer) map[string]bool {
+	byID := map[string]Question{}
+	for _, q := range questions.Questions {
+		byID[q.ID] = q
+	}
+
+	values := map[string]interface{}{}
+	for _, a := range answers {
+		if _, answered := values[a.QuestionID]; !answered {
+			values[a.QuestionID] = a.Value
+		}
+	}
+
+	asked := map[string]bool{}
+	for _, q := range questions.Questions {
+		asked[q.ID] = isAsked(q, byID, values)
+	}
+	return asked
+}
+
+// isAsked follows the question's dependencies up to one that isn't a follow-up, checking each is met along the way
+// Dependencies are validated so they can't loop, but should they, none of the questions in the loop are asked
+func isAsked(q Question, byID map[string]Question, values map[string]interface{}) bool {
+	for steps := 0; q.DependsOn != nil; steps++ {
+		value, answered := values[q.DependsOn.QuestionID]
+		dependsOn, found := byID[q.DependsOn.QuestionID]
+		if !answered || !found || !q.DependsOn.isMetBy(value) || steps == len(byID) {
+			return false
+		}
+		q = dependsOn
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package internal
+
+import "testing"
+
+func TestAskedQuestions(t *testing.T) {
+	three, five := float64(3), float64(5)
+	yes := true
+
+	questions := QuestionList{
+		Questions: []Question{
+			Question{ID: "1", Title: "what kind?", Type: "string", Options: OptionList{
+				{ID: "a", Title: "hotel"},
+				{ID: "b", Title: "hostel"},
+			}},
+			Question{ID: "2", Title: "star rating?", Type: "number", DependsOn: &Dependency{QuestionID: "1", OptionID: "a"}},
+			Question{ID: "3", Title: "spa visited?", Type: "boolean", DependsOn: &Dependency{QuestionID: "2", Min: &three, Max: &five}},
+			Question{ID: "4", Title: "how many nights?", Type: "number"},
+			Question{ID: "5", Title: "was it a hotel?", Type: "boolean"},
+			Question{ID: "6", Title: "star rating?", Type: "number", DependsOn: &Dependency{QuestionID: "5", Equals: &yes}},
+		},
+	}
+
+	cases := map[string]struct {
+		answers []Answer
+		want    map[string]bool
+	}{
+		"no answers asks only questions that aren't follow-ups": {
+			answers: nil,
+			want:    map[string]bool{"1": true, "2": false, "3": false, "4": true, "5": true, "6": false},
+		},
+		"choosing the option asks its follow-up": {
+			answers: []Answer{{QuestionID: "1", Value: "a"}},
+			want:    map[string]bool{"1": true, "2": true, "3": false, "4": true, "5": true, "6": false},
+		},
+		"choosing another option doesn't": {
+			answers: []Answer{{QuestionID: "1", Value: "b"}},
+			want:    map[string]bool{"1": true, "2": false, "3": false, "4": true, "5": true, "6": false},
+		},
+		"a number within range asks its follow-up": {
+			answers: []Answer{{QuestionID: "1", Value: "a"}, {QuestionID: "2", Value: float64(4)}},
+			want:    map[string]bool{"1": true, "2": true, "3": true, "4": true, "5": true, "6": false},
+		},
+		"a number outside range doesn't": {
+			answers: []Answer{{QuestionID: "1", Value: "a"}, {QuestionID: "2", Value: float64(2)}},
+			want:    map[string]bool{"1": true, "2": true, "3": false, "4": true, "5": true, "6": false},
+		},
+		"a boolean answer equal to the condition asks its follow-up": {
+			answers: []Answer{{QuestionID: "5", Value: true}},
+			want:    map[string]bool{"1": true, "2": false, "3": false, "4": true, "5": true, "6": true},
+		},
+		"a boolean answer that isn't doesn't": {
+			answers: []Answer{{QuestionID: "5", Value: false}},
+			want:    map[string]bool{"1": true, "2": false, "3": false, "4": true, "5": true, "6": false},
+		},
+		"a follow-up to a question that isn't asked isn't asked": {
+			answers: []Answer{{QuestionID: "1", Value: "b"}, {QuestionID: "2", Value: float64(4)}},
+			want:    map[string]bool{"1": true, "2": false, "3": false, "4": true, "5": true, "6": false},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assertDeepEqual(t, askedQuestions(questions, c.answers), c.want)
+		})
+	}
+}
+
+func TestValidateDependency(t *testing.T) {
+	min, max := float64(5), float64(1)
+	yes := true
+
+	kind := Question{ID: "1", Title: "what kind?", Type: "string", Options: OptionList{{ID: "a", Title: "hotel"}}}
+	nights := Question{ID: "2", Title: "how many nights?", Type: "number"}
+	breakfast := Question{ID: "3", Title: "breakfast included?", Type: "boolean"}
+
+	cases := map[string]struct {
+		dependsOn  Question
+		dependency Dependency
+		want       FieldErrors
+	}{
+		"option of a question with options": {
+			dependsOn:  kind,
+			dependency: Dependency{QuestionID: "1", OptionID: "a"},
+		},
+		"range of a number question": {
+			dependsOn:  nights,
+			dependency: Dependency{QuestionID: "2", Min: &max},
+		},
+		"answer to a boolean question": {
+			dependsOn:  breakfast,
+			dependency: Dependency{QuestionID: "3", Equals: &yes},
+		},
+		"option that doesn't exist": {
+			dependsOn:  kind,
+			dependency: Dependency{QuestionID: "1", OptionID: "b"},
+			want:       FieldErrors{{ErrorDependencyOptionNotFound, "/dependsOn/optionID", ""}},
+		},
+		"range of a question with options": {
+			dependsOn:  kind,
+			dependency: Dependency{QuestionID: "1", Min: &min},
+			want:       FieldErrors{{ErrorInvalidDependency, "/dependsOn", ""}},
+		},
+		"option and range both": {
+			dependsOn:  nights,
+			dependency: Dependency{QuestionID: "2", OptionID: "a", Min: &min},
+			want:       FieldErrors{{ErrorInvalidDependency, "/dependsOn", ""}},
+		},
+		"no condition": {
+			dependsOn:  nights,
+			dependency: Dependency{QuestionID: "2"},
+			want:       FieldErrors{{ErrorInvalidDependency, "/dependsOn", ""}},
+		},
+		"option of a boolean question": {
+			dependsOn:  breakfast,
+			dependency: Dependency{QuestionID: "3", OptionID: "a"},
+			want:       FieldErrors{{ErrorInvalidDependency, "/dependsOn", ""}},
+		},
+		"equals of a number question": {
+			dependsOn:  nights,
+			dependency: Dependency{QuestionID: "2", Equals: &yes},
+			want:       FieldErrors{{ErrorInvalidDependency, "/dependsOn", ""}},
+		},
+		"a type that can't be depended on": {
+			dependsOn:  Question{ID: "4", Title: "any notes?", Type: "text"},
+			dependency: Dependency{QuestionID: "4", Equals: &yes},
+			want:       FieldErrors{{ErrorInvalidDependency, "/dependsOn", ""}},
+		},
+		"min above max": {
+			dependsOn:  nights,
+			dependency: Dependency{QuestionID: "2", Min: &min, Max: &max},
+			want:       FieldErrors{{ErrorMinAboveMax, "/dependsOn/max", ""}},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateDependency(c.dependsOn, c.dependency)
+			if c.want == nil {
+				assertNoError(t, err)
+				return
+			}
+			assertFieldErrors(t, err, c.want)
+		})
+	}
+}
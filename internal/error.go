@@ -1,8 +1,94 @@
 package internal
 
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNotFound & ErrConflict are wrapped by the errors stores return when they can't do as asked,
+// check for them with errors.Is, any other error is a failure of the store itself
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflict")
+)
+
+// StoreError wraps ErrNotFound or ErrConflict,
+// with the title of the error (one of those below) to report to the user
+// Pointer locates the field of the request at fault, if there is one, see FieldError
+type StoreError struct {
+	Err     error
+	Title   string
+	Pointer string
+}
+
+func (e *StoreError) Error() string {
+	return e.Title
+}
+
+func (e *StoreError) Unwrap() error {
+	return e.Err
+}
+
+func notFound(title string) error {
+	return &StoreError{Err: ErrNotFound, Title: title}
+}
+
+func conflict(title string) error {
+	return &StoreError{Err: ErrConflict, Title: title}
+}
+
+// fieldConflict is a conflict caused by the field of the request at pointer
+func fieldConflict(title, pointer string) error {
+	return &StoreError{Err: ErrConflict, Title: title, Pointer: pointer}
+}
+
+// InUseError is returned when removing something that is still referenced,
+// it wraps ErrConflict, counting the references by kind
+type InUseError struct {
+	Title        string
+	Categories   int
+	Transactions int
+	Questions    int
+}
+
+func (e *InUseError) Error() string {
+	return e.Title
+}
+
+func (e *InUseError) Unwrap() error {
+	return ErrConflict
+}
+
+// FieldError is a problem with one field of a request
+// Pointer is a JSON Pointer (RFC 6901) to the field in the request body, e.g. /answers/0/value
+// Detail says more where the pointer alone can't, e.g. which question a missing answer is to, and is usually empty
+type FieldError struct {
+	Title   string
+	Pointer string
+	Detail  string
+}
+
+func (e FieldError) Error() string {
+	return e.Title
+}
+
+// FieldErrors is every problem found validating a request, not just the first,
+// so they can all be reported at once
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	titles := make([]string, len(e))
+	for i, fe := range e {
+		titles[i] = fe.Title
+	}
+	return strings.Join(titles, "; ")
+}
+
 const (
 	// Generic
-	ErrorFieldMissing = "a required field is missing from the request"
+	ErrorFieldMissing           = "a required field is missing from the request"
+	ErrorInvalidCascade         = "cascade is invalid"
+	ErrorCascadeAndReassignBoth = "cascade and reassignTo cannot be combined"
 
 	// Category
 	ErrorCategoryNotFound      = "categoryID not found"
@@ -10,6 +96,13 @@ const (
 	ErrorInvalidCategoryName   = "name is invalid"
 	ErrorParentIDNotFound      = "parentID not found"
 	ErrorCategoryTooNested     = "category would be too nested"
+	ErrorCategoryCycle         = "category is its own ancestor"
+	ErrorParentIDIsDescendant  = "parentID is the category or one of its subcategories"
+	ErrorCategoryInUse         = "category is used by transactions"
+	ErrorCategoryHasChildren   = "category has subcategories"
+	ErrorInheritedQuestionUsed = "category would stop inheriting questions that are used"
+	ErrorReassignToNotFound    = "reassignTo category not found"
+	ErrorReassignToSelf        = "reassignTo is the category being removed"
 
 	//Question
 	ErrorQuestionNotFound               = "question not found"
@@ -21,5 +114,115 @@ const (
 	ErrorOptionsInvalid                 = "options is invalid"
 	ErrorOptionEmpty                    = "option is empty"
 	ErrorDuplicateOption                = "options list has a duplicate"
+	ErrorOptionNotFound                 = "option not found"
+	ErrorQuestionHasNoOptions           = "question type does not have options"
+	ErrorOptionsNotAllowed              = "options are only allowed for string & multiselect questions"
+	ErrorConstraintNotAllowed           = "constraint does not apply to the question's type"
+	ErrorMinAboveMax                    = "max is below min"
+	ErrorInvalidStep                    = "step is not above zero"
+	ErrorInvalidMaxLength               = "maxLength is below one"
+	ErrorInvalidDefault                 = "default is not a valid answer to the question"
+	ErrorOptionInUse                    = "option is chosen by transactions"
 	ErrorQuestionDoesntBelongToCategory = "question does not belong to category"
+	ErrorQuestionInUse                  = "question is answered by transactions"
+	ErrorQuestionNotInherited           = "question is not inherited by the category"
+	ErrorQuestionAlreadyOverridden      = "question is already overridden in the category"
+	ErrorQuestionNotHidden              = "question is not hidden in the category"
+	ErrorDependencyNotFound             = "dependsOn questionID not found in category"
+	ErrorDependencyOptionNotFound       = "dependsOn optionID not found"
+	ErrorInvalidDependency              = "dependsOn needs one of an optionID, a min and/or max, or equals, to suit the question's type"
+	ErrorDependencyCycle                = "dependsOn would make the question depend on itself"
+
+	// Transaction
+	ErrorTransactionNotFound    = "transaction not found"
+	ErrorDuplicateMonzoID       = "monzoID is a duplicate"
+	ErrorCurrencyEmpty          = "currency is empty"
+	ErrorTimestampEmpty         = "timestamp is empty"
+	ErrorDuplicateAnswer        = "answers list has a duplicate question"
+	ErrorAnswerQuestionNotFound = "answer questionID not found in category"
+	ErrorInvalidAnswer          = "answer value is invalid for question type"
+	ErrorAnswerOutOfRange       = "answer is outside the question's min & max"
+	ErrorAnswerOffStep          = "answer is not a whole number of steps from the question's min"
+	ErrorAnswerTooLong          = "answer is longer than the question's maxLength"
+	ErrorAnswerRequired         = "a required question is unanswered"
+	ErrorAnswerNotAsked         = "answer is to a follow-up question the other answers mean isn't asked"
+
+	// Report
+	ErrorInvalidFrom    = "from is invalid"
+	ErrorInvalidTo      = "to is invalid"
+	ErrorFromAfterTo    = "from is after to"
+	ErrorInvalidGroupBy = "groupBy is invalid"
 )
+
+// errorCodes gives each error above a stable, machine-readable code,
+// so clients can tell errors apart without matching on titles, which may be reworded
+var errorCodes = map[string]string{
+	ErrorFieldMissing:           "field_missing",
+	ErrorInvalidCascade:         "invalid_cascade",
+	ErrorCascadeAndReassignBoth: "cascade_and_reassign_to",
+
+	ErrorCategoryNotFound:      "category_not_found",
+	ErrorDuplicateCategoryName: "duplicate_category_name",
+	ErrorInvalidCategoryName:   "invalid_category_name",
+	ErrorParentIDNotFound:      "parent_not_found",
+	ErrorCategoryCycle:         "category_cycle",
+	ErrorParentIDIsDescendant:  "parent_id_is_descendant",
+	ErrorCategoryTooNested:     "category_too_nested",
+	ErrorCategoryInUse:         "category_in_use",
+	ErrorCategoryHasChildren:   "category_has_children",
+	ErrorInheritedQuestionUsed: "inherited_question_used",
+	ErrorReassignToNotFound:    "reassign_to_not_found",
+	ErrorReassignToSelf:        "reassign_to_self",
+
+	ErrorQuestionNotFound:               "question_not_found",
+	ErrorTitleEmpty:                     "title_empty",
+	ErrorInvalidTitle:                   "invalid_title",
+	ErrorDuplicateTitle:                 "duplicate_title",
+	ErrorTypeEmpty:                      "type_empty",
+	ErrorInvalidType:                    "invalid_type",
+	ErrorOptionsInvalid:                 "invalid_options",
+	ErrorOptionEmpty:                    "option_empty",
+	ErrorDuplicateOption:                "duplicate_option",
+	ErrorOptionNotFound:                 "option_not_found",
+	ErrorQuestionHasNoOptions:           "question_has_no_options",
+	ErrorOptionsNotAllowed:              "options_not_allowed",
+	ErrorConstraintNotAllowed:           "constraint_not_allowed",
+	ErrorMinAboveMax:                    "min_above_max",
+	ErrorInvalidStep:                    "invalid_step",
+	ErrorInvalidMaxLength:               "invalid_max_length",
+	ErrorInvalidDefault:                 "invalid_default",
+	ErrorOptionInUse:                    "option_in_use",
+	ErrorQuestionDoesntBelongToCategory: "question_not_in_category",
+	ErrorQuestionInUse:                  "question_in_use",
+	ErrorQuestionNotInherited:           "question_not_inherited",
+	ErrorQuestionAlreadyOverridden:      "question_already_overridden",
+	ErrorQuestionNotHidden:              "question_not_hidden",
+	ErrorDependencyNotFound:             "dependency_not_found",
+	ErrorDependencyOptionNotFound:       "dependency_option_not_found",
+	ErrorInvalidDependency:              "invalid_dependency",
+	ErrorDependencyCycle:                "dependency_cycle",
+
+	ErrorTransactionNotFound:    "transaction_not_found",
+	ErrorDuplicateMonzoID:       "duplicate_monzo_id",
+	ErrorCurrencyEmpty:          "currency_empty",
+	ErrorTimestampEmpty:         "timestamp_empty",
+	ErrorDuplicateAnswer:        "duplicate_answer",
+	ErrorAnswerQuestionNotFound: "answer_question_not_found",
+	ErrorInvalidAnswer:          "invalid_answer",
+	ErrorAnswerOutOfRange:       "answer_out_of_range",
+	ErrorAnswerOffStep:          "answer_off_step",
+	ErrorAnswerTooLong:          "answer_too_long",
+	ErrorAnswerRequired:         "answer_required",
+	ErrorAnswerNotAsked:         "answer_not_asked",
+
+	ErrorInvalidFrom:    "invalid_from",
+	ErrorInvalidTo:      "invalid_to",
+	ErrorFromAfterTo:    "from_after_to",
+	ErrorInvalidGroupBy: "invalid_group_by",
+}
+
+// ErrorCode returns the code for an error title above, and false if it has none
+func ErrorCode(title string) (string, bool) {
+	code, ok := errorCodes[title]
+	return code, ok
+}
@@ -0,0 +1,29 @@
+package internal
+
+import "testing"
+
+func TestErrorCodes(t *testing.T) {
+	t.Run("codes are unique", func(t *testing.T) {
+		titles := make(map[string]string)
+		for title, code := range errorCodes {
+			if other, ok := titles[code]; ok {
+				t.Errorf("'%s' and '%s' share the code '%s'", title, other, code)
+			}
+			titles[code] = title
+		}
+	})
+
+	t.Run("unknown titles have no code", func(t *testing.T) {
+		_, ok := ErrorCode("not an error title")
+		assertBool(t, ok, false)
+	})
+}
+
+func TestFieldErrors(t *testing.T) {
+	err := FieldErrors{
+		{ErrorTitleEmpty, "/title", ""},
+		{ErrorTypeEmpty, "/type", ""},
+	}
+
+	assertStringsEqual(t, err.Error(), "title is empty; type is empty")
+}
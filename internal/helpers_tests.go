@@ -1,6 +1,11 @@
 package internal
 
 import (
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -35,3 +40,42 @@ func assertIsXid(t *testing.T, s string) {
 		t.Fatalf("got ID '%s' which isn't an xid", s)
 	}
 }
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("got error '%v' wanted none", err)
+	}
+}
+
+func assertErrorIs(t *testing.T, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Errorf("got error '%v' wanted '%v'", got, want)
+	}
+}
+
+func newTempFilePath(t *testing.T, name string) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "practising-go-tdd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, name), func() { os.RemoveAll(dir) }
+}
+
+func assertBool(t *testing.T, got, want bool) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %t wanted %t", got, want)
+	}
+}
+
+func newTestSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := NewSQLiteDB(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
@@ -1,20 +1,43 @@
 package internal
 
-import "regexp"
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/rs/xid"
+)
 
 // QuestionStore is an interface that when implemented,
 // provides methods for manipulating a store of questions,
 // including some helper functions for querying the store
+// Every method acts only on the questions owned by the user in ctx (see WithUser)
+// Errors wrap ErrNotFound when the question (or option) doesn't exist,
+// and ErrConflict when its category already has a question with the title,
+// or the question already has an option with the title
+// HideQuestion hides a question in a category, doing nothing if it's already hidden there,
+// and UnhideQuestion's errors also wrap ErrNotFound when the question isn't hidden there
+// UpdateQuestion applies every change in a QuestionPatchRequest or, if any can't be made, none of them
 type QuestionStore interface {
-	ListQuestionsForCategory(categoryID string) QuestionList
-	GetQuestion(questionID string) Question
-	AddQuestion(categoryID string, question QuestionPostRequest) Question
-	RenameQuestion(questionID, questionTitle string) Question
-	DeleteQuestion(questionID string)
+	ListQuestions(ctx context.Context) (QuestionList, error)
+	ListQuestionsForCategory(ctx context.Context, categoryID string) (QuestionList, error)
+	GetQuestion(ctx context.Context, questionID string) (Question, error)
+	AddQuestion(ctx context.Context, categoryID string, question QuestionPostRequest) (Question, error)
+	RenameQuestion(ctx context.Context, questionID, questionTitle string) (Question, error)
+	SetQuestionConstraints(ctx context.Context, questionID string, constraints *Constraints) (Question, error)
+	SetQuestionDependency(ctx context.Context, questionID string, dependsOn *Dependency) (Question, error)
+	UpdateQuestion(ctx context.Context, questionID string, update QuestionPatchRequest) (Question, error)
+	DeleteQuestion(ctx context.Context, questionID string) error
+	DeleteQuestionsForCategory(ctx context.Context, categoryID string) error
+	HideQuestion(ctx context.Context, questionID, categoryID string) (Question, error)
+	UnhideQuestion(ctx context.Context, questionID, categoryID string) (Question, error)
+	AddOption(ctx context.Context, questionID, optionTitle string) (Option, error)
+	RenameOption(ctx context.Context, questionID, optionID, optionTitle string) (Option, error)
+	DeleteOption(ctx context.Context, questionID, optionID string) error
 
-	QuestionIDExists(questionID string) bool
-	QuestionTitleExists(categoryID, questionTitle string) bool
-	QuestionBelongsToCategory(questionID, categoryID string) bool
+	QuestionIDExists(ctx context.Context, questionID string) (bool, error)
+	QuestionTitleExists(ctx context.Context, categoryID, questionTitle string) (bool, error)
+	QuestionBelongsToCategory(ctx context.Context, questionID, categoryID string) (bool, error)
 }
 
 // QuestionList stores multiple Categorys
@@ -24,24 +47,47 @@ type QuestionList struct {
 
 // Question stores all possible question attributes
 // The structure implements the adjacency list pattern
-// and also has a Type field (currently only "number" or "string"),
-// and Options for string Questions
+// and also has a Type field (one of the QuestionType constants),
+// and Options for the types answered by choosing them, "string" & "multiselect"
+// Constraints are nil for a question accepting any answer of its type,
+// and DependsOn is nil for a question that isn't a follow-up to another
+// Questions are inherited by their category's subcategories, see Service.ListEffectiveQuestions,
+// Overrides is the ID of an inherited question this one is asked in place of,
+// and HiddenIn the subcategories that don't inherit this one
+// Owner is the user the question belongs to, see WithUser
 type Question struct {
-	ID         string     `json:"id"`
-	Title      string     `json:"title"`
-	CategoryID string     `json:"categoryID"`
-	Type       string     `json:"type"`
-	Options    OptionList `json:"options"`
+	ID          string       `json:"id"`
+	Title       string       `json:"title"`
+	CategoryID  string       `json:"categoryID"`
+	Type        string       `json:"type"`
+	Options     OptionList   `json:"options"`
+	Constraints *Constraints `json:"constraints,omitempty"`
+	DependsOn   *Dependency  `json:"dependsOn,omitempty"`
+	Overrides   string       `json:"overrides,omitempty"`
+	HiddenIn    []string     `json:"hiddenIn,omitempty"`
+	Owner       string       `json:"owner,omitempty"`
 }
 
 // QuestionPostRequest is a Question with no ID or CategoryID,
 // as CategoryID is obtained from the reqwuest path
 // Used for sending new Questions to the server
 // Options is a string to allow an empty list to be sent
+// Overrides, if given, is the ID of the inherited question the new one is asked in place of
 type QuestionPostRequest struct {
-	Title   string    `json:"title"`
-	Type    string    `json:"type"`
-	Options *[]string `json:"options"`
+	Title       string       `json:"title"`
+	Type        string       `json:"type"`
+	Options     *[]string    `json:"options"`
+	Constraints *Constraints `json:"constraints"`
+	DependsOn   *Dependency  `json:"dependsOn"`
+	Overrides   string       `json:"overrides"`
+}
+
+// QuestionPatchRequest changes a question's title, constraints and/or dependency, whichever are given
+// Constraints & DependsOn replace any the question had, an empty object removing them
+type QuestionPatchRequest struct {
+	Title       *string      `json:"title"`
+	Constraints *Constraints `json:"constraints"`
+	DependsOn   *Dependency  `json:"dependsOn"`
 }
 
 // OptionList stores multiple Options
@@ -53,9 +99,68 @@ type Option struct {
 	Title string `json:"title"`
 }
 
+// newOptionList gives each of the titles an ID, titles being nil is no options
+func newOptionList(titles *[]string) OptionList {
+	options := OptionList{}
+	if titles == nil {
+		return options
+	}
+
+	for _, title := range *titles {
+		options = append(options, Option{
+			ID:    xid.New().String(),
+			Title: title,
+		})
+	}
+	return options
+}
+
+func (o OptionList) indexOf(optionID string) int {
+	for i, option := range o {
+		if option.ID == optionID {
+			return i
+		}
+	}
+	return -1
+}
+
+// optionTitleExists reports whether any of the options, other than exceptID, is titled optionTitle
+func optionTitleExists(options OptionList, optionTitle, exceptID string) bool {
+	for _, o := range options {
+		if o.Title == optionTitle && o.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// The types of question, each answered by a different kind of value, see isValidAnswerValue
+const (
+	QuestionTypeString      = "string"      // one of the question's options, by ID
+	QuestionTypeNumber      = "number"      // any number
+	QuestionTypeBoolean     = "boolean"     // true or false
+	QuestionTypeDate        = "date"        // a calendar date, e.g. "2019-03-01"
+	QuestionTypeMoney       = "money"       // an amount in minor units with its currency, e.g. {"amount": 1250, "currency": "GBP"}
+	QuestionTypeText        = "text"        // any text that isn't empty
+	QuestionTypeMultiSelect = "multiselect" // one or more of the question's options, by ID
+)
+
 const questionTitleRegex = `^[a-zA-Z]+[a-zA-Z ]+?[a-zA-Z]+\??$`
 
-var possibleOptionTypes = []string{"string", "number"}
+var possibleOptionTypes = []string{
+	QuestionTypeString,
+	QuestionTypeNumber,
+	QuestionTypeBoolean,
+	QuestionTypeDate,
+	QuestionTypeMoney,
+	QuestionTypeText,
+	QuestionTypeMultiSelect,
+}
+
+// typeHasOptions reports whether questions of the type are answered by choosing from their options
+func typeHasOptions(questionType string) bool {
+	return questionType == QuestionTypeString || questionType == QuestionTypeMultiSelect
+}
 
 func IsValidQuestionTitle(title string) bool {
 	isValid := true
@@ -84,3 +189,74 @@ func IsValidOptionType(providedType string) bool {
 
 	return isValid
 }
+
+// ValidateQuestionPostRequest checks a new question's title, type & options,
+// returning FieldErrors with every problem found
+func ValidateQuestionPostRequest(question QuestionPostRequest) error {
+	var problems FieldErrors
+
+	if question.Title == "" {
+		problems = append(problems, FieldError{ErrorTitleEmpty, "/title", ""})
+	} else if !IsValidQuestionTitle(question.Title) {
+		problems = append(problems, FieldError{ErrorInvalidTitle, "/title", ""})
+	}
+
+	if question.Type == "" {
+		problems = append(problems, FieldError{ErrorTypeEmpty, "/type", ""})
+	} else if !IsValidOptionType(question.Type) {
+		problems = append(problems, FieldError{ErrorInvalidType, "/type", ""})
+	}
+
+	// only questions answered by choosing an option may have them,
+	// a type that is itself invalid is already reported, so not reported against too
+	if question.Options != nil && IsValidOptionType(question.Type) && !typeHasOptions(question.Type) {
+		problems = append(problems, FieldError{ErrorOptionsNotAllowed, "/options", ""})
+	} else if question.Options != nil {
+		for i, opt := range *question.Options {
+			if opt == "" {
+				problems = append(problems, FieldError{ErrorOptionEmpty, fmt.Sprintf("/options/%d", i), ""})
+			}
+		}
+
+		for _, i := range duplicateIndexes(*question.Options) {
+			problems = append(problems, FieldError{ErrorDuplicateOption, fmt.Sprintf("/options/%d", i), ""})
+		}
+	}
+
+	// the question's options have no IDs yet, so a default can't choose one
+	if question.Constraints != nil && IsValidOptionType(question.Type) {
+		if err := ValidateConstraints(Question{Type: question.Type}, *question.Constraints); err != nil {
+			problems = append(problems, err.(FieldErrors)...)
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// isHiddenIn reports whether the question is hidden in the category
+func (q Question) isHiddenIn(categoryID string) bool {
+	for _, id := range q.HiddenIn {
+		if id == categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// duplicateIndexes returns the index of every string that repeats an earlier one
+func duplicateIndexes(values []string) []int {
+	var duplicates []int
+
+	seen := make(map[string]bool)
+	for i, str := range values {
+		if seen[str] {
+			duplicates = append(duplicates, i)
+		}
+		seen[str] = true
+	}
+
+	return duplicates
+}
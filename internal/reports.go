@@ -0,0 +1,264 @@
+package internal
+
+import (
+	"sort"
+	"time"
+)
+
+// Ways a SpendingReport can group transactions
+// GroupByCategory includes subcategory spending in each parent's total,
+// GroupByParent only reports top-level categories
+const (
+	GroupByCategory = "category"
+	GroupByParent   = "parent"
+	GroupByMonth    = "month"
+)
+
+var possibleGroupBys = []string{GroupByCategory, GroupByParent, GroupByMonth}
+
+// uncategorisedKey groups transactions without a category
+const uncategorisedKey = ""
+
+// SpendingReport totals transactions in a time range by group,
+// and counts how often each option of each string & multiselect question was chosen
+type SpendingReport struct {
+	From         *time.Time            `json:"from,omitempty"`
+	To           *time.Time            `json:"to,omitempty"`
+	GroupBy      string                `json:"groupBy"`
+	Groups       []SpendingGroup       `json:"groups"`
+	OptionCounts []QuestionOptionCount `json:"optionCounts"`
+}
+
+// SpendingGroup is a category or month's spending
+// Key is a category ID ("" for uncategorised) or a month e.g. "2019-03"
+// Totals are summed per currency, as amounts in different currencies can't be added
+type SpendingGroup struct {
+	Key    string           `json:"key"`
+	Name   string           `json:"name,omitempty"`
+	Count  int              `json:"count"`
+	Totals map[string]int64 `json:"totals"`
+}
+
+// QuestionOptionCount counts the answers choosing each of a question's options
+type QuestionOptionCount struct {
+	QuestionID string        `json:"questionID"`
+	Title      string        `json:"title"`
+	CategoryID string        `json:"categoryID"`
+	Options    []OptionCount `json:"options"`
+}
+
+// OptionCount counts the answers for one option
+type OptionCount struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Count int    `json:"count"`
+}
+
+// reportDateFormat is accepted for a SpendingReportRequest's from & to as well as RFC3339
+const reportDateFormat = "2006-01-02"
+
+// SpendingReportRequest asks for a SpendingReport, as given e.g. in a query string
+// From & To are dates or RFC3339 timestamps, or empty to leave that end of the range open,
+// and GroupBy defaults to GroupByCategory when empty
+type SpendingReportRequest struct {
+	From    string
+	To      string
+	GroupBy string
+}
+
+// ParseSpendingReportRequest parses the range & grouping of a SpendingReport,
+// returning FieldErrors with every problem found
+func ParseSpendingReportRequest(request SpendingReportRequest) (from, to *time.Time, groupBy string, err error) {
+	var problems FieldErrors
+
+	from, ok := parseReportTime(request.From)
+	if !ok {
+		problems = append(problems, FieldError{ErrorInvalidFrom, "", `"from" is not a date or RFC3339 timestamp`})
+	}
+
+	to, ok = parseReportTime(request.To)
+	if !ok {
+		problems = append(problems, FieldError{ErrorInvalidTo, "", `"to" is not a date or RFC3339 timestamp`})
+	}
+
+	if from != nil && to != nil && from.After(*to) {
+		problems = append(problems, FieldError{ErrorFromAfterTo, "", ""})
+	}
+
+	groupBy = request.GroupBy
+	if groupBy == "" {
+		groupBy = GroupByCategory
+	}
+
+	if !IsValidGroupBy(groupBy) {
+		problems = append(problems, FieldError{ErrorInvalidGroupBy, "", ""})
+	}
+
+	if len(problems) > 0 {
+		return nil, nil, "", problems
+	}
+	return from, to, groupBy, nil
+}
+
+// parseReportTime parses an optional from/to, a date is midnight UTC at its start
+func parseReportTime(value string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+
+	for _, layout := range []string{time.RFC3339, reportDateFormat} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, true
+		}
+	}
+
+	return nil, false
+}
+
+// IsValidGroupBy checks that a SpendingReport can be grouped that way
+func IsValidGroupBy(groupBy string) bool {
+	for _, g := range possibleGroupBys {
+		if groupBy == g {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSpendingReport builds a SpendingReport of the transactions from (inclusive) to (exclusive),
+// either may be nil to leave that end of the range open
+func NewSpendingReport(categories CategoryList, questions QuestionList, transactions TransactionList, from, to *time.Time, groupBy string) SpendingReport {
+	report := SpendingReport{
+		From:         from,
+		To:           to,
+		GroupBy:      groupBy,
+		Groups:       []SpendingGroup{},
+		OptionCounts: []QuestionOptionCount{},
+	}
+
+	var inRange []Transaction
+	for _, t := range transactions.Transactions {
+		if from != nil && t.Timestamp.Before(*from) {
+			continue
+		}
+		if to != nil && !t.Timestamp.Before(*to) {
+			continue
+		}
+		inRange = append(inRange, t)
+	}
+
+	groups := map[string]*SpendingGroup{}
+	addTo := func(key string, t Transaction) {
+		group, ok := groups[key]
+		if !ok {
+			group = &SpendingGroup{Key: key, Totals: map[string]int64{}}
+			groups[key] = group
+		}
+		group.Count++
+		group.Totals[t.Currency] += t.Amount
+	}
+
+	parents := map[string]string{}
+	for _, c := range categories.Categories {
+		parents[c.ID] = c.ParentID
+	}
+
+	for _, t := range inRange {
+		switch groupBy {
+		case GroupByMonth:
+			addTo(t.Timestamp.UTC().Format("2006-01"), t)
+		case GroupByParent:
+			addTo(topLevelAncestor(parents, t.CategoryID), t)
+		default:
+			for _, categoryID := range selfAndAncestors(parents, t.CategoryID) {
+				addTo(categoryID, t)
+			}
+		}
+	}
+
+	if groupBy == GroupByMonth {
+		for _, group := range groups {
+			report.Groups = append(report.Groups, *group)
+		}
+		sort.Slice(report.Groups, func(i, j int) bool {
+			return report.Groups[i].Key < report.Groups[j].Key
+		})
+	} else {
+		// keep the order categories are listed in, with uncategorised last
+		for _, c := range categories.Categories {
+			if group, ok := groups[c.ID]; ok {
+				group.Name = c.Name
+				report.Groups = append(report.Groups, *group)
+			}
+		}
+		if group, ok := groups[uncategorisedKey]; ok {
+			report.Groups = append(report.Groups, *group)
+		}
+	}
+
+	report.OptionCounts = countOptions(questions, inRange)
+
+	return report
+}
+
+// selfAndAncestors returns the category ID followed by its ancestors' IDs,
+// stopping at a missing parent or a cycle
+func selfAndAncestors(parents map[string]string, categoryID string) []string {
+	if categoryID == uncategorisedKey {
+		return []string{uncategorisedKey}
+	}
+
+	ids := []string{}
+	seen := map[string]bool{}
+	for id := categoryID; id != "" && !seen[id]; id = parents[id] {
+		if _, ok := parents[id]; !ok {
+			break
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return []string{uncategorisedKey}
+	}
+	return ids
+}
+
+func topLevelAncestor(parents map[string]string, categoryID string) string {
+	ids := selfAndAncestors(parents, categoryID)
+	return ids[len(ids)-1]
+}
+
+func countOptions(questions QuestionList, transactions []Transaction) []QuestionOptionCount {
+	counts := map[string]map[string]int{}
+	for _, t := range transactions {
+		for _, a := range t.Answers {
+			for _, optionID := range chosenOptions(a.Value) {
+				if counts[a.QuestionID] == nil {
+					counts[a.QuestionID] = map[string]int{}
+				}
+				counts[a.QuestionID][optionID]++
+			}
+		}
+	}
+
+	result := []QuestionOptionCount{}
+	for _, q := range questions.Questions {
+		if !typeHasOptions(q.Type) {
+			continue
+		}
+
+		questionCount := QuestionOptionCount{
+			QuestionID: q.ID,
+			Title:      q.Title,
+			CategoryID: q.CategoryID,
+			Options:    []OptionCount{},
+		}
+		for _, o := range q.Options {
+			questionCount.Options = append(questionCount.Options, OptionCount{o.ID, o.Title, counts[q.ID][o.ID]})
+		}
+		result = append(result, questionCount)
+	}
+
+	return result
+}
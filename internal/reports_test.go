@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSpendingReport(t *testing.T) {
+	categories := CategoryList{
+		Categories: []Category{
+			Category{ID: "1", Name: "food and drink", ParentID: ""},
+			Category{ID: "2", Name: "groceries", ParentID: "1"},
+			Category{ID: "3", Name: "eating out", ParentID: "1"},
+			Category{ID: "4", Name: "travel", ParentID: ""},
+		},
+	}
+	questions := QuestionList{
+		Questions: []Question{
+			Question{ID: "q1", Title: "which meal?", CategoryID: "3", Type: "string", Options: OptionList{
+				{ID: "a", Title: "breakfast"},
+				{ID: "b", Title: "lunch"},
+			}},
+			Question{ID: "q2", Title: "how many people?", CategoryID: "3", Type: "number"},
+			Question{ID: "q3", Title: "which drinks?", CategoryID: "3", Type: "multiselect", Options: OptionList{
+				{ID: "c", Title: "coffee"},
+				{ID: "d", Title: "juice"},
+			}},
+		},
+	}
+
+	march := time.Date(2019, time.March, 1, 12, 0, 0, 0, time.UTC)
+	april := time.Date(2019, time.April, 1, 12, 0, 0, 0, time.UTC)
+	transactions := TransactionList{
+		Transactions: []Transaction{
+			Transaction{ID: "t1", Amount: -1000, Currency: "GBP", Timestamp: march, CategoryID: "2"},
+			Transaction{ID: "t2", Amount: -350, Currency: "GBP", Timestamp: march, CategoryID: "3", Answers: []Answer{
+				{QuestionID: "q1", Value: "b"},
+				{QuestionID: "q2", Value: float64(2)},
+				{QuestionID: "q3", Value: []interface{}{"c", "d"}},
+			}},
+			Transaction{ID: "t3", Amount: -500, Currency: "GBP", Timestamp: april, CategoryID: "1"},
+			Transaction{ID: "t4", Amount: -2000, Currency: "EUR", Timestamp: april, CategoryID: "4"},
+			Transaction{ID: "t5", Amount: 150, Currency: "GBP", Timestamp: april, CategoryID: "", Answers: nil},
+		},
+	}
+
+	t.Run("groups by category, rolling subcategories up into parents", func(t *testing.T) {
+		got := NewSpendingReport(categories, questions, transactions, nil, nil, GroupByCategory)
+
+		want := []SpendingGroup{
+			{Key: "1", Name: "food and drink", Count: 3, Totals: map[string]int64{"GBP": -1850}},
+			{Key: "2", Name: "groceries", Count: 1, Totals: map[string]int64{"GBP": -1000}},
+			{Key: "3", Name: "eating out", Count: 1, Totals: map[string]int64{"GBP": -350}},
+			{Key: "4", Name: "travel", Count: 1, Totals: map[string]int64{"EUR": -2000}},
+			{Key: "", Count: 1, Totals: map[string]int64{"GBP": 150}},
+		}
+		assertDeepEqual(t, got.Groups, want)
+	})
+
+	t.Run("groups by top-level category", func(t *testing.T) {
+		got := NewSpendingReport(categories, questions, transactions, nil, nil, GroupByParent)
+
+		want := []SpendingGroup{
+			{Key: "1", Name: "food and drink", Count: 3, Totals: map[string]int64{"GBP": -1850}},
+			{Key: "4", Name: "travel", Count: 1, Totals: map[string]int64{"EUR": -2000}},
+			{Key: "", Count: 1, Totals: map[string]int64{"GBP": 150}},
+		}
+		assertDeepEqual(t, got.Groups, want)
+	})
+
+	t.Run("groups by month", func(t *testing.T) {
+		got := NewSpendingReport(categories, questions, transactions, nil, nil, GroupByMonth)
+
+		want := []SpendingGroup{
+			{Key: "2019-03", Count: 2, Totals: map[string]int64{"GBP": -1350}},
+			{Key: "2019-04", Count: 3, Totals: map[string]int64{"GBP": -350, "EUR": -2000}},
+		}
+		assertDeepEqual(t, got.Groups, want)
+	})
+
+	t.Run("limits to the time range", func(t *testing.T) {
+		from := march.Add(-time.Hour)
+		to := april
+
+		got := NewSpendingReport(categories, questions, transactions, &from, &to, GroupByParent)
+
+		want := []SpendingGroup{
+			{Key: "1", Name: "food and drink", Count: 2, Totals: map[string]int64{"GBP": -1350}},
+		}
+		assertDeepEqual(t, got.Groups, want)
+	})
+
+	t.Run("counts answers per option of string & multiselect questions", func(t *testing.T) {
+		got := NewSpendingReport(categories, questions, transactions, nil, nil, GroupByCategory)
+
+		want := []QuestionOptionCount{
+			{QuestionID: "q1", Title: "which meal?", CategoryID: "3", Options: []OptionCount{
+				{ID: "a", Title: "breakfast", Count: 0},
+				{ID: "b", Title: "lunch", Count: 1},
+			}},
+			{QuestionID: "q3", Title: "which drinks?", CategoryID: "3", Options: []OptionCount{
+				{ID: "c", Title: "coffee", Count: 1},
+				{ID: "d", Title: "juice", Count: 1},
+			}},
+		}
+		assertDeepEqual(t, got.OptionCounts, want)
+	})
+}
+
+func TestIsValidGroupBy(t *testing.T) {
+	assertBool(t, IsValidGroupBy(GroupByCategory), true)
+	assertBool(t, IsValidGroupBy(GroupByParent), true)
+	assertBool(t, IsValidGroupBy(GroupByMonth), true)
+	assertBool(t, IsValidGroupBy("merchant"), false)
+}
@@ -0,0 +1,62 @@
+package internal
+
+import "sync"
+
+// Service applies the business rules for categories, questions & categorising transactions on top of the stores,
+// validating every change before making it, so every transport behaves identically
+// Categories & transactions may be nil, in which case their rules aren't applied
+// e.g. without a category store, questions may be added to any category
+// It is safe for concurrent use
+type Service struct {
+	categories   CategoryStore
+	questions    QuestionStore
+	transactions TransactionStore
+
+	// see SetMaxCategoryDepth
+	maxCategoryDepth int
+
+	// see Serialise
+	mu sync.Mutex
+}
+
+const (
+	// DefaultMaxCategoryDepth only allows categories and one level of subcategories
+	DefaultMaxCategoryDepth = 1
+	// UnlimitedCategoryDepth allows categories to be nested as deeply as wanted
+	UnlimitedCategoryDepth = -1
+)
+
+// NewService returns a Service over the stores
+func NewService(categories CategoryStore, questions QuestionStore, transactions TransactionStore) *Service {
+	return &Service{
+		categories:       categories,
+		questions:        questions,
+		transactions:     transactions,
+		maxCategoryDepth: DefaultMaxCategoryDepth,
+	}
+}
+
+// SetMaxCategoryDepth sets the deepest a category may be nested,
+// 0 being top level categories only, or UnlimitedCategoryDepth
+// Categories already nested deeper are left where they are
+// Call it before making any changes through the Service
+func (s *Service) SetMaxCategoryDepth(depth int) {
+	s.maxCategoryDepth = depth
+}
+
+// Serialise runs f while no change is being made through the Service
+// Every change the Service makes is serialised this way, so the checks it makes against the stores
+// (e.g. CategoryNameExists before AddCategory) can't be invalidated before it acts on them
+// Callers changing the stores directly should do so within f, for the same reason
+func (s *Service) Serialise(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f()
+}
+
+// Removed lists everything removed along with a category
+type Removed struct {
+	Categories             []string `json:"categories"`
+	Questions              []string `json:"questions"`
+	ReassignedTransactions int      `json:"reassignedTransactions"`
+}
@@ -0,0 +1,396 @@
+package internal
+
+import "context"
+
+func (s *Service) ListCategories(ctx context.Context) (CategoryList, error) {
+	return s.categories.ListCategories(ctx)
+}
+
+// GetCategory returns the category along with its immediate children
+func (s *Service) GetCategory(ctx context.Context, categoryID string) (Category, []Category, error) {
+	category, err := s.categories.GetCategory(ctx, categoryID)
+	if err != nil {
+		return Category{}, nil, err
+	}
+
+	children, err := s.categories.GetChildCategories(ctx, categoryID)
+	if err != nil {
+		return Category{}, nil, err
+	}
+
+	return category, children, nil
+}
+
+// GetCategoryTree returns the category with all of its descendants nested beneath it
+func (s *Service) GetCategoryTree(ctx context.Context, categoryID string) (CategoryTree, error) {
+	category, err := s.categories.GetCategory(ctx, categoryID)
+	if err != nil {
+		return CategoryTree{}, err
+	}
+
+	descendants, err := s.categories.GetDescendantCategories(ctx, categoryID)
+	if err != nil {
+		return CategoryTree{}, err
+	}
+
+	return newCategoryTree(category, descendants), nil
+}
+
+// AddCategory adds a category beneath parentID, or at the top level if it's ""
+// parentID is a pointer so that it not being given at all can be told apart from ""
+func (s *Service) AddCategory(ctx context.Context, categoryName string, parentID *string) (Category, error) {
+	var problems FieldErrors
+
+	if parentID == nil {
+		problems = append(problems, FieldError{ErrorFieldMissing, "/parentID", ""})
+	}
+
+	if !IsValidCategoryName(categoryName) {
+		problems = append(problems, FieldError{ErrorInvalidCategoryName, "/name", ""})
+	}
+
+	if len(problems) > 0 {
+		return Category{}, problems
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nameExists, err := s.categories.CategoryNameExists(ctx, categoryName)
+	if err != nil {
+		return Category{}, err
+	}
+
+	if nameExists {
+		return Category{}, fieldConflict(ErrorDuplicateCategoryName, "/name")
+	}
+
+	if *parentID != "" {
+		parentExists, err := s.categories.CategoryIDExists(ctx, *parentID)
+		if err != nil {
+			return Category{}, err
+		}
+
+		if !parentExists {
+			return Category{}, FieldErrors{{ErrorParentIDNotFound, "/parentID", ""}}
+		}
+	}
+
+	if *parentID != "" && s.maxCategoryDepth != UnlimitedCategoryDepth {
+		// depths are zero indexed, so the new category is one deeper than its parent's depth
+		parentDepth, err := s.categories.GetCategoryDepth(ctx, *parentID)
+		if err != nil {
+			return Category{}, err
+		}
+
+		if parentDepth+1 > s.maxCategoryDepth {
+			return Category{}, FieldErrors{{ErrorCategoryTooNested, "/parentID", ""}}
+		}
+	}
+
+	return s.categories.AddCategory(ctx, categoryName, *parentID)
+}
+
+func (s *Service) RenameCategory(ctx context.Context, categoryID, categoryName string) (Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.categories.GetCategory(ctx, categoryID); err != nil {
+		return Category{}, err
+	}
+
+	nameExists, err := s.categories.CategoryNameExists(ctx, categoryName)
+	if err != nil {
+		return Category{}, err
+	}
+
+	if nameExists {
+		return Category{}, fieldConflict(ErrorDuplicateCategoryName, "/name")
+	}
+
+	if !IsValidCategoryName(categoryName) {
+		return Category{}, FieldErrors{{ErrorInvalidCategoryName, "/name", ""}}
+	}
+
+	return s.categories.RenameCategory(ctx, categoryID, categoryName)
+}
+
+// MoveCategory reparents a category beneath parentID, or to the top level if it's "",
+// along with its questions, transactions & subcategories, which all stay with it
+// The category can't be moved beneath itself, and its subtree must fit within the max depth
+// Nor can it be moved away from the ancestors of questions it inherits that are used in its subtree,
+// see lostQuestionsInUse
+func (s *Service) MoveCategory(ctx context.Context, categoryID string, parentID *string) (Category, error) {
+	if parentID == nil {
+		return Category{}, FieldErrors{{ErrorFieldMissing, "/parentID", ""}}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.categories.GetCategory(ctx, categoryID); err != nil {
+		return Category{}, err
+	}
+
+	if *parentID != "" {
+		parentExists, err := s.categories.CategoryIDExists(ctx, *parentID)
+		if err != nil {
+			return Category{}, err
+		}
+
+		if !parentExists {
+			return Category{}, FieldErrors{{ErrorParentIDNotFound, "/parentID", ""}}
+		}
+	}
+
+	descendants, err := s.categories.GetDescendantCategories(ctx, categoryID)
+	if err != nil {
+		return Category{}, err
+	}
+
+	// moving beneath itself would leave the subtree's ParentIDs looping, detached from the top level
+	if *parentID == categoryID || containsCategory(descendants, *parentID) {
+		return Category{}, FieldErrors{{ErrorParentIDIsDescendant, "/parentID", ""}}
+	}
+
+	if s.maxCategoryDepth != UnlimitedCategoryDepth {
+		depth := 0
+		if *parentID != "" {
+			parentDepth, err := s.categories.GetCategoryDepth(ctx, *parentID)
+			if err != nil {
+				return Category{}, err
+			}
+			depth = parentDepth + 1
+		}
+
+		if depth+subtreeHeight(categoryID, descendants) > s.maxCategoryDepth {
+			return Category{}, FieldErrors{{ErrorCategoryTooNested, "/parentID", ""}}
+		}
+	}
+
+	subtree := append([]Category{{ID: categoryID}}, descendants...)
+	if err := s.lostQuestionsInUse(ctx, categoryID, *parentID, subtree); err != nil {
+		return Category{}, err
+	}
+
+	return s.categories.MoveCategory(ctx, categoryID, *parentID)
+}
+
+// lostQuestionsInUse checks nothing in the subtree uses a question it would stop inheriting by moving beneath parentID,
+// those of the ancestors it's moving away from, returning an InUseError counting the subtree's questions
+// depending on or overriding them, and the subtree's transactions answering them
+func (s *Service) lostQuestionsInUse(ctx context.Context, categoryID, parentID string, subtree []Category) error {
+	if s.questions == nil {
+		return nil
+	}
+
+	ancestry, err := s.ancestry(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	kept := map[string]bool{}
+	if parentID != "" {
+		newAncestry, err := s.ancestry(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		for _, id := range newAncestry {
+			kept[id] = true
+		}
+	}
+
+	lost := map[string]bool{}
+	for _, id := range ancestry[:len(ancestry)-1] {
+		if kept[id] {
+			continue
+		}
+
+		questionList, err := s.questions.ListQuestionsForCategory(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, q := range questionList.Questions {
+			lost[q.ID] = true
+		}
+	}
+
+	if len(lost) == 0 {
+		return nil
+	}
+
+	inSubtree := map[string]bool{}
+	inUse := &InUseError{Title: ErrorInheritedQuestionUsed}
+
+	for _, c := range subtree {
+		inSubtree[c.ID] = true
+
+		questionList, err := s.questions.ListQuestionsForCategory(ctx, c.ID)
+		if err != nil {
+			return err
+		}
+		for _, q := range questionList.Questions {
+			if lost[q.Overrides] || (q.DependsOn != nil && lost[q.DependsOn.QuestionID]) {
+				inUse.Questions++
+			}
+		}
+	}
+
+	if s.transactions != nil {
+		transactionList, err := s.transactions.ListTransactions(ctx)
+		if err != nil {
+			return err
+		}
+		for _, t := range transactionList.Transactions {
+			if inSubtree[t.CategoryID] && answersAny(t.Answers, lost) {
+				inUse.Transactions++
+			}
+		}
+	}
+
+	if inUse.Questions > 0 || inUse.Transactions > 0 {
+		return inUse
+	}
+	return nil
+}
+
+// answersAny reports whether any of the answers respond to one of the questions
+func answersAny(answers []Answer, questionIDs map[string]bool) bool {
+	for _, a := range answers {
+		if questionIDs[a.QuestionID] {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveCategory removes a category along with its questions
+// subcategories and transactions using the category block the removal,
+// unless they are removed/uncategorised (cascade),
+// or the transactions are moved to another category (reassignTo)
+func (s *Service) RemoveCategory(ctx context.Context, categoryID string, cascade bool, reassignTo string) (Removed, error) {
+	if cascade && reassignTo != "" {
+		return Removed{}, FieldErrors{{ErrorCascadeAndReassignBoth, "", ""}}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	category, err := s.categories.GetCategory(ctx, categoryID)
+	if err != nil {
+		return Removed{}, err
+	}
+
+	if reassignTo == categoryID {
+		return Removed{}, FieldErrors{{ErrorReassignToSelf, "", ""}}
+	}
+
+	if reassignTo != "" {
+		reassignToExists, err := s.categories.CategoryIDExists(ctx, reassignTo)
+		if err != nil {
+			return Removed{}, err
+		}
+
+		if !reassignToExists {
+			return Removed{}, FieldErrors{{ErrorReassignToNotFound, "", ""}}
+		}
+	}
+
+	// subcategories are only removed along with their parent when cascading
+	descendants, err := s.categories.GetDescendantCategories(ctx, categoryID)
+	if err != nil {
+		return Removed{}, err
+	}
+
+	if len(descendants) > 0 && !cascade {
+		return Removed{}, &InUseError{Title: ErrorCategoryHasChildren, Categories: len(descendants)}
+	}
+
+	if s.transactions != nil && !cascade && reassignTo == "" {
+		inUse, err := s.transactions.CountTransactionsForCategory(ctx, categoryID)
+		if err != nil {
+			return Removed{}, err
+		}
+
+		if inUse > 0 {
+			return Removed{}, &InUseError{Title: ErrorCategoryInUse, Transactions: inUse}
+		}
+	}
+
+	subtree := append([]Category{category}, descendants...)
+
+	return s.removeCategories(ctx, subtree, reassignTo)
+}
+
+// removeCategories removes the categories, along with their questions,
+// reassigning their transactions to reassignTo ("" uncategorises them)
+// The categories are removed last, all at once, so they're never seen partly removed,
+// and if a store fails before then they all stay, for the removal to be tried again
+func (s *Service) removeCategories(ctx context.Context, categories []Category, reassignTo string) (Removed, error) {
+	removed := Removed{
+		Categories: []string{},
+		Questions:  []string{},
+	}
+
+	for i := len(categories) - 1; i >= 0; i-- {
+		id := categories[i].ID
+
+		if s.transactions != nil {
+			count, err := s.transactions.CountTransactionsForCategory(ctx, id)
+			if err != nil {
+				return Removed{}, err
+			}
+			if err := s.transactions.ReassignTransactions(ctx, id, reassignTo); err != nil {
+				return Removed{}, err
+			}
+			removed.ReassignedTransactions += count
+		}
+
+		if s.questions != nil {
+			questionList, err := s.questions.ListQuestionsForCategory(ctx, id)
+			if err != nil {
+				return Removed{}, err
+			}
+			if err := s.questions.DeleteQuestionsForCategory(ctx, id); err != nil {
+				return Removed{}, err
+			}
+			for _, q := range questionList.Questions {
+				removed.Questions = append(removed.Questions, q.ID)
+			}
+		}
+
+		removed.Categories = append(removed.Categories, id)
+	}
+
+	// leaves first, so no category is ever left with a dangling ParentID
+	if err := s.categories.DeleteCategories(ctx, removed.Categories); err != nil {
+		return Removed{}, err
+	}
+
+	return removed, nil
+}
+
+func containsCategory(categories []Category, categoryID string) bool {
+	for _, c := range categories {
+		if c.ID == categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// subtreeHeight is how many levels of descendants the root has, 0 if none,
+// relying on them being ordered parents first, as GetDescendantCategories returns them
+func subtreeHeight(rootID string, descendants []Category) int {
+	depths := map[string]int{rootID: 0}
+	height := 0
+
+	for _, c := range descendants {
+		depths[c.ID] = depths[c.ParentID] + 1
+		if depths[c.ID] > height {
+			height = depths[c.ID]
+		}
+	}
+
+	return height
+}
@@ -0,0 +1,124 @@
+package internal
+
+import "context"
+
+// NextQuestions returns the questions still to be answered in the category, given the answers so far:
+// every question asked in it (see ListEffectiveQuestions) that isn't answered,
+// except follow-ups whose dependencies the answers don't meet, see Dependency
+// The answers are checked as they would be for a transaction, except that required questions may be unanswered
+func (s *Service) NextQuestions(ctx context.Context, categoryID string, answers []Answer) (QuestionList, error) {
+	if err := s.ensureCategoryExists(ctx, categoryID); err != nil {
+		return QuestionList{}, err
+	}
+
+	effective, err := s.effectiveQuestions(ctx, categoryID)
+	if err != nil {
+		return QuestionList{}, err
+	}
+	questions := QuestionList{Questions: effective.questions}
+
+	if problems := validatePartialAnswers(questions, answers); len(problems) > 0 {
+		return QuestionList{}, problems
+	}
+
+	answered := map[string]bool{}
+	for _, a := range answers {
+		answered[a.QuestionID] = true
+	}
+
+	next := QuestionList{Questions: []Question{}}
+	asked := askedQuestions(questions, answers)
+	for _, q := range questions.Questions {
+		if asked[q.ID] && !answered[q.ID] {
+			next.Questions = append(next.Questions, q)
+		}
+	}
+	return next, nil
+}
+
+// ensureValidDependency checks a question in the category (questionID, or "" for a question being added)
+// can depend on another as asked, which must be asked in the category too, see validateDependency
+// The question mustn't end up depending on itself, through any number of others
+func (s *Service) ensureValidDependency(ctx context.Context, categoryID, questionID string, dependsOn Dependency) error {
+	if dependsOn.QuestionID == "" {
+		return FieldErrors{{ErrorFieldMissing, "/dependsOn/questionID", ""}}
+	}
+
+	effective, err := s.effectiveQuestions(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	byID := map[string]Question{}
+	for _, q := range effective.questions {
+		byID[q.ID] = q
+	}
+
+	target, found := byID[dependsOn.QuestionID]
+	if !found {
+		return FieldErrors{{ErrorDependencyNotFound, "/dependsOn/questionID", ""}}
+	}
+
+	if err := validateDependency(target, dependsOn); err != nil {
+		return err
+	}
+
+	// follow what the question would depend on, checking it doesn't lead back to the question
+	q := target
+	for steps := 0; steps < len(byID); steps++ {
+		if q.ID == questionID {
+			return FieldErrors{{ErrorDependencyCycle, "/dependsOn/questionID", ""}}
+		}
+		if q.DependsOn == nil {
+			break
+		}
+
+		next, found := byID[q.DependsOn.QuestionID]
+		if !found {
+			break
+		}
+		q = next
+	}
+	return nil
+}
+
+// followUps returns the questions depending on the question in the category,
+// or only those depending on the option being chosen, given an optionID
+// A follow-up must be asked wherever the question is, so they can only be in the category or its subcategories
+func (s *Service) followUps(ctx context.Context, categoryID, questionID, optionID string) ([]Question, error) {
+	categoryIDs := []string{categoryID}
+	if s.categories != nil {
+		descendants, err := s.categories.GetDescendantCategories(ctx, categoryID)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range descendants {
+			categoryIDs = append(categoryIDs, c.ID)
+		}
+	}
+
+	var followUps []Question
+	for _, id := range categoryIDs {
+		questions, err := s.questions.ListQuestionsForCategory(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, q := range questions.Questions {
+			if q.DependsOn != nil && q.DependsOn.QuestionID == questionID && (optionID == "" || q.DependsOn.OptionID == optionID) {
+				followUps = append(followUps, q)
+			}
+		}
+	}
+	return followUps, nil
+}
+
+// removeDependencies stops the follow-ups depending on anything, so they're asked as any other question
+func (s *Service) removeDependencies(ctx context.Context, followUps []Question) error {
+	for _, q := range followUps {
+		if _, err := s.questions.SetQuestionDependency(ctx, q.ID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
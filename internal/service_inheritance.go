@@ -0,0 +1,201 @@
+package internal
+
+import "context"
+
+// ListEffectiveQuestions returns the questions asked of a transaction in the category:
+// those it inherits from its ancestors, outermost first, followed by its own
+// A question that overrides an inherited one (see Question.Overrides) is asked in its place,
+// and a question hidden in a category (see HideQuestion) isn't asked there, or in its subcategories
+// An override of a question that's no longer inherited, e.g. as it's been removed, is asked as any other question
+func (s *Service) ListEffectiveQuestions(ctx context.Context, categoryID string) (QuestionList, error) {
+	if err := s.ensureCategoryExists(ctx, categoryID); err != nil {
+		return QuestionList{}, err
+	}
+
+	effective, err := s.effectiveQuestions(ctx, categoryID)
+	if err != nil {
+		return QuestionList{}, err
+	}
+	return QuestionList{Questions: effective.questions}, nil
+}
+
+// HideQuestion stops a question the category inherits being asked in it, or its subcategories
+func (s *Service) HideQuestion(ctx context.Context, categoryID, questionID string) (Question, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureCategoryExists(ctx, categoryID); err != nil {
+		return Question{}, err
+	}
+
+	if err := s.ensureInherited(ctx, categoryID, questionID); err != nil {
+		return Question{}, err
+	}
+
+	return s.questions.HideQuestion(ctx, questionID, categoryID)
+}
+
+// UnhideQuestion undoes HideQuestion, so the category inherits the question again
+func (s *Service) UnhideQuestion(ctx context.Context, categoryID, questionID string) (Question, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureCategoryExists(ctx, categoryID); err != nil {
+		return Question{}, err
+	}
+
+	return s.questions.UnhideQuestion(ctx, questionID, categoryID)
+}
+
+// ensureCanOverride checks a question being added to the category can override the inherited question,
+// either the question itself or one it's being asked in place of,
+// which it can't if another of the category's questions already does
+func (s *Service) ensureCanOverride(ctx context.Context, categoryID, overriddenID string) error {
+	inherited, err := s.inheritedQuestions(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	if inherited.indexOfOverridden(overriddenID) == -1 {
+		return FieldErrors{{ErrorQuestionNotInherited, "/overrides", ""}}
+	}
+
+	own, err := s.questions.ListQuestionsForCategory(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	for _, q := range own.Questions {
+		if q.Overrides == overriddenID {
+			return fieldConflict(ErrorQuestionAlreadyOverridden, "/overrides")
+		}
+	}
+	return nil
+}
+
+// ensureInherited checks the category inherits the question itself, not an override of it
+func (s *Service) ensureInherited(ctx context.Context, categoryID, questionID string) error {
+	inherited, err := s.inheritedQuestions(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	for _, q := range inherited.questions {
+		if q.ID == questionID {
+			return nil
+		}
+	}
+	return FieldErrors{{ErrorQuestionNotInherited, "", ""}}
+}
+
+// effectiveQuestions are the questions asked in a category,
+// with askedFor holding, for each question, the IDs of those it's being asked in place of
+type effectiveQuestions struct {
+	questions []Question
+	askedFor  map[string][]string
+}
+
+// indexOfOverridden returns the index of the question being asked for overriddenID, or -1 if there isn't one
+func (e effectiveQuestions) indexOfOverridden(overriddenID string) int {
+	if overriddenID == "" {
+		return -1
+	}
+
+	for i, q := range e.questions {
+		if q.ID == overriddenID {
+			return i
+		}
+		for _, id := range e.askedFor[q.ID] {
+			if id == overriddenID {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// inheritedQuestions returns the effective questions of the category's parent,
+// none for a top level category, or without a category store
+func (s *Service) inheritedQuestions(ctx context.Context, categoryID string) (effectiveQuestions, error) {
+	if s.categories == nil {
+		return effectiveQuestions{}, nil
+	}
+
+	category, err := s.categories.GetCategory(ctx, categoryID)
+	if err != nil {
+		return effectiveQuestions{}, err
+	}
+
+	if category.ParentID == "" {
+		return effectiveQuestions{}, nil
+	}
+	return s.effectiveQuestions(ctx, category.ParentID)
+}
+
+// effectiveQuestions works down from the outermost ancestor of the category to the category itself,
+// each category's questions replacing those they override, after dropping those hidden in it
+func (s *Service) effectiveQuestions(ctx context.Context, categoryID string) (effectiveQuestions, error) {
+	ancestry, err := s.ancestry(ctx, categoryID)
+	if err != nil {
+		return effectiveQuestions{}, err
+	}
+
+	effective := effectiveQuestions{askedFor: map[string][]string{}}
+
+	for _, id := range ancestry {
+		own, err := s.questions.ListQuestionsForCategory(ctx, id)
+		if err != nil {
+			return effectiveQuestions{}, err
+		}
+
+		var kept []Question
+		for _, q := range effective.questions {
+			if !q.isHiddenIn(id) {
+				kept = append(kept, q)
+			}
+		}
+		effective.questions = kept
+
+		for _, q := range own.Questions {
+			i := effective.indexOfOverridden(q.Overrides)
+			if i == -1 {
+				effective.questions = append(effective.questions, q)
+				continue
+			}
+
+			overridden := effective.questions[i]
+			effective.askedFor[q.ID] = append(append([]string{}, effective.askedFor[overridden.ID]...), overridden.ID)
+			effective.questions[i] = q
+		}
+	}
+
+	return effective, nil
+}
+
+// ancestry returns the IDs of the category's ancestors, outermost first, followed by the category's own
+// Without a category store categories have no ancestors
+func (s *Service) ancestry(ctx context.Context, categoryID string) ([]string, error) {
+	if s.categories == nil {
+		return []string{categoryID}, nil
+	}
+
+	var ancestry []string
+	seen := map[string]bool{}
+
+	for id := categoryID; id != ""; {
+		if seen[id] {
+			return nil, conflict(ErrorCategoryCycle)
+		}
+		seen[id] = true
+
+		category, err := s.categories.GetCategory(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		ancestry = append([]string{id}, ancestry...)
+		id = category.ParentID
+	}
+
+	return ancestry, nil
+}
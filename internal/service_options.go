@@ -0,0 +1,155 @@
+package internal
+
+import "context"
+
+// ListOptions returns the options of a question in the category, in the order they were added
+func (s *Service) ListOptions(ctx context.Context, categoryID, questionID string) (OptionList, error) {
+	question, err := s.getQuestionWithOptions(ctx, categoryID, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return question.Options, nil
+}
+
+func (s *Service) GetOption(ctx context.Context, categoryID, questionID, optionID string) (Option, error) {
+	question, err := s.getQuestionWithOptions(ctx, categoryID, questionID)
+	if err != nil {
+		return Option{}, err
+	}
+
+	i := question.Options.indexOf(optionID)
+	if i == -1 {
+		return Option{}, notFound(ErrorOptionNotFound)
+	}
+
+	return question.Options[i], nil
+}
+
+// AddOption adds an option to a question, which must be of a type with options, see typeHasOptions
+// As when adding the question, the option can't be empty or the same as another of its options
+func (s *Service) AddOption(ctx context.Context, categoryID, questionID, optionTitle string) (Option, error) {
+	if optionTitle == "" {
+		return Option{}, FieldErrors{{ErrorOptionEmpty, "/title", ""}}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	question, err := s.getQuestionWithOptions(ctx, categoryID, questionID)
+	if err != nil {
+		return Option{}, err
+	}
+
+	if optionTitleExists(question.Options, optionTitle, "") {
+		return Option{}, FieldErrors{{ErrorDuplicateOption, "/title", ""}}
+	}
+
+	return s.questions.AddOption(ctx, questionID, optionTitle)
+}
+
+// RenameOption retitles an option, answers choosing it keep doing so
+func (s *Service) RenameOption(ctx context.Context, categoryID, questionID, optionID, optionTitle string) (Option, error) {
+	if optionTitle == "" {
+		return Option{}, FieldErrors{{ErrorOptionEmpty, "/title", ""}}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	question, err := s.getQuestionWithOptions(ctx, categoryID, questionID)
+	if err != nil {
+		return Option{}, err
+	}
+
+	if question.Options.indexOf(optionID) == -1 {
+		return Option{}, notFound(ErrorOptionNotFound)
+	}
+
+	if optionTitleExists(question.Options, optionTitle, optionID) {
+		return Option{}, FieldErrors{{ErrorDuplicateOption, "/title", ""}}
+	}
+
+	return s.questions.RenameOption(ctx, questionID, optionID, optionTitle)
+}
+
+// RemoveOption removes an option from a question
+// answers choosing the option, and follow-ups depending on it being chosen, either block the removal,
+// or the answers are removed and the follow-ups stop depending on it (cascade)
+func (s *Service) RemoveOption(ctx context.Context, categoryID, questionID, optionID string, cascade bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	question, err := s.getQuestionWithOptions(ctx, categoryID, questionID)
+	if err != nil {
+		return err
+	}
+
+	if question.Options.indexOf(optionID) == -1 {
+		return notFound(ErrorOptionNotFound)
+	}
+
+	followUps, err := s.followUps(ctx, categoryID, questionID, optionID)
+	if err != nil {
+		return err
+	}
+
+	inUse := 0
+	if s.transactions != nil {
+		if inUse, err = s.transactions.CountTransactionsAnsweringOption(ctx, questionID, optionID); err != nil {
+			return err
+		}
+	}
+
+	if (inUse > 0 || len(followUps) > 0) && !cascade {
+		return &InUseError{Title: ErrorOptionInUse, Transactions: inUse, Questions: len(followUps)}
+	}
+
+	if s.transactions != nil {
+		if err := s.transactions.DeleteAnswersForOption(ctx, questionID, optionID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.removeDependencies(ctx, followUps); err != nil {
+		return err
+	}
+
+	// the default can't go on choosing an option that no longer exists
+	if c := question.Constraints; c != nil && c.Default != nil && isOptionChosen(c.Default, optionID) {
+		constraints := *c
+		constraints.Default = nil
+		if remaining, keep := unchooseOption(c.Default, optionID); keep {
+			constraints.Default = remaining
+		}
+
+		if _, err := s.questions.SetQuestionConstraints(ctx, questionID, withoutEmptyConstraints(&constraints)); err != nil {
+			return err
+		}
+	}
+
+	return s.questions.DeleteOption(ctx, questionID, optionID)
+}
+
+// getQuestionWithOptions returns a question in the category,
+// so long as it's of a type that has options, which e.g. "number" questions don't
+func (s *Service) getQuestionWithOptions(ctx context.Context, categoryID, questionID string) (Question, error) {
+	if err := s.ensureCategoryExists(ctx, categoryID); err != nil {
+		return Question{}, err
+	}
+
+	if err := s.ensureQuestionInCategory(ctx, categoryID, questionID); err != nil {
+		return Question{}, err
+	}
+
+	question, err := s.questions.GetQuestion(ctx, questionID)
+	if err != nil {
+		return Question{}, err
+	}
+
+	if !typeHasOptions(question.Type) {
+		return Question{}, FieldErrors{{ErrorQuestionHasNoOptions, "", ""}}
+	}
+
+	return question, nil
+}
@@ -0,0 +1,183 @@
+package internal
+
+import "context"
+
+func (s *Service) ListQuestions(ctx context.Context, categoryID string) (QuestionList, error) {
+	return s.questions.ListQuestionsForCategory(ctx, categoryID)
+}
+
+func (s *Service) GetQuestion(ctx context.Context, questionID string) (Question, error) {
+	return s.questions.GetQuestion(ctx, questionID)
+}
+
+// AddQuestion adds a question to the category, see ValidateQuestionPostRequest
+// A question overriding another must override one the category inherits, and only one question may do so
+// A follow-up must depend on a question asked in the category, see ensureValidDependency
+func (s *Service) AddQuestion(ctx context.Context, categoryID string, question QuestionPostRequest) (Question, error) {
+	if err := ValidateQuestionPostRequest(question); err != nil {
+		return Question{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureQuestionTitleFree(ctx, categoryID, question.Title); err != nil {
+		return Question{}, err
+	}
+
+	if err := s.ensureCategoryExists(ctx, categoryID); err != nil {
+		return Question{}, err
+	}
+
+	if question.Overrides != "" {
+		if err := s.ensureCanOverride(ctx, categoryID, question.Overrides); err != nil {
+			return Question{}, err
+		}
+	}
+
+	question.DependsOn = withoutEmptyDependency(question.DependsOn)
+	if question.DependsOn != nil {
+		if err := s.ensureValidDependency(ctx, categoryID, "", *question.DependsOn); err != nil {
+			return Question{}, err
+		}
+	}
+
+	question.Constraints = withoutEmptyConstraints(question.Constraints)
+
+	return s.questions.AddQuestion(ctx, categoryID, question)
+}
+
+func (s *Service) RenameQuestion(ctx context.Context, categoryID, questionID, questionTitle string) (Question, error) {
+	return s.UpdateQuestion(ctx, categoryID, questionID, QuestionPatchRequest{Title: &questionTitle})
+}
+
+// UpdateQuestion renames a question and/or replaces its constraints or dependency, see QuestionPatchRequest
+// Everything is checked before the store applies the whole request at once, so it's never only partly applied
+func (s *Service) UpdateQuestion(ctx context.Context, categoryID, questionID string, update QuestionPatchRequest) (Question, error) {
+	if update.Title == nil && update.Constraints == nil && update.DependsOn == nil {
+		return Question{}, FieldErrors{{ErrorFieldMissing, "", ""}}
+	}
+
+	if update.Title != nil && !IsValidQuestionTitle(*update.Title) {
+		return Question{}, FieldErrors{{ErrorInvalidTitle, "/title", ""}}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureCategoryExists(ctx, categoryID); err != nil {
+		return Question{}, err
+	}
+
+	if err := s.ensureQuestionInCategory(ctx, categoryID, questionID); err != nil {
+		return Question{}, err
+	}
+
+	question, err := s.questions.GetQuestion(ctx, questionID)
+	if err != nil {
+		return Question{}, err
+	}
+
+	// the question's options exist by now, so a default can choose from them
+	if update.Constraints != nil {
+		if err := ValidateConstraints(question, *update.Constraints); err != nil {
+			return Question{}, err
+		}
+	}
+
+	dependsOn := withoutEmptyDependency(update.DependsOn)
+	if dependsOn != nil {
+		if err := s.ensureValidDependency(ctx, categoryID, questionID, *dependsOn); err != nil {
+			return Question{}, err
+		}
+	}
+
+	if update.Title != nil {
+		if err := s.ensureQuestionTitleFree(ctx, categoryID, *update.Title); err != nil {
+			return Question{}, err
+		}
+	}
+
+	return s.questions.UpdateQuestion(ctx, questionID, update)
+}
+
+// RemoveQuestion removes a question from the category
+// answers to the question, and follow-ups depending on it, either block the removal,
+// or the answers are removed and the follow-ups stop depending on it (cascade)
+func (s *Service) RemoveQuestion(ctx context.Context, categoryID, questionID string, cascade bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureCategoryExists(ctx, categoryID); err != nil {
+		return err
+	}
+
+	if err := s.ensureQuestionInCategory(ctx, categoryID, questionID); err != nil {
+		return err
+	}
+
+	followUps, err := s.followUps(ctx, categoryID, questionID, "")
+	if err != nil {
+		return err
+	}
+
+	inUse := 0
+	if s.transactions != nil {
+		if inUse, err = s.transactions.CountTransactionsAnsweringQuestion(ctx, questionID); err != nil {
+			return err
+		}
+	}
+
+	if (inUse > 0 || len(followUps) > 0) && !cascade {
+		return &InUseError{Title: ErrorQuestionInUse, Transactions: inUse, Questions: len(followUps)}
+	}
+
+	if s.transactions != nil {
+		if err := s.transactions.DeleteAnswersForQuestion(ctx, questionID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.removeDependencies(ctx, followUps); err != nil {
+		return err
+	}
+
+	return s.questions.DeleteQuestion(ctx, questionID)
+}
+
+// ensureCategoryExists checks the category a question belongs to exists,
+// without a category store any category goes
+func (s *Service) ensureCategoryExists(ctx context.Context, categoryID string) error {
+	if s.categories == nil {
+		return nil
+	}
+
+	_, err := s.categories.GetCategory(ctx, categoryID)
+	return err
+}
+
+// ensureQuestionInCategory checks the question exists, and belongs to the category
+func (s *Service) ensureQuestionInCategory(ctx context.Context, categoryID, questionID string) error {
+	question, err := s.questions.GetQuestion(ctx, questionID)
+	if err != nil {
+		return err
+	}
+
+	if question.CategoryID != categoryID {
+		return notFound(ErrorQuestionDoesntBelongToCategory)
+	}
+	return nil
+}
+
+// ensureQuestionTitleFree checks the category has no question with the title already
+func (s *Service) ensureQuestionTitleFree(ctx context.Context, categoryID, questionTitle string) error {
+	titleExists, err := s.questions.QuestionTitleExists(ctx, categoryID, questionTitle)
+	if err != nil {
+		return err
+	}
+
+	if titleExists {
+		return fieldConflict(ErrorDuplicateTitle, "/title")
+	}
+	return nil
+}
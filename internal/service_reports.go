@@ -0,0 +1,33 @@
+package internal
+
+import "context"
+
+// SpendingReport reports on the transactions in the requested range, see ParseSpendingReportRequest
+// It's built while no change is being made, so the categories, questions & transactions it's built from agree
+// It needs the Service to have category & transaction stores
+func (s *Service) SpendingReport(ctx context.Context, request SpendingReportRequest) (SpendingReport, error) {
+	from, to, groupBy, err := ParseSpendingReportRequest(request)
+	if err != nil {
+		return SpendingReport{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categories, err := s.categories.ListCategories(ctx)
+	if err != nil {
+		return SpendingReport{}, err
+	}
+
+	questions, err := s.questions.ListQuestions(ctx)
+	if err != nil {
+		return SpendingReport{}, err
+	}
+
+	transactions, err := s.transactions.ListTransactions(ctx)
+	if err != nil {
+		return SpendingReport{}, err
+	}
+
+	return NewSpendingReport(categories, questions, transactions, from, to, groupBy), nil
+}
@@ -0,0 +1,933 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestService(t *testing.T) (*Service, *InMemoryQuestionStore) {
+	t.Helper()
+	categoryStore := NewInMemoryCategoryStore(&CategoryList{
+		Categories: []Category{
+			{ID: "1234", Name: "accommodation", ParentID: ""},
+			{ID: "5678", Name: "hotel", ParentID: "1234"},
+		},
+	})
+	questionStore := NewInMemoryQuestionStore(&QuestionList{
+		Questions: []Question{
+			{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+		},
+	})
+	transactionStore := NewInMemoryTransactionStore(&TransactionList{
+		Transactions: []Transaction{
+			{ID: "abcdef", Amount: -350, Currency: "GBP", CategoryID: "5678", Answers: []Answer{
+				{QuestionID: "1", Value: "2"},
+			}},
+		},
+	})
+	return NewService(categoryStore, questionStore, transactionStore), questionStore
+}
+
+func assertFieldErrors(t *testing.T, err error, want FieldErrors) {
+	t.Helper()
+	var got FieldErrors
+	if !errors.As(err, &got) {
+		t.Fatalf("got error '%v' wanted field errors", err)
+	}
+	assertDeepEqual(t, got, want)
+}
+
+func TestServiceAddQuestion(t *testing.T) {
+	t.Run("every problem with the question is reported", func(t *testing.T) {
+		service, _ := newTestService(t)
+		options := []string{"a", "a"}
+
+		_, err := service.AddQuestion(ctx, "1234", QuestionPostRequest{Title: "!!!", Type: "foo", Options: &options})
+
+		assertFieldErrors(t, err, FieldErrors{
+			{ErrorInvalidTitle, "/title", ""},
+			{ErrorInvalidType, "/type", ""},
+			{ErrorDuplicateOption, "/options/1", ""},
+		})
+	})
+
+	t.Run("only questions answered by choosing may have options", func(t *testing.T) {
+		service, _ := newTestService(t)
+		options := []string{"yes", "no"}
+
+		_, err := service.AddQuestion(ctx, "1234", QuestionPostRequest{Title: "work expense?", Type: "boolean", Options: &options})
+		assertFieldErrors(t, err, FieldErrors{{ErrorOptionsNotAllowed, "/options", ""}})
+
+		added, err := service.AddQuestion(ctx, "1234", QuestionPostRequest{Title: "which meals?", Type: "multiselect", Options: &options})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(added.Options), 2)
+	})
+
+	t.Run("titles are unique within a category", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.AddQuestion(ctx, "1234", QuestionPostRequest{Title: "how many nights?", Type: "number"})
+
+		assertErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("the category must exist", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.AddQuestion(ctx, "9999", QuestionPostRequest{Title: "foo", Type: "number"})
+
+		assertErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("a valid question is added", func(t *testing.T) {
+		service, questionStore := newTestService(t)
+
+		question, err := service.AddQuestion(ctx, "1234", QuestionPostRequest{Title: "foo", Type: "number"})
+
+		assertNoError(t, err)
+		got, err := questionStore.GetQuestion(ctx, question.ID)
+		assertNoError(t, err)
+		assertDeepEqual(t, got, question)
+	})
+}
+
+func TestServiceQuestionConstraints(t *testing.T) {
+	min, max := float64(1), float64(30)
+
+	t.Run("constraints are checked against the question's type", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.AddQuestion(ctx, "1234", QuestionPostRequest{Title: "any notes?", Type: "text", Constraints: &Constraints{Min: &min}})
+		assertFieldErrors(t, err, FieldErrors{{ErrorConstraintNotAllowed, "/constraints/min", ""}})
+
+		_, err = service.UpdateQuestion(ctx, "1234", "1", QuestionPatchRequest{Constraints: &Constraints{Min: &max, Max: &min}})
+		assertFieldErrors(t, err, FieldErrors{{ErrorMinAboveMax, "/constraints/max", ""}})
+	})
+
+	t.Run("a question's constraints are replaced", func(t *testing.T) {
+		service, questionStore := newTestService(t)
+		constraints := &Constraints{Required: true, Min: &min, Max: &max, Unit: "nights"}
+
+		updated, err := service.UpdateQuestion(ctx, "1234", "1", QuestionPatchRequest{Constraints: constraints})
+		assertNoError(t, err)
+		assertDeepEqual(t, updated.Constraints, constraints)
+
+		got, err := questionStore.GetQuestion(ctx, "1")
+		assertNoError(t, err)
+		assertDeepEqual(t, got, updated)
+
+		updated, err = service.UpdateQuestion(ctx, "1234", "1", QuestionPatchRequest{Constraints: &Constraints{}})
+		assertNoError(t, err)
+		if updated.Constraints != nil {
+			t.Errorf("got constraints %v wanted none", updated.Constraints)
+		}
+	})
+
+	t.Run("nothing is changed when any of the update is invalid", func(t *testing.T) {
+		service, questionStore := newTestService(t)
+		title := "how many nights away?"
+
+		_, err := service.UpdateQuestion(ctx, "1234", "1", QuestionPatchRequest{Title: &title, Constraints: &Constraints{MaxLength: new(int)}})
+		assertFieldErrors(t, err, FieldErrors{
+			{ErrorConstraintNotAllowed, "/constraints/maxLength", ""},
+			{ErrorInvalidMaxLength, "/constraints/maxLength", ""},
+		})
+
+		got, err := questionStore.GetQuestion(ctx, "1")
+		assertNoError(t, err)
+		assertStringsEqual(t, got.Title, "how many nights?")
+	})
+
+	t.Run("an update must change something", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.UpdateQuestion(ctx, "1234", "1", QuestionPatchRequest{})
+		assertFieldErrors(t, err, FieldErrors{{ErrorFieldMissing, "", ""}})
+	})
+}
+
+func TestServiceRemove(t *testing.T) {
+	t.Run("a question with answers is in use", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		err := service.RemoveQuestion(ctx, "1234", "1", false)
+
+		var inUse *InUseError
+		if !errors.As(err, &inUse) {
+			t.Fatalf("got error '%v' wanted an in use error", err)
+		}
+		assertStringsEqual(t, inUse.Title, ErrorQuestionInUse)
+		assertNumbersEqual(t, inUse.Transactions, 1)
+		assertErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("a question must belong to the category", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		err := service.RemoveQuestion(ctx, "5678", "1", true)
+
+		assertErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("a category with children is in use", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.RemoveCategory(ctx, "1234", false, "")
+
+		var inUse *InUseError
+		if !errors.As(err, &inUse) {
+			t.Fatalf("got error '%v' wanted an in use error", err)
+		}
+		assertStringsEqual(t, inUse.Title, ErrorCategoryHasChildren)
+		assertNumbersEqual(t, inUse.Categories, 1)
+	})
+
+	t.Run("cascading removes the subtree and everything in it", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		removed, err := service.RemoveCategory(ctx, "1234", true, "")
+
+		assertNoError(t, err)
+		assertDeepEqual(t, removed, Removed{
+			Categories:             []string{"5678", "1234"},
+			Questions:              []string{"1"},
+			ReassignedTransactions: 1,
+		})
+	})
+
+	t.Run("cascading over SQLite removes the subtree and everything in it", func(t *testing.T) {
+		db := newTestSQLiteDB(t)
+		categoryStore := NewSQLiteCategoryStore(db)
+		questionStore := NewSQLiteQuestionStore(db)
+		transactionStore := NewSQLiteTransactionStore(db)
+		service := NewService(categoryStore, questionStore, transactionStore)
+
+		accommodation := addCategory(t, ctx, categoryStore, "accommodation", "")
+		hotel := addCategory(t, ctx, categoryStore, "hotel", accommodation.ID)
+		nights := addQuestion(t, ctx, questionStore, accommodation.ID, QuestionPostRequest{Title: "how many nights?", Type: "number"})
+		transaction := addTransaction(t, ctx, transactionStore, Transaction{Amount: -350, Currency: "GBP", Merchant: "Hotel", Timestamp: time.Now().UTC()})
+		_, err := transactionStore.CategoriseTransaction(ctx, transaction.ID, hotel.ID, nil)
+		assertNoError(t, err)
+
+		removed, err := service.RemoveCategory(ctx, accommodation.ID, true, "")
+
+		assertNoError(t, err)
+		assertDeepEqual(t, removed, Removed{
+			Categories:             []string{hotel.ID, accommodation.ID},
+			Questions:              []string{nights.ID},
+			ReassignedTransactions: 1,
+		})
+		got, err := transactionStore.GetTransaction(ctx, transaction.ID)
+		assertNoError(t, err)
+		assertStringsEqual(t, got.CategoryID, "")
+	})
+
+	t.Run("cascading and reassigning are exclusive", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.RemoveCategory(ctx, "5678", true, "1234")
+
+		assertFieldErrors(t, err, FieldErrors{{ErrorCascadeAndReassignBoth, "", ""}})
+	})
+}
+
+func TestServiceCategoryDepth(t *testing.T) {
+	newService := func(maxDepth int) *Service {
+		service := NewService(NewInMemoryCategoryStore(&CategoryList{
+			Categories: []Category{
+				{ID: "1234", Name: "accommodation", ParentID: ""},
+				{ID: "5678", Name: "hostel", ParentID: "1234"},
+			},
+		}), nil, nil)
+		service.SetMaxCategoryDepth(maxDepth)
+		return service
+	}
+
+	t.Run("subcategories are only one level deep by default", func(t *testing.T) {
+		service := NewService(NewInMemoryCategoryStore(nil), nil, nil)
+		topLevel := ""
+		accommodation, err := service.AddCategory(ctx, "accommodation", &topLevel)
+		assertNoError(t, err)
+		hostel, err := service.AddCategory(ctx, "hostel", &accommodation.ID)
+		assertNoError(t, err)
+
+		_, err = service.AddCategory(ctx, "dorm", &hostel.ID)
+		assertFieldErrors(t, err, FieldErrors{{ErrorCategoryTooNested, "/parentID", ""}})
+	})
+
+	t.Run("deeper nesting can be allowed", func(t *testing.T) {
+		service := newService(2)
+		parentID := "5678"
+
+		dorm, err := service.AddCategory(ctx, "dorm", &parentID)
+		assertNoError(t, err)
+
+		_, err = service.AddCategory(ctx, "bunk", &dorm.ID)
+		assertFieldErrors(t, err, FieldErrors{{ErrorCategoryTooNested, "/parentID", ""}})
+	})
+
+	t.Run("nesting can be unlimited", func(t *testing.T) {
+		service := newService(UnlimitedCategoryDepth)
+		parentID := "5678"
+
+		for _, name := range []string{"dorm", "bunk", "top bunk", "pillow"} {
+			category, err := service.AddCategory(ctx, name, &parentID)
+			assertNoError(t, err)
+			parentID = category.ID
+		}
+	})
+
+	t.Run("subcategories can be disallowed", func(t *testing.T) {
+		service := newService(0)
+		parentID := "1234"
+
+		_, err := service.AddCategory(ctx, "hotel", &parentID)
+		assertFieldErrors(t, err, FieldErrors{{ErrorCategoryTooNested, "/parentID", ""}})
+	})
+}
+
+func TestServiceGetCategoryTree(t *testing.T) {
+	accommodation := Category{ID: "1234", Name: "accommodation", ParentID: ""}
+	hostel := Category{ID: "5678", Name: "hostel", ParentID: "1234"}
+	hotel := Category{ID: "9012", Name: "hotel", ParentID: "1234"}
+	dorm := Category{ID: "abcd", Name: "dorm", ParentID: "5678"}
+	service := NewService(NewInMemoryCategoryStore(&CategoryList{
+		Categories: []Category{accommodation, hostel, hotel, dorm, {ID: "efgh", Name: "food", ParentID: ""}},
+	}), nil, nil)
+
+	got, err := service.GetCategoryTree(ctx, "1234")
+	assertNoError(t, err)
+
+	want := CategoryTree{Category: accommodation, Children: []CategoryTree{
+		{Category: hostel, Children: []CategoryTree{
+			{Category: dorm, Children: []CategoryTree{}},
+		}},
+		{Category: hotel, Children: []CategoryTree{}},
+	}}
+	assertDeepEqual(t, got, want)
+
+	_, err = service.GetCategoryTree(ctx, "9999")
+	assertErrorIs(t, err, ErrNotFound)
+}
+
+func TestServiceMoveCategory(t *testing.T) {
+	newService := func(maxDepth int) (*Service, *InMemoryQuestionStore) {
+		service, questionStore := newTestService(t)
+		_, err := service.categories.AddCategory(ctx, "food", "")
+		assertNoError(t, err)
+		service.SetMaxCategoryDepth(maxDepth)
+		return service, questionStore
+	}
+	parentID := func(id string) *string { return &id }
+
+	t.Run("moves the category with its subtree & questions", func(t *testing.T) {
+		service, questionStore := newService(UnlimitedCategoryDepth)
+		travel, err := service.AddCategory(ctx, "travel", parentID(""))
+		assertNoError(t, err)
+
+		moved, err := service.MoveCategory(ctx, "1234", &travel.ID)
+		assertNoError(t, err)
+		assertStringsEqual(t, moved.ParentID, travel.ID)
+
+		tree, err := service.GetCategoryTree(ctx, travel.ID)
+		assertNoError(t, err)
+		assertStringsEqual(t, tree.Children[0].ID, "1234")
+		assertStringsEqual(t, tree.Children[0].Children[0].ID, "5678")
+
+		question, err := questionStore.GetQuestion(ctx, "1")
+		assertNoError(t, err)
+		assertStringsEqual(t, question.CategoryID, "1234")
+	})
+
+	t.Run("moves a subcategory to the top level", func(t *testing.T) {
+		service, _ := newService(DefaultMaxCategoryDepth)
+		assertNoError(t, service.transactions.DeleteTransaction(ctx, "abcdef"))
+
+		moved, err := service.MoveCategory(ctx, "5678", parentID(""))
+		assertNoError(t, err)
+		assertStringsEqual(t, moved.ParentID, "")
+	})
+
+	t.Run("can't stop inheriting questions that are used in the subtree", func(t *testing.T) {
+		service, _ := newService(DefaultMaxCategoryDepth)
+		_, err := service.AddQuestion(ctx, "5678", QuestionPostRequest{Title: "how many breakfasts?", Type: "number", DependsOn: &Dependency{QuestionID: "1", Min: new(float64)}})
+		assertNoError(t, err)
+		_, err = service.AddQuestion(ctx, "5678", QuestionPostRequest{Title: "how many hotel nights?", Type: "number", Overrides: "1"})
+		assertNoError(t, err)
+
+		// transaction abcdef in 5678 answers question 1, asked in 1234
+		_, err = service.MoveCategory(ctx, "5678", parentID(""))
+
+		var inUse *InUseError
+		if !errors.As(err, &inUse) {
+			t.Fatalf("got error '%v' wanted an InUseError", err)
+		}
+		assertDeepEqual(t, inUse, &InUseError{Title: ErrorInheritedQuestionUsed, Questions: 2, Transactions: 1})
+
+		category, err := service.categories.GetCategory(ctx, "5678")
+		assertNoError(t, err)
+		assertStringsEqual(t, category.ParentID, "1234")
+	})
+
+	t.Run("can move while keeping the ancestors of the questions used", func(t *testing.T) {
+		service, _ := newService(UnlimitedCategoryDepth)
+		hotels, err := service.AddCategory(ctx, "hotels", parentID("1234"))
+		assertNoError(t, err)
+
+		// still beneath 1234, so transaction abcdef's answer to question 1 is still asked
+		moved, err := service.MoveCategory(ctx, "5678", &hotels.ID)
+		assertNoError(t, err)
+		assertStringsEqual(t, moved.ParentID, hotels.ID)
+	})
+
+	t.Run("can't move beneath itself", func(t *testing.T) {
+		service, _ := newService(UnlimitedCategoryDepth)
+
+		_, err := service.MoveCategory(ctx, "1234", parentID("1234"))
+		assertFieldErrors(t, err, FieldErrors{{ErrorParentIDIsDescendant, "/parentID", ""}})
+
+		_, err = service.MoveCategory(ctx, "1234", parentID("5678"))
+		assertFieldErrors(t, err, FieldErrors{{ErrorParentIDIsDescendant, "/parentID", ""}})
+	})
+
+	t.Run("the whole subtree must fit within the max depth", func(t *testing.T) {
+		service, _ := newService(DefaultMaxCategoryDepth)
+		categoryList, err := service.ListCategories(ctx)
+		assertNoError(t, err)
+		food := categoryList.Categories[2]
+
+		_, err = service.MoveCategory(ctx, "1234", &food.ID)
+		assertFieldErrors(t, err, FieldErrors{{ErrorCategoryTooNested, "/parentID", ""}})
+	})
+
+	t.Run("the parent must be given & exist", func(t *testing.T) {
+		service, _ := newService(DefaultMaxCategoryDepth)
+
+		_, err := service.MoveCategory(ctx, "5678", nil)
+		assertFieldErrors(t, err, FieldErrors{{ErrorFieldMissing, "/parentID", ""}})
+
+		_, err = service.MoveCategory(ctx, "5678", parentID("9999"))
+		assertFieldErrors(t, err, FieldErrors{{ErrorParentIDNotFound, "/parentID", ""}})
+
+		_, err = service.MoveCategory(ctx, "9999", parentID(""))
+		assertErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestServiceOptions(t *testing.T) {
+	newService := func() (*Service, *InMemoryTransactionStore) {
+		categoryStore := NewInMemoryCategoryStore(&CategoryList{
+			Categories: []Category{{ID: "1234", Name: "accommodation"}},
+		})
+		questionStore := NewInMemoryQuestionStore(&QuestionList{
+			Questions: []Question{
+				{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+				{ID: "2", Title: "what kind?", CategoryID: "1234", Type: "string", Options: OptionList{
+					{ID: "a", Title: "hotel"},
+					{ID: "b", Title: "hostel"},
+				}},
+			},
+		})
+		transactionStore := NewInMemoryTransactionStore(&TransactionList{
+			Transactions: []Transaction{
+				{ID: "abcdef", Amount: -4000, Currency: "GBP", CategoryID: "1234", Answers: []Answer{
+					{QuestionID: "2", Value: "a"},
+				}},
+			},
+		})
+		return NewService(categoryStore, questionStore, transactionStore), transactionStore
+	}
+
+	t.Run("options can't be empty or duplicates", func(t *testing.T) {
+		service, _ := newService()
+
+		_, err := service.AddOption(ctx, "1234", "2", "")
+		assertFieldErrors(t, err, FieldErrors{{ErrorOptionEmpty, "/title", ""}})
+
+		_, err = service.AddOption(ctx, "1234", "2", "hostel")
+		assertFieldErrors(t, err, FieldErrors{{ErrorDuplicateOption, "/title", ""}})
+
+		_, err = service.RenameOption(ctx, "1234", "2", "a", "hostel")
+		assertFieldErrors(t, err, FieldErrors{{ErrorDuplicateOption, "/title", ""}})
+	})
+
+	t.Run("number questions have no options", func(t *testing.T) {
+		service, _ := newService()
+
+		_, err := service.ListOptions(ctx, "1234", "1")
+		assertFieldErrors(t, err, FieldErrors{{ErrorQuestionHasNoOptions, "", ""}})
+
+		_, err = service.AddOption(ctx, "1234", "1", "two")
+		assertFieldErrors(t, err, FieldErrors{{ErrorQuestionHasNoOptions, "", ""}})
+	})
+
+	t.Run("an option chosen by answers is in use", func(t *testing.T) {
+		service, _ := newService()
+
+		err := service.RemoveOption(ctx, "1234", "2", "a", false)
+
+		var inUse *InUseError
+		if !errors.As(err, &inUse) {
+			t.Fatalf("got error '%v' wanted an in use error", err)
+		}
+		assertStringsEqual(t, inUse.Title, ErrorOptionInUse)
+		assertNumbersEqual(t, inUse.Transactions, 1)
+	})
+
+	t.Run("cascading removes the answers choosing the option", func(t *testing.T) {
+		service, transactionStore := newService()
+
+		err := service.RemoveOption(ctx, "1234", "2", "a", true)
+		assertNoError(t, err)
+
+		options, err := service.ListOptions(ctx, "1234", "2")
+		assertNoError(t, err)
+		assertDeepEqual(t, options, OptionList{{ID: "b", Title: "hostel"}})
+
+		transaction, err := transactionStore.GetTransaction(ctx, "abcdef")
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(transaction.Answers), 0)
+	})
+	t.Run("removing the option chosen by default clears the default", func(t *testing.T) {
+		service, _ := newService()
+
+		_, err := service.UpdateQuestion(ctx, "1234", "2", QuestionPatchRequest{Constraints: &Constraints{Required: true, Default: "b"}})
+		assertNoError(t, err)
+
+		err = service.RemoveOption(ctx, "1234", "2", "b", false)
+		assertNoError(t, err)
+
+		question, err := service.GetQuestion(ctx, "2")
+		assertNoError(t, err)
+		assertDeepEqual(t, question.Constraints, &Constraints{Required: true})
+	})
+}
+
+func TestServiceEffectiveQuestions(t *testing.T) {
+	newService := func() *Service {
+		categoryStore := NewInMemoryCategoryStore(&CategoryList{
+			Categories: []Category{
+				{ID: "1234", Name: "food & drink"},
+				{ID: "5678", Name: "restaurants", ParentID: "1234"},
+				{ID: "9012", Name: "fancy", ParentID: "5678"},
+				{ID: "3456", Name: "accommodation"},
+			},
+		})
+		questionStore := NewInMemoryQuestionStore(&QuestionList{
+			Questions: []Question{
+				{ID: "1", Title: "which meal?", CategoryID: "1234", Type: "string", Options: OptionList{
+					{ID: "a", Title: "breakfast"},
+					{ID: "b", Title: "lunch"},
+					{ID: "c", Title: "dinner"},
+				}},
+				{ID: "2", Title: "how many people?", CategoryID: "1234", Type: "number"},
+				{ID: "3", Title: "any tip?", CategoryID: "5678", Type: "boolean"},
+				{ID: "4", Title: "how many nights?", CategoryID: "3456", Type: "number"},
+			},
+		})
+		service := NewService(categoryStore, questionStore, nil)
+		service.SetMaxCategoryDepth(UnlimitedCategoryDepth)
+		return service
+	}
+
+	effectiveIDs := func(t *testing.T, service *Service, categoryID string) []string {
+		t.Helper()
+		questions, err := service.ListEffectiveQuestions(ctx, categoryID)
+		assertNoError(t, err)
+
+		var ids []string
+		for _, q := range questions.Questions {
+			ids = append(ids, q.ID)
+		}
+		return ids
+	}
+
+	t.Run("questions are inherited from every ancestor", func(t *testing.T) {
+		service := newService()
+
+		assertDeepEqual(t, effectiveIDs(t, service, "1234"), []string{"1", "2"})
+		assertDeepEqual(t, effectiveIDs(t, service, "5678"), []string{"1", "2", "3"})
+		assertDeepEqual(t, effectiveIDs(t, service, "9012"), []string{"1", "2", "3"})
+	})
+
+	t.Run("an override is asked in place of the inherited question", func(t *testing.T) {
+		service := newService()
+
+		override, err := service.AddQuestion(ctx, "5678", QuestionPostRequest{Title: "which meal?", Type: "string", Options: &[]string{"lunch", "dinner"}, Overrides: "1"})
+		assertNoError(t, err)
+
+		assertDeepEqual(t, effectiveIDs(t, service, "5678"), []string{override.ID, "2", "3"})
+		assertDeepEqual(t, effectiveIDs(t, service, "9012"), []string{override.ID, "2", "3"})
+		assertDeepEqual(t, effectiveIDs(t, service, "1234"), []string{"1", "2"})
+
+		t.Run("only once per category", func(t *testing.T) {
+			_, err := service.AddQuestion(ctx, "5678", QuestionPostRequest{Title: "what meal?", Type: "number", Overrides: "1"})
+			assertErrorIs(t, err, ErrConflict)
+		})
+
+		t.Run("overriding an override", func(t *testing.T) {
+			again, err := service.AddQuestion(ctx, "9012", QuestionPostRequest{Title: "which meal?", Type: "number", Overrides: "1"})
+			assertNoError(t, err)
+			assertDeepEqual(t, effectiveIDs(t, service, "9012"), []string{again.ID, "2", "3"})
+		})
+	})
+
+	t.Run("only inherited questions can be overridden", func(t *testing.T) {
+		service := newService()
+
+		for _, overrides := range []string{"3", "4", "9999"} {
+			_, err := service.AddQuestion(ctx, "5678", QuestionPostRequest{Title: "what meal?", Type: "number", Overrides: overrides})
+			assertFieldErrors(t, err, FieldErrors{{ErrorQuestionNotInherited, "/overrides", ""}})
+		}
+	})
+
+	t.Run("a hidden question isn't asked in the category or its subcategories", func(t *testing.T) {
+		service := newService()
+
+		hidden, err := service.HideQuestion(ctx, "5678", "2")
+		assertNoError(t, err)
+		assertDeepEqual(t, hidden.HiddenIn, []string{"5678"})
+
+		assertDeepEqual(t, effectiveIDs(t, service, "5678"), []string{"1", "3"})
+		assertDeepEqual(t, effectiveIDs(t, service, "9012"), []string{"1", "3"})
+		assertDeepEqual(t, effectiveIDs(t, service, "1234"), []string{"1", "2"})
+
+		_, err = service.UnhideQuestion(ctx, "5678", "2")
+		assertNoError(t, err)
+		assertDeepEqual(t, effectiveIDs(t, service, "9012"), []string{"1", "2", "3"})
+	})
+
+	t.Run("only inherited questions can be hidden", func(t *testing.T) {
+		service := newService()
+
+		_, err := service.HideQuestion(ctx, "5678", "3")
+		assertFieldErrors(t, err, FieldErrors{{ErrorQuestionNotInherited, "", ""}})
+
+		_, err = service.HideQuestion(ctx, "1234", "1")
+		assertFieldErrors(t, err, FieldErrors{{ErrorQuestionNotInherited, "", ""}})
+
+		_, err = service.UnhideQuestion(ctx, "5678", "1")
+		assertErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("the category must exist", func(t *testing.T) {
+		service := newService()
+
+		_, err := service.ListEffectiveQuestions(ctx, "9999")
+		assertErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestServiceDependencies(t *testing.T) {
+	three := float64(3)
+
+	newService := func() *Service {
+		categoryStore := NewInMemoryCategoryStore(&CategoryList{
+			Categories: []Category{
+				{ID: "1234", Name: "accommodation"},
+				{ID: "5678", Name: "hotel", ParentID: "1234"},
+				{ID: "9012", Name: "food & drink"},
+			},
+		})
+		questionStore := NewInMemoryQuestionStore(&QuestionList{
+			Questions: []Question{
+				{ID: "1", Title: "what kind?", CategoryID: "1234", Type: "string", Options: OptionList{
+					{ID: "a", Title: "hotel"},
+					{ID: "b", Title: "hostel"},
+				}},
+				{ID: "2", Title: "star rating?", CategoryID: "1234", Type: "number", DependsOn: &Dependency{QuestionID: "1", OptionID: "a"}},
+				{ID: "3", Title: "any notes?", CategoryID: "1234", Type: "text"},
+				{ID: "4", Title: "which meal?", CategoryID: "9012", Type: "string"},
+			},
+		})
+		return NewService(categoryStore, questionStore, nil)
+	}
+
+	t.Run("a follow-up can depend on an inherited question", func(t *testing.T) {
+		service := newService()
+
+		followUp, err := service.AddQuestion(ctx, "5678", QuestionPostRequest{Title: "spa visited?", Type: "boolean", DependsOn: &Dependency{QuestionID: "2", Min: &three}})
+		assertNoError(t, err)
+		assertDeepEqual(t, followUp.DependsOn, &Dependency{QuestionID: "2", Min: &three})
+	})
+
+	t.Run("a follow-up can depend on a yes or no answer", func(t *testing.T) {
+		service := newService()
+		yes := true
+
+		hotel, err := service.AddQuestion(ctx, "9012", QuestionPostRequest{Title: "was it a hotel?", Type: "boolean"})
+		assertNoError(t, err)
+		rating, err := service.AddQuestion(ctx, "9012", QuestionPostRequest{Title: "star rating?", Type: "number", DependsOn: &Dependency{QuestionID: hotel.ID, Equals: &yes}})
+		assertNoError(t, err)
+
+		next, err := service.NextQuestions(ctx, "9012", []Answer{{QuestionID: hotel.ID, Value: true}})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(next.Questions), 2)
+		assertDeepEqual(t, next.Questions[1], rating)
+
+		next, err = service.NextQuestions(ctx, "9012", []Answer{{QuestionID: hotel.ID, Value: false}})
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(next.Questions), 1)
+		assertStringsEqual(t, next.Questions[0].ID, "4")
+	})
+
+	t.Run("the question depended on must be asked in the category", func(t *testing.T) {
+		service := newService()
+
+		for _, id := range []string{"4", "9999"} {
+			_, err := service.AddQuestion(ctx, "1234", QuestionPostRequest{Title: "spa visited?", Type: "boolean", DependsOn: &Dependency{QuestionID: id, OptionID: "a"}})
+			assertFieldErrors(t, err, FieldErrors{{ErrorDependencyNotFound, "/dependsOn/questionID", ""}})
+		}
+
+		_, err := service.AddQuestion(ctx, "1234", QuestionPostRequest{Title: "spa visited?", Type: "boolean", DependsOn: &Dependency{OptionID: "a"}})
+		assertFieldErrors(t, err, FieldErrors{{ErrorFieldMissing, "/dependsOn/questionID", ""}})
+	})
+
+	t.Run("the condition must suit the question depended on", func(t *testing.T) {
+		service := newService()
+
+		_, err := service.AddQuestion(ctx, "1234", QuestionPostRequest{Title: "spa visited?", Type: "boolean", DependsOn: &Dependency{QuestionID: "1", OptionID: "c"}})
+		assertFieldErrors(t, err, FieldErrors{{ErrorDependencyOptionNotFound, "/dependsOn/optionID", ""}})
+
+		_, err = service.UpdateQuestion(ctx, "1234", "3", QuestionPatchRequest{DependsOn: &Dependency{QuestionID: "3", OptionID: "a"}})
+		assertFieldErrors(t, err, FieldErrors{{ErrorInvalidDependency, "/dependsOn", ""}})
+	})
+
+	t.Run("dependencies can't form a cycle", func(t *testing.T) {
+		service := newService()
+
+		_, err := service.UpdateQuestion(ctx, "1234", "1", QuestionPatchRequest{DependsOn: &Dependency{QuestionID: "2", Min: &three}})
+		assertFieldErrors(t, err, FieldErrors{{ErrorDependencyCycle, "/dependsOn/questionID", ""}})
+
+		_, err = service.UpdateQuestion(ctx, "1234", "1", QuestionPatchRequest{DependsOn: &Dependency{QuestionID: "1", OptionID: "a"}})
+		assertFieldErrors(t, err, FieldErrors{{ErrorDependencyCycle, "/dependsOn/questionID", ""}})
+	})
+
+	t.Run("a question stops being a follow-up given an empty dependency", func(t *testing.T) {
+		service := newService()
+
+		updated, err := service.UpdateQuestion(ctx, "1234", "2", QuestionPatchRequest{DependsOn: &Dependency{}})
+		assertNoError(t, err)
+		if updated.DependsOn != nil {
+			t.Errorf("got dependency %v wanted none", updated.DependsOn)
+		}
+	})
+
+	t.Run("next questions are those asked & unanswered", func(t *testing.T) {
+		service := newService()
+
+		nextIDs := func(t *testing.T, answers []Answer) []string {
+			t.Helper()
+			next, err := service.NextQuestions(ctx, "5678", answers)
+			assertNoError(t, err)
+
+			ids := []string{}
+			for _, q := range next.Questions {
+				ids = append(ids, q.ID)
+			}
+			return ids
+		}
+
+		assertDeepEqual(t, nextIDs(t, nil), []string{"1", "3"})
+		assertDeepEqual(t, nextIDs(t, []Answer{{QuestionID: "1", Value: "a"}}), []string{"2", "3"})
+		assertDeepEqual(t, nextIDs(t, []Answer{{QuestionID: "1", Value: "b"}}), []string{"3"})
+		assertDeepEqual(t, nextIDs(t, []Answer{{QuestionID: "1", Value: "b"}, {QuestionID: "3", Value: "quiet"}}), []string{})
+	})
+
+	t.Run("follow-ups depending on a question or option keep it in use", func(t *testing.T) {
+		service := newService()
+
+		for _, remove := range []func() error{
+			func() error { return service.RemoveQuestion(ctx, "1234", "1", false) },
+			func() error { return service.RemoveOption(ctx, "1234", "1", "a", false) },
+		} {
+			var inUse *InUseError
+			if err := remove(); !errors.As(err, &inUse) {
+				t.Fatalf("got error '%v' wanted an in use error", err)
+			}
+			assertNumbersEqual(t, inUse.Questions, 1)
+			assertNumbersEqual(t, inUse.Transactions, 0)
+		}
+
+		assertNoError(t, service.RemoveOption(ctx, "1234", "1", "b", false))
+	})
+
+	t.Run("cascading stops the follow-ups depending on it", func(t *testing.T) {
+		cases := map[string]struct {
+			remove func(*Service) error
+			next   []string
+		}{
+			"question": {func(service *Service) error { return service.RemoveQuestion(ctx, "1234", "1", true) }, []string{"2", "3"}},
+			"option":   {func(service *Service) error { return service.RemoveOption(ctx, "1234", "1", "a", true) }, []string{"1", "2", "3"}},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				service := newService()
+				assertNoError(t, c.remove(service))
+
+				followUp, err := service.GetQuestion(ctx, "2")
+				assertNoError(t, err)
+				if followUp.DependsOn != nil {
+					t.Errorf("got dependency %v wanted none", followUp.DependsOn)
+				}
+
+				next, err := service.NextQuestions(ctx, "5678", nil)
+				assertNoError(t, err)
+
+				var ids []string
+				for _, q := range next.Questions {
+					ids = append(ids, q.ID)
+				}
+				assertDeepEqual(t, ids, c.next)
+			})
+		}
+	})
+
+	t.Run("the answers so far must be valid", func(t *testing.T) {
+		service := newService()
+
+		_, err := service.NextQuestions(ctx, "5678", []Answer{{QuestionID: "1", Value: "b"}, {QuestionID: "2", Value: float64(4)}})
+		assertFieldErrors(t, err, FieldErrors{{ErrorAnswerNotAsked, "/answers/1/questionID", ""}})
+
+		_, err = service.NextQuestions(ctx, "9999", nil)
+		assertErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestServiceTransactions(t *testing.T) {
+	timestamp := time.Date(2019, time.March, 1, 12, 30, 0, 0, time.UTC)
+	amount := int64(-350)
+
+	t.Run("every problem with a new transaction is reported at once", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.AddTransaction(ctx, TransactionPostRequest{Merchant: "Pret"})
+
+		assertFieldErrors(t, err, FieldErrors{
+			{ErrorFieldMissing, "/amount", ""},
+			{ErrorCurrencyEmpty, "/currency", ""},
+			{ErrorTimestampEmpty, "/timestamp", ""},
+		})
+	})
+
+	t.Run("the category must exist", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.AddTransaction(ctx, TransactionPostRequest{Amount: &amount, Currency: "GBP", Timestamp: timestamp, CategoryID: "9999"})
+
+		assertFieldErrors(t, err, FieldErrors{{ErrorCategoryNotFound, "/categoryID", ""}})
+	})
+
+	t.Run("defaults answer the questions left unanswered", func(t *testing.T) {
+		service, _ := newTestService(t)
+		_, err := service.UpdateQuestion(ctx, "1234", "1", QuestionPatchRequest{Constraints: &Constraints{Required: true, Default: float64(1)}})
+		assertNoError(t, err)
+
+		added, err := service.AddTransaction(ctx, TransactionPostRequest{Amount: &amount, Currency: "GBP", Timestamp: timestamp, CategoryID: "5678"})
+
+		assertNoError(t, err)
+		assertDeepEqual(t, added.Answers, []Answer{{QuestionID: "1", Value: float64(1)}})
+	})
+
+	t.Run("categorising returns the transaction as it was too", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		previous, categorised, err := service.CategoriseTransaction(ctx, "abcdef", "", nil)
+
+		assertNoError(t, err)
+		assertStringsEqual(t, previous.CategoryID, "5678")
+		assertStringsEqual(t, categorised.CategoryID, "")
+		assertNumbersEqual(t, len(categorised.Answers), 0)
+	})
+
+	t.Run("answers are checked against the category's questions", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, _, err := service.CategoriseTransaction(ctx, "abcdef", "5678", []Answer{{QuestionID: "1", Value: "two"}})
+
+		assertFieldErrors(t, err, FieldErrors{{ErrorInvalidAnswer, "/answers/0/value", ""}})
+	})
+
+	t.Run("the transaction must exist", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, _, err := service.CategoriseTransaction(ctx, "zzzzzz", "", nil)
+
+		assertErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("imports are filed under their category with its defaults", func(t *testing.T) {
+		service, _ := newTestService(t)
+		_, err := service.UpdateQuestion(ctx, "1234", "1", QuestionPatchRequest{Constraints: &Constraints{Default: float64(1)}})
+		assertNoError(t, err)
+
+		imported, err := service.ImportTransaction(ctx, Transaction{MonzoID: "tx_1", Amount: amount, Currency: "GBP", Timestamp: timestamp, CategoryID: "5678"})
+
+		assertNoError(t, err)
+		assertStringsEqual(t, imported.CategoryID, "5678")
+		assertDeepEqual(t, imported.Answers, []Answer{{QuestionID: "1", Value: float64(1)}})
+	})
+
+	t.Run("imports are left uncategorised where they can't be categorised", func(t *testing.T) {
+		service, _ := newTestService(t)
+		_, err := service.UpdateQuestion(ctx, "1234", "1", QuestionPatchRequest{Constraints: &Constraints{Required: true}})
+		assertNoError(t, err)
+
+		// a required question without a default can't be answered by an import
+		imported, err := service.ImportTransaction(ctx, Transaction{MonzoID: "tx_1", Amount: amount, Currency: "GBP", Timestamp: timestamp, CategoryID: "5678"})
+		assertNoError(t, err)
+		assertStringsEqual(t, imported.CategoryID, "")
+		assertNumbersEqual(t, len(imported.Answers), 0)
+
+		imported, err = service.ImportTransaction(ctx, Transaction{MonzoID: "tx_2", Amount: amount, Currency: "GBP", Timestamp: timestamp, CategoryID: "9999"})
+		assertNoError(t, err)
+		assertStringsEqual(t, imported.CategoryID, "")
+	})
+
+	t.Run("re-importing is a conflict", func(t *testing.T) {
+		service, _ := newTestService(t)
+		_, err := service.ImportTransaction(ctx, Transaction{MonzoID: "tx_1", Amount: amount, Currency: "GBP", Timestamp: timestamp})
+		assertNoError(t, err)
+
+		_, err = service.ImportTransaction(ctx, Transaction{MonzoID: "tx_1", Amount: amount, Currency: "GBP", Timestamp: timestamp})
+
+		assertErrorIs(t, err, ErrConflict)
+	})
+}
+
+func TestServiceSpendingReport(t *testing.T) {
+	t.Run("every problem with the request is reported at once", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.SpendingReport(ctx, SpendingReportRequest{From: "yesterday", To: "2019-13-01", GroupBy: "merchant"})
+
+		assertFieldErrors(t, err, FieldErrors{
+			{ErrorInvalidFrom, "", `"from" is not a date or RFC3339 timestamp`},
+			{ErrorInvalidTo, "", `"to" is not a date or RFC3339 timestamp`},
+			{ErrorInvalidGroupBy, "", ""},
+		})
+	})
+
+	t.Run("from must not be after to", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.SpendingReport(ctx, SpendingReportRequest{From: "2019-04-01", To: "2019-03-01"})
+
+		assertFieldErrors(t, err, FieldErrors{{ErrorFromAfterTo, "", ""}})
+	})
+
+	t.Run("it reports on every transaction grouped by category by default", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		report, err := service.SpendingReport(ctx, SpendingReportRequest{})
+
+		assertNoError(t, err)
+		assertStringsEqual(t, report.GroupBy, GroupByCategory)
+		if report.From != nil || report.To != nil {
+			t.Errorf("got range %v to %v wanted it open", report.From, report.To)
+		}
+		assertNumbersEqual(t, len(report.Groups), 2)
+	})
+}
@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+// AddTransaction adds a transaction, see ValidateTransactionPostRequest,
+// its category & answers being checked as CategoriseTransaction checks them
+// It needs the Service to have a transaction store
+func (s *Service) AddTransaction(ctx context.Context, transaction TransactionPostRequest) (Transaction, error) {
+	if err := ValidateTransactionPostRequest(transaction); err != nil {
+		return Transaction{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	answers, err := s.categorisation(ctx, transaction.CategoryID, transaction.Answers)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	return s.transactions.AddTransaction(ctx, Transaction{
+		Amount:     *transaction.Amount,
+		Currency:   transaction.Currency,
+		Merchant:   transaction.Merchant,
+		Timestamp:  transaction.Timestamp,
+		CategoryID: transaction.CategoryID,
+		Answers:    answers,
+	})
+}
+
+// ImportTransaction stores a transaction from elsewhere (e.g. Monzo), returning ErrConflict if it already has been
+// It's filed under its category with the defaults of the questions asked there, see WithDefaultAnswers,
+// but left uncategorised if the category doesn't exist or its answers aren't valid there,
+// e.g. a required question without a default can't be answered by an import
+// It needs the Service to have a transaction store
+func (s *Service) ImportTransaction(ctx context.Context, transaction Transaction) (Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if transaction.CategoryID != "" {
+		answers, err := s.categorisation(ctx, transaction.CategoryID, transaction.Answers)
+		var fieldErrs FieldErrors
+		switch {
+		case errors.As(err, &fieldErrs):
+			transaction.CategoryID, transaction.Answers = "", nil
+		case err != nil:
+			return Transaction{}, err
+		default:
+			transaction.Answers = answers
+		}
+	}
+
+	return s.transactions.ImportTransaction(ctx, transaction)
+}
+
+// CategoriseTransaction (re)categorises a transaction, replacing all of its answers,
+// returning the transaction as it was before along with how it is now
+// The category must exist, unless it's "" to uncategorise the transaction,
+// and the answers must respond to the questions asked in it, see ValidateAnswers,
+// the defaults of any left unanswered being filled in, see WithDefaultAnswers
+// It needs the Service to have a transaction store
+func (s *Service) CategoriseTransaction(ctx context.Context, transactionID, categoryID string, answers []Answer) (previous, categorised Transaction, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, err = s.transactions.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return Transaction{}, Transaction{}, err
+	}
+
+	if answers, err = s.categorisation(ctx, categoryID, answers); err != nil {
+		return Transaction{}, Transaction{}, err
+	}
+
+	categorised, err = s.transactions.CategoriseTransaction(ctx, transactionID, categoryID, answers)
+	if err != nil {
+		return Transaction{}, Transaction{}, err
+	}
+
+	return previous, categorised, nil
+}
+
+// categorisation checks the category exists (unless it's "") and that the answers respond to the questions
+// asked in it, including those it inherits, returning them along with the defaults of any left unanswered
+// The category is part of the request here, so its not existing is a problem with the request's categoryID
+func (s *Service) categorisation(ctx context.Context, categoryID string, answers []Answer) ([]Answer, error) {
+	var questions QuestionList
+
+	if categoryID != "" {
+		err := s.ensureCategoryExists(ctx, categoryID)
+		if errors.Is(err, ErrNotFound) {
+			return nil, FieldErrors{{ErrorCategoryNotFound, "/categoryID", ""}}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		effective, err := s.effectiveQuestions(ctx, categoryID)
+		if err != nil {
+			return nil, err
+		}
+		questions.Questions = effective.questions
+	}
+
+	if err := ValidateAnswers(questions, answers); err != nil {
+		return nil, err
+	}
+
+	return WithDefaultAnswers(questions, answers), nil
+}
@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+
+	// registers the "sqlite3" database/sql driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// migrations are applied in order at startup,
+// a migration's version is its index in the slice plus one
+// only ever append to this list, never edit a migration that has shipped
+var migrations = []string{
+	`CREATE TABLE categories (
+		id        TEXT PRIMARY KEY,
+		name      TEXT NOT NULL,
+		parent_id TEXT REFERENCES categories(id) ON DELETE CASCADE
+	)`,
+	`CREATE TABLE questions (
+		id          TEXT PRIMARY KEY,
+		title       TEXT NOT NULL,
+		category_id TEXT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+		type        TEXT NOT NULL
+	);
+	CREATE TABLE options (
+		id          TEXT PRIMARY KEY,
+		question_id TEXT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+		title       TEXT NOT NULL
+	)`,
+	`CREATE TABLE transactions (
+		id          TEXT PRIMARY KEY,
+		amount      INTEGER NOT NULL,
+		currency    TEXT NOT NULL,
+		merchant    TEXT NOT NULL,
+		timestamp   TEXT NOT NULL,
+		category_id TEXT REFERENCES categories(id) ON DELETE SET NULL
+	);
+	CREATE TABLE answers (
+		transaction_id TEXT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+		question_id    TEXT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+		value          TEXT NOT NULL,
+		PRIMARY KEY (transaction_id, question_id)
+	)`,
+	`ALTER TABLE transactions ADD COLUMN monzo_id TEXT;
+	CREATE UNIQUE INDEX transactions_monzo_id ON transactions (monzo_id)`,
+	`ALTER TABLE categories ADD COLUMN owner TEXT NOT NULL DEFAULT '';
+	ALTER TABLE questions ADD COLUMN owner TEXT NOT NULL DEFAULT '';
+	ALTER TABLE transactions ADD COLUMN owner TEXT NOT NULL DEFAULT '';
+	DROP INDEX transactions_monzo_id;
+	CREATE UNIQUE INDEX transactions_owner_monzo_id ON transactions (owner, monzo_id)`,
+	`ALTER TABLE questions ADD COLUMN constraints TEXT`,
+	`ALTER TABLE questions ADD COLUMN overrides TEXT NOT NULL DEFAULT '';
+	CREATE TABLE hidden_questions (
+		question_id TEXT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+		category_id TEXT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+		PRIMARY KEY (question_id, category_id)
+	)`,
+	`ALTER TABLE questions ADD COLUMN depends_on TEXT`,
+}
+
+// NewSQLiteDB opens the SQLite database at path with foreign keys enforced,
+// and migrates it to the latest schema version
+// path may be ":memory:" for a throwaway database
+func NewSQLiteDB(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", path+"?_foreign_keys=1")
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite only allows a single writer, and every connection
+	// to ":memory:" would otherwise get its own empty database
+	db.SetMaxOpenConns(1)
+
+	if err := Migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Migrate applies any migrations the database hasn't seen yet,
+// each in its own transaction alongside the bump of the schema version
+func Migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`)
+	if err != nil {
+		return err
+	}
+
+	version, err := SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, i+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the version of the latest migration applied to the database
+func SchemaVersion(db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
+
+// nullString stores "" as NULL so it can satisfy a foreign key
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// queryer is what *sql.DB & *sql.Tx have in common for querying,
+// so a check can be made inside the transaction of the change it guards
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// exists runs a SELECT EXISTS (...) query
+func exists(ctx context.Context, q queryer, query string, args ...interface{}) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, query, args...).Scan(&exists)
+	return exists, err
+}
+
+// inTx runs f in a transaction, committing it if f succeeds and rolling it back if not
+// with a single connection, f must only use tx or it will wait on itself forever
+func inTx(ctx context.Context, db *sql.DB, f func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ensureRowAffected returns a not found error titled title if an UPDATE or DELETE matched no rows
+func ensureRowAffected(result sql.Result, title string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound(title)
+	}
+	return nil
+}
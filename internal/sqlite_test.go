@@ -0,0 +1,47 @@
+package internal
+
+import "testing"
+
+func TestMigrate(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	defer db.Close()
+
+	t.Run("applies every migration", func(t *testing.T) {
+		got, err := SchemaVersion(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertNumbersEqual(t, got, len(migrations))
+	})
+
+	t.Run("is a no-op when already up to date", func(t *testing.T) {
+		if err := Migrate(db); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := SchemaVersion(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertNumbersEqual(t, got, len(migrations))
+	})
+}
+
+func TestSQLiteForeignKeys(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	defer db.Close()
+
+	t.Run("category parentID must exist", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO categories (id, name, parent_id) VALUES ('1', 'hostel', '5678')`)
+		if err == nil {
+			t.Fatal("expected a foreign key error")
+		}
+	})
+
+	t.Run("question categoryID must exist", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO questions (id, title, category_id, type) VALUES ('1', 'how many nights?', '5678', 'number')`)
+		if err == nil {
+			t.Fatal("expected a foreign key error")
+		}
+	})
+}
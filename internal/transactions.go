@@ -0,0 +1,271 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+)
+
+// TransactionStore is an interface that when implemented,
+// provides methods for manipulating a store of transactions,
+// including some helper functions for querying the store
+// Every method acts only on the transactions owned by the user in ctx (see WithUser)
+// Errors wrap ErrNotFound when the transaction doesn't exist,
+// and ErrConflict when importing a transaction the user already has
+type TransactionStore interface {
+	ListTransactions(ctx context.Context) (TransactionList, error)
+	GetTransaction(ctx context.Context, transactionID string) (Transaction, error)
+	AddTransaction(ctx context.Context, transaction Transaction) (Transaction, error)
+	ImportTransaction(ctx context.Context, transaction Transaction) (Transaction, error)
+	CategoriseTransaction(ctx context.Context, transactionID, categoryID string, answers []Answer) (Transaction, error)
+	DeleteTransaction(ctx context.Context, transactionID string) error
+	ReassignTransactions(ctx context.Context, fromCategoryID, toCategoryID string) error
+	DeleteAnswersForQuestion(ctx context.Context, questionID string) error
+	DeleteAnswersForOption(ctx context.Context, questionID, optionID string) error
+
+	TransactionIDExists(ctx context.Context, transactionID string) (bool, error)
+	CountTransactionsForCategory(ctx context.Context, categoryID string) (int, error)
+	CountTransactionsAnsweringQuestion(ctx context.Context, questionID string) (int, error)
+	CountTransactionsAnsweringOption(ctx context.Context, questionID, optionID string) (int, error)
+}
+
+// TransactionList stores multiple Transactions
+type TransactionList struct {
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Transaction stores a single item of spending
+// Amount is in minor units (e.g. pence) and negative for spending, as Monzo does
+// MonzoID is set for transactions imported from Monzo
+// CategoryID is "" while the transaction is uncategorised,
+// and Answers respond to the Questions of that category
+// Owner is the user the transaction belongs to, see WithUser
+type Transaction struct {
+	ID         string    `json:"id"`
+	MonzoID    string    `json:"monzoID,omitempty"`
+	Amount     int64     `json:"amount"`
+	Currency   string    `json:"currency"`
+	Merchant   string    `json:"merchant"`
+	Timestamp  time.Time `json:"timestamp"`
+	CategoryID string    `json:"categoryID"`
+	Answers    []Answer  `json:"answers"`
+	Owner      string    `json:"owner,omitempty"`
+}
+
+// TransactionPostRequest is a Transaction with no ID
+// Used for sending new Transactions to the server
+// Amount is a pointer so that a missing amount can be told apart from zero
+type TransactionPostRequest struct {
+	Amount     *int64    `json:"amount"`
+	Currency   string    `json:"currency"`
+	Merchant   string    `json:"merchant"`
+	Timestamp  time.Time `json:"timestamp"`
+	CategoryID string    `json:"categoryID"`
+	Answers    []Answer  `json:"answers"`
+}
+
+// ValidateTransactionPostRequest checks a new transaction's amount, currency & timestamp,
+// returning FieldErrors with every problem found
+// Its category & answers are checked against the stores, see Service.AddTransaction
+func ValidateTransactionPostRequest(transaction TransactionPostRequest) error {
+	var problems FieldErrors
+
+	if transaction.Amount == nil {
+		problems = append(problems, FieldError{ErrorFieldMissing, "/amount", ""})
+	}
+
+	if transaction.Currency == "" {
+		problems = append(problems, FieldError{ErrorCurrencyEmpty, "/currency", ""})
+	}
+
+	if transaction.Timestamp.IsZero() {
+		problems = append(problems, FieldError{ErrorTimestampEmpty, "/timestamp", ""})
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// Answer stores the response to one of a category's Questions
+// Value depends on the question's type, see the QuestionType constants
+type Answer struct {
+	QuestionID string      `json:"questionID"`
+	Value      interface{} `json:"value"`
+}
+
+// ValidateAnswers checks answers against the questions they respond to,
+// returning FieldErrors with every problem found, pointing into the request's answers
+// Every required question that's asked must be answered, unless it has a default, see WithDefaultAnswers,
+// and as a missing answer has nowhere to point to, its problem's detail names the question
+func ValidateAnswers(questions QuestionList, answers []Answer) error {
+	problems := validatePartialAnswers(questions, answers)
+
+	answered := make(map[string]bool)
+	for _, a := range answers {
+		answered[a.QuestionID] = true
+	}
+
+	// defaults may answer the questions follow-ups depend on
+	asked := askedQuestions(questions, WithDefaultAnswers(questions, answers))
+	for _, q := range questions.Questions {
+		if q.Constraints != nil && q.Constraints.Required && q.Constraints.Default == nil && asked[q.ID] && !answered[q.ID] {
+			problems = append(problems, FieldError{ErrorAnswerRequired, "/answers", fmt.Sprintf("question %s is unanswered", q.ID)})
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// validatePartialAnswers checks each of the answers, but not that every required question is answered,
+// as more answers are still to come
+// Answers to follow-ups that the other answers mean aren't asked are problems too
+func validatePartialAnswers(questions QuestionList, answers []Answer) FieldErrors {
+	var problems FieldErrors
+	answered := make(map[string]bool)
+	asked := askedQuestions(questions, WithDefaultAnswers(questions, answers))
+
+	for i, a := range answers {
+		if answered[a.QuestionID] {
+			problems = append(problems, FieldError{ErrorDuplicateAnswer, fmt.Sprintf("/answers/%d/questionID", i), ""})
+			continue
+		}
+		answered[a.QuestionID] = true
+
+		question, found := findQuestion(questions, a.QuestionID)
+		if !found {
+			problems = append(problems, FieldError{ErrorAnswerQuestionNotFound, fmt.Sprintf("/answers/%d/questionID", i), ""})
+			continue
+		}
+
+		if !asked[a.QuestionID] {
+			problems = append(problems, FieldError{ErrorAnswerNotAsked, fmt.Sprintf("/answers/%d/questionID", i), ""})
+			continue
+		}
+
+		if problem := answerProblem(question, a.Value); problem != "" {
+			problems = append(problems, FieldError{problem, fmt.Sprintf("/answers/%d/value", i), ""})
+		}
+	}
+
+	return problems
+}
+
+func findQuestion(questions QuestionList, questionID string) (Question, bool) {
+	for _, q := range questions.Questions {
+		if q.ID == questionID {
+			return q, true
+		}
+	}
+	return Question{}, false
+}
+
+func isValidAnswerValue(question Question, value interface{}) bool {
+	switch question.Type {
+	case QuestionTypeNumber:
+		_, isNumber := value.(float64)
+		return isNumber
+	case QuestionTypeBoolean:
+		_, isBool := value.(bool)
+		return isBool
+	case QuestionTypeDate:
+		date, isString := value.(string)
+		if !isString {
+			return false
+		}
+		_, err := time.Parse(answerDateLayout, date)
+		return err == nil
+	case QuestionTypeMoney:
+		return isValidMoney(value)
+	case QuestionTypeText:
+		text, isString := value.(string)
+		return isString && text != ""
+	case QuestionTypeString:
+		optionID, isString := value.(string)
+		return isString && question.Options.indexOf(optionID) != -1
+	case QuestionTypeMultiSelect:
+		values, isList := value.([]interface{})
+		if !isList || len(values) == 0 {
+			return false
+		}
+		chosen := map[string]bool{}
+		for _, v := range values {
+			optionID, isString := v.(string)
+			if !isString || chosen[optionID] || question.Options.indexOf(optionID) == -1 {
+				return false
+			}
+			chosen[optionID] = true
+		}
+		return true
+	}
+	return false
+}
+
+// answerDateLayout is how "date" answers are written
+const answerDateLayout = "2006-01-02"
+
+var currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// isValidMoney checks a "money" answer is exactly a whole amount & an ISO 4217 currency code,
+// as JSON decodes it, e.g. {"amount": 1250, "currency": "GBP"}
+func isValidMoney(value interface{}) bool {
+	money, isObject := value.(map[string]interface{})
+	if !isObject || len(money) != 2 {
+		return false
+	}
+
+	amount, isNumber := money["amount"].(float64)
+	currency, isString := money["currency"].(string)
+	return isNumber && amount == math.Trunc(amount) && isString && currencyRegex.MatchString(currency)
+}
+
+// chosenOptions returns the option IDs an answer chooses, for the types answered by choosing,
+// "string" answers being a single ID and "multiselect" answers a list of them
+func chosenOptions(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var optionIDs []string
+		for _, item := range v {
+			if optionID, ok := item.(string); ok {
+				optionIDs = append(optionIDs, optionID)
+			}
+		}
+		return optionIDs
+	}
+	return nil
+}
+
+// isOptionChosen reports whether an answer's value chooses the option
+func isOptionChosen(value interface{}, optionID string) bool {
+	for _, chosen := range chosenOptions(value) {
+		if chosen == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+// unchooseOption returns an answer's value without the option chosen,
+// keep is false when nothing would be left chosen, so the answer should be removed
+func unchooseOption(value interface{}, optionID string) (remaining interface{}, keep bool) {
+	values, isList := value.([]interface{})
+	if !isList {
+		return value, !isOptionChosen(value, optionID)
+	}
+
+	var rest []interface{}
+	for _, v := range values {
+		if v != optionID {
+			rest = append(rest, v)
+		}
+	}
+	return rest, len(rest) > 0
+}
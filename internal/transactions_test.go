@@ -0,0 +1,213 @@
+package internal
+
+import "testing"
+
+func TestValidateAnswers(t *testing.T) {
+	min, max, step, maxLength := float64(1), float64(30), float64(0.5), 20
+
+	questions := QuestionList{
+		Questions: []Question{
+			Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number"},
+			Question{ID: "2", Title: "which meal?", CategoryID: "1234", Type: "string", Options: OptionList{
+				{ID: "a", Title: "brekkie"},
+			}},
+			Question{ID: "4", Title: "work expense?", CategoryID: "1234", Type: "boolean"},
+			Question{ID: "5", Title: "when did it start?", CategoryID: "1234", Type: "date"},
+			Question{ID: "6", Title: "how much was the deposit?", CategoryID: "1234", Type: "money"},
+			Question{ID: "7", Title: "any notes?", CategoryID: "1234", Type: "text", Constraints: &Constraints{MaxLength: &maxLength}},
+			Question{ID: "8", Title: "which meals?", CategoryID: "1234", Type: "multiselect", Options: OptionList{
+				{ID: "a", Title: "brekkie"},
+				{ID: "b", Title: "lunch"},
+			}},
+			Question{ID: "9", Title: "how many hours?", CategoryID: "1234", Type: "number", Constraints: &Constraints{
+				Min: &min, Max: &max, Step: &step, Unit: "hours",
+			}},
+		},
+	}
+
+	t.Run("valid answers", func(t *testing.T) {
+		answers := []Answer{
+			{QuestionID: "1", Value: float64(3)},
+			{QuestionID: "2", Value: "a"},
+			{QuestionID: "4", Value: false},
+			{QuestionID: "5", Value: "2019-03-01"},
+			{QuestionID: "6", Value: map[string]interface{}{"amount": float64(5000), "currency": "GBP"}},
+			{QuestionID: "7", Value: "booked late"},
+			{QuestionID: "8", Value: []interface{}{"b", "a"}},
+			{QuestionID: "9", Value: float64(1.5)},
+		}
+		if err := ValidateAnswers(questions, answers); err != nil {
+			t.Fatalf("expected answers to be valid, got '%s'", err)
+		}
+	})
+
+	cases := map[string]struct {
+		answers    []Answer
+		errorTitle string
+	}{
+		"question not in category": {
+			answers:    []Answer{{QuestionID: "3", Value: float64(3)}},
+			errorTitle: ErrorAnswerQuestionNotFound,
+		},
+		"question answered twice": {
+			answers:    []Answer{{QuestionID: "1", Value: float64(3)}, {QuestionID: "1", Value: float64(4)}},
+			errorTitle: ErrorDuplicateAnswer,
+		},
+		"number question given a string": {
+			answers:    []Answer{{QuestionID: "1", Value: "3"}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"string question given a number": {
+			answers:    []Answer{{QuestionID: "2", Value: float64(3)}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"string question given an unknown option": {
+			answers:    []Answer{{QuestionID: "2", Value: "b"}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"boolean question given a string": {
+			answers:    []Answer{{QuestionID: "4", Value: "true"}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"date question given a timestamp": {
+			answers:    []Answer{{QuestionID: "5", Value: "2019-03-01T12:30:00Z"}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"date question given an impossible date": {
+			answers:    []Answer{{QuestionID: "5", Value: "2019-02-30"}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"money question given a number": {
+			answers:    []Answer{{QuestionID: "6", Value: float64(5000)}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"money question given a fractional amount": {
+			answers:    []Answer{{QuestionID: "6", Value: map[string]interface{}{"amount": 50.5, "currency": "GBP"}}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"money question given an invalid currency": {
+			answers:    []Answer{{QuestionID: "6", Value: map[string]interface{}{"amount": float64(5000), "currency": "pounds"}}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"money question given an extra field": {
+			answers: []Answer{{QuestionID: "6", Value: map[string]interface{}{
+				"amount": float64(5000), "currency": "GBP", "note": "deposit",
+			}}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"text question given empty text": {
+			answers:    []Answer{{QuestionID: "7", Value: ""}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"multiselect question given a single option": {
+			answers:    []Answer{{QuestionID: "8", Value: "a"}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"multiselect question given no options": {
+			answers:    []Answer{{QuestionID: "8", Value: []interface{}{}}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"multiselect question given an option twice": {
+			answers:    []Answer{{QuestionID: "8", Value: []interface{}{"a", "a"}}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"multiselect question given an unknown option": {
+			answers:    []Answer{{QuestionID: "8", Value: []interface{}{"a", "c"}}},
+			errorTitle: ErrorInvalidAnswer,
+		},
+		"number below min": {
+			answers:    []Answer{{QuestionID: "9", Value: float64(-3)}},
+			errorTitle: ErrorAnswerOutOfRange,
+		},
+		"number above max": {
+			answers:    []Answer{{QuestionID: "9", Value: float64(10000)}},
+			errorTitle: ErrorAnswerOutOfRange,
+		},
+		"number between steps": {
+			answers:    []Answer{{QuestionID: "9", Value: float64(1.25)}},
+			errorTitle: ErrorAnswerOffStep,
+		},
+		"text longer than max length": {
+			answers:    []Answer{{QuestionID: "7", Value: "booked far too late, again"}},
+			errorTitle: ErrorAnswerTooLong,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateAnswers(questions, c.answers)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			assertStringsEqual(t, err.Error(), c.errorTitle)
+		})
+	}
+
+	t.Run("every problem is reported, pointing at its answer", func(t *testing.T) {
+		answers := []Answer{
+			{QuestionID: "1", Value: "3"},
+			{QuestionID: "3", Value: float64(3)},
+			{QuestionID: "1", Value: float64(4)},
+		}
+
+		err := ValidateAnswers(questions, answers)
+
+		got, ok := err.(FieldErrors)
+		if !ok {
+			t.Fatalf("got error '%v' wanted FieldErrors", err)
+		}
+		want := FieldErrors{
+			{ErrorInvalidAnswer, "/answers/0/value", ""},
+			{ErrorAnswerQuestionNotFound, "/answers/1/questionID", ""},
+			{ErrorDuplicateAnswer, "/answers/2/questionID", ""},
+		}
+		assertDeepEqual(t, got, want)
+	})
+}
+
+func TestValidateAnswersRequired(t *testing.T) {
+	questions := QuestionList{
+		Questions: []Question{
+			Question{ID: "1", Title: "how many nights?", CategoryID: "1234", Type: "number", Constraints: &Constraints{Required: true}},
+			Question{ID: "2", Title: "how many guests?", CategoryID: "1234", Type: "number", Constraints: &Constraints{Required: true, Default: float64(1)}},
+			Question{ID: "3", Title: "any notes?", CategoryID: "1234", Type: "text", Constraints: &Constraints{Required: true}},
+		},
+	}
+
+	t.Run("required question answered", func(t *testing.T) {
+		assertNoError(t, ValidateAnswers(questions, []Answer{{QuestionID: "1", Value: float64(2)}, {QuestionID: "3", Value: "quiet"}}))
+	})
+
+	t.Run("required questions left unanswered are each named", func(t *testing.T) {
+		err := ValidateAnswers(questions, []Answer{{QuestionID: "2", Value: float64(2)}})
+		assertFieldErrors(t, err, FieldErrors{
+			{ErrorAnswerRequired, "/answers", "question 1 is unanswered"},
+			{ErrorAnswerRequired, "/answers", "question 3 is unanswered"},
+		})
+	})
+}
+
+func TestValidateAnswersFollowUps(t *testing.T) {
+	questions := QuestionList{
+		Questions: []Question{
+			Question{ID: "1", Title: "what kind?", CategoryID: "1234", Type: "string", Options: OptionList{
+				{ID: "a", Title: "hotel"},
+				{ID: "b", Title: "hostel"},
+			}},
+			Question{ID: "2", Title: "star rating?", CategoryID: "1234", Type: "number", Constraints: &Constraints{Required: true},
+				DependsOn: &Dependency{QuestionID: "1", OptionID: "a"}},
+		},
+	}
+
+	t.Run("a required follow-up is only required once asked", func(t *testing.T) {
+		assertNoError(t, ValidateAnswers(questions, []Answer{{QuestionID: "1", Value: "b"}}))
+
+		err := ValidateAnswers(questions, []Answer{{QuestionID: "1", Value: "a"}})
+		assertFieldErrors(t, err, FieldErrors{{ErrorAnswerRequired, "/answers", "question 2 is unanswered"}})
+	})
+
+	t.Run("a follow-up that isn't asked can't be answered", func(t *testing.T) {
+		err := ValidateAnswers(questions, []Answer{{QuestionID: "1", Value: "b"}, {QuestionID: "2", Value: float64(4)}})
+		assertFieldErrors(t, err, FieldErrors{{ErrorAnswerNotAsked, "/answers/1/questionID", ""}})
+	})
+}
@@ -0,0 +1,19 @@
+package internal
+
+import "context"
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx identifying the user that store methods act for
+// every category, question and transaction is owned by the user that added it,
+// and is invisible to other users
+func WithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserFromContext returns the user set by WithUser,
+// or "" (a single shared user) if none was set
+func UserFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userKey{}).(string)
+	return userID
+}
@@ -0,0 +1,177 @@
+package monzo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the production Monzo API
+const DefaultBaseURL = "https://api.monzo.com"
+
+// pageLimit is how many transactions are requested per page
+const pageLimit = 100
+
+// Client calls the Monzo API on behalf of the owner of an OAuth access token
+type Client struct {
+	baseURL     string
+	accessToken string
+	httpClient  *http.Client
+}
+
+// NewClient returns a Client pointer for the Monzo API at baseURL,
+// normally DefaultBaseURL, but tests point it at an httptest server
+func NewClient(baseURL, accessToken string) *Client {
+	return &Client{
+		baseURL:     baseURL,
+		accessToken: accessToken,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Transaction is the subset of Monzo's transaction object that gets imported
+// Category is one of Monzo's built-in categories e.g. "eating_out"
+type Transaction struct {
+	ID          string    `json:"id"`
+	Amount      int64     `json:"amount"`
+	Currency    string    `json:"currency"`
+	Created     time.Time `json:"created"`
+	Category    string    `json:"category"`
+	Description string    `json:"description"`
+	Merchant    *Merchant `json:"merchant"`
+}
+
+// Merchant is only present on card transactions,
+// and only as an object when requested with expand[]=merchant
+type Merchant struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type transactionsResponse struct {
+	Transactions []Transaction `json:"transactions"`
+}
+
+// ListTransactions fetches every transaction on the account created after since
+// and before before, following Monzo's since cursor a page at a time
+// since may be an RFC3339 timestamp or a transaction ID, before an RFC3339 timestamp,
+// either may be "" to leave that end of the range open
+// Cancelling ctx abandons the listing, as does its deadline passing
+func (c *Client) ListTransactions(ctx context.Context, accountID, since, before string) ([]Transaction, error) {
+	var transactions []Transaction
+
+	for {
+		page, err := c.listTransactionsPage(ctx, accountID, since, before)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, page...)
+
+		if len(page) < pageLimit {
+			return transactions, nil
+		}
+
+		// pages are oldest first, so the next page starts after the last transaction
+		since = page[len(page)-1].ID
+	}
+}
+
+func (c *Client) listTransactionsPage(ctx context.Context, accountID, since, before string) ([]Transaction, error) {
+	query := url.Values{}
+	query.Set("account_id", accountID)
+	query.Set("expand[]", "merchant")
+	query.Set("limit", strconv.Itoa(pageLimit))
+	if since != "" {
+		query.Set("since", since)
+	}
+	if before != "" {
+		query.Set("before", before)
+	}
+
+	var response transactionsResponse
+	if err := c.get(ctx, "/transactions?"+query.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	return response.Transactions, nil
+}
+
+// PatchTransactionMetadata sets metadata keys on a transaction,
+// a key set to "" is removed by Monzo
+func (c *Client) PatchTransactionMetadata(ctx context.Context, transactionID string, metadata map[string]string) error {
+	form := url.Values{}
+	for key, value := range metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	return c.do(ctx, http.MethodPatch, "/transactions/"+url.PathEscape(transactionID), form, nil)
+}
+
+// APIError is any non-200 response from Monzo
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("monzo responded %d to %s %s: %s", e.StatusCode, e.Method, e.Path, e.Body)
+}
+
+// Temporary reports whether the request is worth retrying
+// i.e. Monzo was rate limiting or having problems of its own
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	return c.do(ctx, http.MethodGet, path, nil, v)
+}
+
+// do sends form (if any) url encoded, and decodes the response into v (if any)
+// The request is abandoned if ctx is cancelled first
+func (c *Client) do(ctx context.Context, method, path string, form url.Values, v interface{}) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return &APIError{method, path, res.StatusCode, string(resBody)}
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.Unmarshal(resBody, v)
+}
@@ -0,0 +1,81 @@
+package monzo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestListTransactions(t *testing.T) {
+	transactions := newTestTransactions(pageLimit*2 + 5)
+
+	t.Run("follows the since cursor across pages", func(t *testing.T) {
+		monzo := newFakeMonzo(transactions)
+		defer monzo.Close()
+
+		client := NewClient(monzo.URL, testAccessToken)
+		got, err := client.ListTransactions(ctx, testAccountID, "", "")
+		assertNoError(t, err)
+
+		assertNumbersEqual(t, len(got), len(transactions))
+		assertNumbersEqual(t, monzo.requests, 3)
+		if !reflect.DeepEqual(transactionIDs(got), transactionIDs(transactions)) {
+			t.Errorf("got transactions %v wanted %v", transactionIDs(got), transactionIDs(transactions))
+		}
+	})
+
+	t.Run("requests one empty page when the last page is exactly full", func(t *testing.T) {
+		monzo := newFakeMonzo(transactions[:pageLimit])
+		defer monzo.Close()
+
+		client := NewClient(monzo.URL, testAccessToken)
+		got, err := client.ListTransactions(ctx, testAccountID, "", "")
+		assertNoError(t, err)
+
+		assertNumbersEqual(t, len(got), pageLimit)
+		assertNumbersEqual(t, monzo.requests, 2)
+	})
+
+	t.Run("limits to the since and before range", func(t *testing.T) {
+		monzo := newFakeMonzo(transactions)
+		defer monzo.Close()
+
+		since := transactions[10].Created.Format("2006-01-02T15:04:05Z07:00")
+		before := transactions[20].Created.Format("2006-01-02T15:04:05Z07:00")
+
+		client := NewClient(monzo.URL, testAccessToken)
+		got, err := client.ListTransactions(ctx, testAccountID, since, before)
+		assertNoError(t, err)
+
+		assertNumbersEqual(t, len(got), 10)
+		assertStringsEqual(t, got[0].ID, transactions[10].ID)
+		assertStringsEqual(t, got[len(got)-1].ID, transactions[19].ID)
+	})
+
+	t.Run("returns an error for a bad access token", func(t *testing.T) {
+		monzo := newFakeMonzo(transactions)
+		defer monzo.Close()
+
+		client := NewClient(monzo.URL, "wrong-token")
+		_, err := client.ListTransactions(ctx, testAccountID, "", "")
+		if err == nil {
+			t.Fatal("wanted an error but didn't get one")
+		}
+	})
+
+	t.Run("stops once ctx is cancelled", func(t *testing.T) {
+		monzo := newFakeMonzo(transactions)
+		defer monzo.Close()
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		client := NewClient(monzo.URL, testAccessToken)
+		_, err := client.ListTransactions(cancelled, testAccountID, "", "")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error '%v' wanted %v", err, context.Canceled)
+		}
+		assertNumbersEqual(t, monzo.requests, 0)
+	})
+}
@@ -0,0 +1,126 @@
+package monzo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+// metadata keys written to Monzo transactions,
+// each answer is keyed on its question's ID
+const (
+	metadataCategoryKey       = "category_id"
+	metadataQuestionKeyPrefix = "question_"
+)
+
+// Exporter writes a transaction's category and answers back to Monzo
+// as metadata on the transaction it was imported from
+type Exporter struct {
+	client *Client
+
+	// DryRun logs the metadata that would be written instead of writing it
+	DryRun bool
+	// Retries is how many times a failed PATCH is retried
+	Retries int
+	// Backoff is the wait before the first retry, doubling with each retry after
+	Backoff time.Duration
+
+	// sleep is swapped out in tests to avoid waiting
+	sleep func(time.Duration)
+}
+
+// NewExporter returns an Exporter pointer that retries a few times over a few seconds
+func NewExporter(client *Client) *Exporter {
+	return &Exporter{
+		client:  client,
+		Retries: 3,
+		Backoff: 500 * time.Millisecond,
+		sleep:   time.Sleep,
+	}
+}
+
+// Metadata returns the Monzo metadata keys describing how a transaction is categorised
+func Metadata(t internal.Transaction) map[string]string {
+	metadata := map[string]string{
+		metadataCategoryKey: t.CategoryID,
+	}
+
+	for _, answer := range t.Answers {
+		metadata[metadataQuestionKeyPrefix+answer.QuestionID] = formatAnswer(answer.Value)
+	}
+
+	return metadata
+}
+
+// MetadataChange returns the Monzo metadata keys to write when a transaction is recategorised,
+// those of Metadata along with "" for any of previous's no longer set, which Monzo removes
+func MetadataChange(previous, t internal.Transaction) map[string]string {
+	metadata := Metadata(t)
+
+	for key := range Metadata(previous) {
+		if _, stillSet := metadata[key]; !stillSet {
+			metadata[key] = ""
+		}
+	}
+
+	return metadata
+}
+
+// formatAnswer writes strings (including dates) as they are & numbers in full,
+// and every other answer, e.g. money or multiselect, as the JSON it was given in
+func formatAnswer(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+// Export PATCHes the transaction's metadata onto its Monzo transaction,
+// removing what was written for how it was previously categorised (see MetadataChange),
+// backing off and retrying while Monzo is unavailable or rate limiting
+// Transactions that weren't imported from Monzo are skipped, and cancelling ctx stops the retries
+func (e *Exporter) Export(ctx context.Context, previous, t internal.Transaction) error {
+	if t.MonzoID == "" {
+		return nil
+	}
+
+	metadata := MetadataChange(previous, t)
+
+	if e.DryRun {
+		log.Printf("monzo export (dry run) %s %v", t.MonzoID, metadata)
+		return nil
+	}
+
+	backoff := e.Backoff
+	for attempt := 0; ; attempt++ {
+		err := e.client.PatchTransactionMetadata(ctx, t.MonzoID, metadata)
+		if err == nil || attempt == e.Retries || !isTemporary(err) || ctx.Err() != nil {
+			return err
+		}
+
+		log.Printf("monzo export of %s failed, retrying in %s %v", t.MonzoID, backoff, err)
+		e.sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isTemporary treats network errors as temporary as well as Monzo's own
+func isTemporary(err error) bool {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.Temporary()
+	}
+	return true
+}
@@ -0,0 +1,162 @@
+package monzo
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+func newTestExporter(monzo *fakeMonzo) (*Exporter, *[]time.Duration) {
+	exporter := NewExporter(NewClient(monzo.URL, testAccessToken))
+	var slept []time.Duration
+	exporter.sleep = func(d time.Duration) { slept = append(slept, d) }
+	return exporter, &slept
+}
+
+func TestExport(t *testing.T) {
+	transaction := internal.Transaction{
+		ID:         "abcdef",
+		MonzoID:    "tx_00001",
+		CategoryID: "1234",
+		Answers: []internal.Answer{
+			{QuestionID: "1", Value: float64(2)},
+			{QuestionID: "2", Value: "a"},
+		},
+	}
+	want := map[string]string{
+		"category_id": "1234",
+		"question_1":  "2",
+		"question_2":  "a",
+	}
+
+	t.Run("writes category and answers as metadata", func(t *testing.T) {
+		monzo := newFakeMonzo(nil)
+		defer monzo.Close()
+		exporter, slept := newTestExporter(monzo)
+
+		assertNoError(t, exporter.Export(ctx, internal.Transaction{}, transaction))
+
+		if !reflect.DeepEqual(monzo.metadata["tx_00001"], want) {
+			t.Errorf("got metadata %v wanted %v", monzo.metadata["tx_00001"], want)
+		}
+		assertNumbersEqual(t, len(*slept), 0)
+	})
+
+	t.Run("removes what no longer applies when recategorised", func(t *testing.T) {
+		monzo := newFakeMonzo(nil)
+		defer monzo.Close()
+		exporter, _ := newTestExporter(monzo)
+
+		assertNoError(t, exporter.Export(ctx, internal.Transaction{}, transaction))
+
+		recategorised := transaction
+		recategorised.CategoryID = "5678"
+		recategorised.Answers = []internal.Answer{{QuestionID: "3", Value: true}}
+
+		assertNoError(t, exporter.Export(ctx, transaction, recategorised))
+
+		want := map[string]string{
+			"category_id": "5678",
+			"question_3":  "true",
+		}
+		if !reflect.DeepEqual(monzo.metadata["tx_00001"], want) {
+			t.Errorf("got metadata %v wanted %v", monzo.metadata["tx_00001"], want)
+		}
+	})
+
+	t.Run("retries with backoff while Monzo is unavailable", func(t *testing.T) {
+		monzo := newFakeMonzo(nil)
+		defer monzo.Close()
+		monzo.failures = 2
+		exporter, slept := newTestExporter(monzo)
+
+		assertNoError(t, exporter.Export(ctx, internal.Transaction{}, transaction))
+
+		if !reflect.DeepEqual(monzo.metadata["tx_00001"], want) {
+			t.Errorf("got metadata %v wanted %v", monzo.metadata["tx_00001"], want)
+		}
+		wantSlept := []time.Duration{exporter.Backoff, exporter.Backoff * 2}
+		if !reflect.DeepEqual(*slept, wantSlept) {
+			t.Errorf("got backoffs %v wanted %v", *slept, wantSlept)
+		}
+	})
+
+	t.Run("gives up after the last retry", func(t *testing.T) {
+		monzo := newFakeMonzo(nil)
+		defer monzo.Close()
+		monzo.failures = 10
+		exporter, slept := newTestExporter(monzo)
+
+		if err := exporter.Export(ctx, internal.Transaction{}, transaction); err == nil {
+			t.Fatal("wanted an error but didn't get one")
+		}
+		assertNumbersEqual(t, monzo.requests, exporter.Retries+1)
+		assertNumbersEqual(t, len(*slept), exporter.Retries)
+	})
+
+	t.Run("doesn't retry client errors", func(t *testing.T) {
+		monzo := newFakeMonzo(nil)
+		defer monzo.Close()
+		monzo.failures = 1
+		monzo.failureStatus = http.StatusBadRequest
+		exporter, _ := newTestExporter(monzo)
+
+		if err := exporter.Export(ctx, internal.Transaction{}, transaction); err == nil {
+			t.Fatal("wanted an error but didn't get one")
+		}
+		assertNumbersEqual(t, monzo.requests, 1)
+	})
+
+	t.Run("writes nothing in dry run mode", func(t *testing.T) {
+		monzo := newFakeMonzo(nil)
+		defer monzo.Close()
+		exporter, _ := newTestExporter(monzo)
+		exporter.DryRun = true
+
+		assertNoError(t, exporter.Export(ctx, internal.Transaction{}, transaction))
+		assertNumbersEqual(t, monzo.requests, 0)
+	})
+
+	t.Run("skips transactions that didn't come from Monzo", func(t *testing.T) {
+		monzo := newFakeMonzo(nil)
+		defer monzo.Close()
+		exporter, _ := newTestExporter(monzo)
+
+		notFromMonzo := transaction
+		notFromMonzo.MonzoID = ""
+
+		assertNoError(t, exporter.Export(ctx, internal.Transaction{}, notFromMonzo))
+		assertNumbersEqual(t, monzo.requests, 0)
+	})
+}
+
+func TestMetadata(t *testing.T) {
+	transaction := internal.Transaction{
+		ID:         "abcdef",
+		CategoryID: "1234",
+		Answers: []internal.Answer{
+			{QuestionID: "n", Value: float64(1.5)},
+			{QuestionID: "m", Value: map[string]interface{}{"amount": float64(1250), "currency": "GBP"}},
+			{QuestionID: "s", Value: []interface{}{"a", "b"}},
+			{QuestionID: "b", Value: true},
+			{QuestionID: "d", Value: "2019-03-01"},
+		},
+	}
+
+	want := map[string]string{
+		"category_id": "1234",
+		"question_n":  "1.5",
+		"question_m":  `{"amount":1250,"currency":"GBP"}`,
+		"question_s":  `["a","b"]`,
+		"question_b":  "true",
+		"question_d":  "2019-03-01",
+	}
+
+	got := Metadata(transaction)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got metadata %v wanted %v", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+package monzo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+const testAccessToken = "test-access-token"
+const testAccountID = "acc_test"
+
+var ctx = context.Background()
+
+// newTestService returns a service over the stores, with no questions
+func newTestService(categories internal.CategoryStore, transactions internal.TransactionStore) *internal.Service {
+	return internal.NewService(categories, internal.NewInMemoryQuestionStore(nil), transactions)
+}
+
+// fakeMonzo is a stand-in for the Monzo API serving a fixed set of transactions,
+// oldest first, honouring the since/before/limit cursors like the real thing
+// failures makes the next PATCHes fail with failureStatus
+type fakeMonzo struct {
+	*httptest.Server
+	transactions  []Transaction
+	metadata      map[string]map[string]string
+	requests      int
+	failures      int
+	failureStatus int
+}
+
+func newFakeMonzo(transactions []Transaction) *fakeMonzo {
+	f := &fakeMonzo{
+		transactions:  transactions,
+		metadata:      map[string]map[string]string{},
+		failureStatus: http.StatusServiceUnavailable,
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	return f
+}
+
+func (f *fakeMonzo) serve(res http.ResponseWriter, req *http.Request) {
+	f.requests++
+
+	if req.Header.Get("Authorization") != "Bearer "+testAccessToken {
+		res.WriteHeader(http.StatusUnauthorized)
+		res.Write([]byte(`{"code": "unauthorized"}`))
+		return
+	}
+
+	switch {
+	case req.Method == http.MethodGet && req.URL.Path == "/transactions":
+		f.listTransactions(res, req)
+	case req.Method == http.MethodPatch && strings.HasPrefix(req.URL.Path, "/transactions/"):
+		f.patchTransaction(res, req, strings.TrimPrefix(req.URL.Path, "/transactions/"))
+	default:
+		res.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeMonzo) patchTransaction(res http.ResponseWriter, req *http.Request, id string) {
+	if f.failures > 0 {
+		f.failures--
+		res.WriteHeader(f.failureStatus)
+		return
+	}
+
+	if err := req.ParseForm(); err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if f.metadata[id] == nil {
+		f.metadata[id] = map[string]string{}
+	}
+	// as Monzo does, a key set to "" is removed
+	for key := range req.PostForm {
+		if strings.HasPrefix(key, "metadata[") && strings.HasSuffix(key, "]") {
+			name := key[len("metadata[") : len(key)-1]
+			if value := req.PostForm.Get(key); value != "" {
+				f.metadata[id][name] = value
+			} else {
+				delete(f.metadata[id], name)
+			}
+		}
+	}
+
+	json.NewEncoder(res).Encode(map[string]interface{}{"transaction": map[string]string{"id": id}})
+}
+
+func (f *fakeMonzo) listTransactions(res http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	if query.Get("account_id") != testAccountID {
+		res.WriteHeader(http.StatusBadRequest)
+		res.Write([]byte(`{"code": "bad_request.missing_account_id"}`))
+		return
+	}
+
+	var limit int
+	fmt.Sscan(query.Get("limit"), &limit)
+
+	since := query.Get("since")
+	before, _ := time.Parse(time.RFC3339, query.Get("before"))
+
+	page := []Transaction{}
+	started := since == ""
+	for _, t := range f.transactions {
+		if !started {
+			if sinceTime, err := time.Parse(time.RFC3339, since); err == nil {
+				started = !t.Created.Before(sinceTime)
+			} else if t.ID == since {
+				// an ID cursor is exclusive
+				started = true
+				continue
+			}
+		}
+		if !started {
+			continue
+		}
+		if !before.IsZero() && !t.Created.Before(before) {
+			break
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, t)
+	}
+
+	json.NewEncoder(res).Encode(transactionsResponse{Transactions: page})
+}
+
+// newTestTransactions returns n transactions a minute apart, oldest first
+func newTestTransactions(n int) []Transaction {
+	start := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	transactions := make([]Transaction, n)
+	for i := range transactions {
+		transactions[i] = Transaction{
+			ID:          fmt.Sprintf("tx_%05d", i),
+			Amount:      -int64(100 + i),
+			Currency:    "GBP",
+			Created:     start.Add(time.Duration(i) * time.Minute),
+			Category:    "eating_out",
+			Description: fmt.Sprintf("DESCRIPTION %d", i),
+		}
+	}
+	return transactions
+}
+
+func transactionIDs(transactions []Transaction) []string {
+	ids := make([]string, len(transactions))
+	for i, t := range transactions {
+		ids[i] = t.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func assertNumbersEqual(t *testing.T, got, want int) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %d wanted %d", got, want)
+	}
+}
+
+func assertStringsEqual(t *testing.T, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got '%s' wanted '%s'", got, want)
+	}
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package monzo
+
+import (
+	"context"
+	"errors"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+// Importer copies Monzo transactions into our own, through a Service
+// so they're categorised as it would categorise them, see internal.Service.ImportTransaction,
+// their categories coming from a CategoryMapping
+// Re-importing is idempotent, transactions are keyed on their Monzo ID
+type Importer struct {
+	client  *Client
+	service *internal.Service
+	mapping CategoryMapping
+}
+
+// ImportResult counts what an Import did
+type ImportResult struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+}
+
+// NewImporter returns an Importer pointer
+// The service needs a transaction store, and mapping may be nil to leave every imported transaction uncategorised
+func NewImporter(client *Client, service *internal.Service, mapping CategoryMapping) *Importer {
+	return &Importer{client: client, service: service, mapping: mapping}
+}
+
+// Import fetches the account's transactions between since and before (see Client.ListTransactions),
+// skipping any that have already been imported
+// They're stored for the user in ctx, see internal.WithUser, and cancelling ctx stops the import
+func (i *Importer) Import(ctx context.Context, accountID, since, before string) (ImportResult, error) {
+	var result ImportResult
+
+	transactions, err := i.client.ListTransactions(ctx, accountID, since, before)
+	if err != nil {
+		return result, err
+	}
+
+	for _, t := range transactions {
+		imported, err := i.importTransaction(ctx, t)
+		switch {
+		case err != nil:
+			return result, err
+		case imported:
+			result.Imported++
+		default:
+			result.Skipped++
+		}
+	}
+
+	return result, nil
+}
+
+// importTransaction stores the transaction, returning false if it had already been imported
+func (i *Importer) importTransaction(ctx context.Context, t Transaction) (bool, error) {
+	_, err := i.service.ImportTransaction(ctx, NewTransaction(t, i.mapping))
+	if errors.Is(err, internal.ErrConflict) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// NewTransaction converts a Monzo transaction into one of ours, ready to be imported
+// Merchant falls back to the description when Monzo has no merchant (e.g. bank transfers)
+// and the category comes from the mapping, left uncategorised if unmapped
+func NewTransaction(t Transaction, mapping CategoryMapping) internal.Transaction {
+	merchant := t.Description
+	if t.Merchant != nil && t.Merchant.Name != "" {
+		merchant = t.Merchant.Name
+	}
+
+	return internal.Transaction{
+		MonzoID:    t.ID,
+		Amount:     t.Amount,
+		Currency:   t.Currency,
+		Merchant:   merchant,
+		Timestamp:  t.Created,
+		CategoryID: mapping.CategoryFor(t.Category),
+	}
+}
@@ -0,0 +1,104 @@
+package monzo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	internal "github.com/jgillard/practising-go-tdd/internal"
+)
+
+func TestImport(t *testing.T) {
+	categories := internal.NewInMemoryCategoryStore(&internal.CategoryList{
+		Categories: []internal.Category{
+			{ID: "1234", Name: "food and drink", ParentID: ""},
+		},
+	})
+	mapping := CategoryMapping{
+		"eating_out": "1234",
+		"transport":  "does-not-exist",
+	}
+
+	monzoTransactions := newTestTransactions(3)
+	monzoTransactions[0].Merchant = &Merchant{ID: "merch_1", Name: "Pret A Manger"}
+	monzoTransactions[1].Category = "transport"
+	monzoTransactions[2].Category = "groceries"
+
+	t.Run("stores mapped transactions and skips them on re-import", func(t *testing.T) {
+		monzo := newFakeMonzo(monzoTransactions)
+		defer monzo.Close()
+
+		transactions := internal.NewInMemoryTransactionStore(nil)
+		importer := NewImporter(NewClient(monzo.URL, testAccessToken), newTestService(categories, transactions), mapping)
+
+		result, err := importer.Import(ctx, testAccountID, "", "")
+		assertNoError(t, err)
+		assertNumbersEqual(t, result.Imported, 3)
+		assertNumbersEqual(t, result.Skipped, 0)
+
+		transactionList, err := transactions.ListTransactions(ctx)
+		assertNoError(t, err)
+		stored := transactionList.Transactions
+		assertNumbersEqual(t, len(stored), 3)
+
+		assertStringsEqual(t, stored[0].MonzoID, monzoTransactions[0].ID)
+		assertStringsEqual(t, stored[0].Merchant, "Pret A Manger")
+		assertStringsEqual(t, stored[0].CategoryID, "1234")
+		assertStringsEqual(t, stored[0].Currency, "GBP")
+		if stored[0].Amount != monzoTransactions[0].Amount {
+			t.Errorf("got amount %d wanted %d", stored[0].Amount, monzoTransactions[0].Amount)
+		}
+
+		// no merchant falls back to the description, unknown categories are left uncategorised
+		assertStringsEqual(t, stored[1].Merchant, monzoTransactions[1].Description)
+		assertStringsEqual(t, stored[1].CategoryID, "")
+		assertStringsEqual(t, stored[2].CategoryID, "")
+
+		result, err = importer.Import(ctx, testAccountID, "", "")
+		assertNoError(t, err)
+		assertNumbersEqual(t, result.Imported, 0)
+		assertNumbersEqual(t, result.Skipped, 3)
+
+		transactionList, err = transactions.ListTransactions(ctx)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(transactionList.Transactions), 3)
+	})
+
+	t.Run("imports nothing when Monzo errors", func(t *testing.T) {
+		monzo := newFakeMonzo(monzoTransactions)
+		defer monzo.Close()
+
+		transactions := internal.NewInMemoryTransactionStore(nil)
+		importer := NewImporter(NewClient(monzo.URL, "wrong-token"), newTestService(categories, transactions), mapping)
+
+		_, err := importer.Import(ctx, testAccountID, "", "")
+		if err == nil {
+			t.Fatal("wanted an error but didn't get one")
+		}
+
+		transactionList, err := transactions.ListTransactions(ctx)
+		assertNoError(t, err)
+		assertNumbersEqual(t, len(transactionList.Transactions), 0)
+	})
+}
+
+func TestLoadCategoryMapping(t *testing.T) {
+	dir, err := ioutil.TempDir("", "practising-go-tdd")
+	assertNoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "mapping.json")
+	err = ioutil.WriteFile(path, []byte(`{"eating_out": "1234", "groceries": "5678"}`), 0644)
+	assertNoError(t, err)
+
+	mapping, err := LoadCategoryMapping(path)
+	assertNoError(t, err)
+	assertStringsEqual(t, mapping["eating_out"], "1234")
+	assertStringsEqual(t, mapping["groceries"], "5678")
+
+	_, err = LoadCategoryMapping(filepath.Join(dir, "missing.json"))
+	if err == nil {
+		t.Error("wanted an error for a missing file but didn't get one")
+	}
+}
@@ -0,0 +1,32 @@
+package monzo
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// CategoryMapping maps Monzo's built-in categories (e.g. "eating_out")
+// onto the IDs of our own categories
+type CategoryMapping map[string]string
+
+// LoadCategoryMapping reads a CategoryMapping from a JSON object file
+// e.g. {"eating_out": "<categoryID>", "groceries": "<categoryID>"}
+func LoadCategoryMapping(path string) (CategoryMapping, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapping CategoryMapping
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+// CategoryFor returns our category ID for one of Monzo's built-in categories, or "" if it isn't mapped
+// The category may not exist (any more), which internal.Service.ImportTransaction leaves uncategorised
+func (m CategoryMapping) CategoryFor(monzoCategory string) string {
+	return m[monzoCategory]
+}
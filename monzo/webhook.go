@@ -0,0 +1,11 @@
+package monzo
+
+// WebhookTransactionCreated is the only webhook event type Monzo sends today
+const WebhookTransactionCreated = "transaction.created"
+
+// WebhookEvent is the body Monzo POSTs to a registered webhook URL
+// Data is a transaction with the merchant already expanded
+type WebhookEvent struct {
+	Type string      `json:"type"`
+	Data Transaction `json:"data"`
+}